@@ -2,12 +2,16 @@ package bun
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"reflect"
 
 	"github.com/uptrace/bun/schema"
 )
 
+// ErrMultipleRows is returned by SelectQuery.ScanOne when the query returns more than one row.
+var ErrMultipleRows = errors.New("bun: query returned multiple rows")
+
 type (
 	Safe  = schema.Safe
 	Ident = schema.Ident
@@ -54,6 +58,22 @@ type AfterDeleteHook interface {
 	AfterDelete(ctx context.Context, query *DeleteQuery) error
 }
 
+// BeforeAppendModelHook is called for each struct of the model, including slice elements,
+// before the struct values are appended to INSERT, UPDATE, and DELETE queries,
+// for example, to set timestamps or validate the struct.
+type BeforeAppendModelHook interface {
+	BeforeAppendModel(ctx context.Context, query schema.QueryAppender) error
+}
+
+// BeforeAppendQueryHook is called once on the zero model value before the SQL
+// of a select, insert, update, or delete query with the model is built,
+// for example, to add tenant filters or default ordering to every query.
+// It is also called for the models of the has-one and belongs-to relations
+// joined in the query and for the queries that select the other relations.
+type BeforeAppendQueryHook interface {
+	BeforeAppendQuery(ctx context.Context, query schema.QueryAppender) error
+}
+
 type BeforeCreateTableHook interface {
 	BeforeCreateTable(ctx context.Context, query *CreateTableQuery) error
 }
@@ -0,0 +1,84 @@
+package bun
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// copyToConn is implemented by driver connections that support `COPY ... TO STDOUT`,
+// for example, pgdriver.Conn.
+type copyToConn interface {
+	CopyTo(ctx context.Context, w io.Writer, query string) (int64, error)
+}
+
+// readerDialect is implemented by dialects with feature.LoadData that bulk load the data
+// from a reader registered with the driver, for example, mysqldialect uses
+// `LOAD DATA LOCAL INFILE 'Reader::name'`. The data uses the COPY text format.
+type readerDialect interface {
+	RegisterReader(r io.Reader) (name string, unregister func())
+}
+
+var errCopyNotSupported = errors.New("bun: COPY is not supported by the driver")
+
+// CopyTo executes the `COPY ... TO STDOUT` query writing the data to the writer,
+// for example, `db.CopyTo(ctx, w, "COPY ? TO STDOUT WITH (FORMAT csv)", bun.Ident("users"))`.
+// Use NewCopyFrom to copy the data into a table.
+func (db *DB) CopyTo(
+	ctx context.Context, w io.Writer, query string, args ...interface{},
+) (sql.Result, error) {
+	return db.copyTo(ctx, db.DB, w, query, args)
+}
+
+func (c Conn) CopyTo(
+	ctx context.Context, w io.Writer, query string, args ...interface{},
+) (sql.Result, error) {
+	return c.db.copyTo(ctx, c.Conn, w, query, args)
+}
+
+func (db *DB) copyTo(
+	ctx context.Context, conn IConn, w io.Writer, query string, args []interface{},
+) (sql.Result, error) {
+	ctx, event := db.beforeQuery(ctx, nil, query, args)
+
+	var n int64
+	err := withDriverConn(ctx, conn, func(driverConn interface{}) error {
+		cn, ok := driverConn.(copyToConn)
+		if !ok {
+			return errCopyNotSupported
+		}
+
+		var err error
+		n, err = cn.CopyTo(ctx, w, db.format(query, args))
+		return err
+	})
+	if err != nil {
+		db.afterQuery(ctx, event, nil, err)
+		return nil, err
+	}
+
+	res := driver.RowsAffected(n)
+	db.afterQuery(ctx, event, res, nil)
+	return res, nil
+}
+
+// withDriverConn calls the fn with the driver connection that is used by the conn.
+// Transactions are not supported, because sql.Tx does not expose the connection.
+func withDriverConn(ctx context.Context, conn IConn, fn func(driverConn interface{}) error) error {
+	switch conn := conn.(type) {
+	case *sql.DB:
+		c, err := conn.Conn(ctx)
+		if err != nil {
+			return err
+		}
+		defer c.Close()
+		return c.Raw(fn)
+	case *sql.Conn:
+		return conn.Raw(fn)
+	default:
+		return fmt.Errorf("bun: COPY requires a DB or Conn, got %T", conn)
+	}
+}
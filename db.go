@@ -6,8 +6,10 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"strconv"
 	"strings"
 	"sync/atomic"
+	"time"
 
 	"github.com/uptrace/bun/dialect/feature"
 	"github.com/uptrace/bun/internal"
@@ -31,12 +33,53 @@ func WithDiscardUnknownColumns() DBOption {
 	}
 }
 
+// WithQueryComment configures a function that returns sqlcommenter key-value pairs
+// for the query context, for example, a traceparent of the current span.
+// The pairs are appended to queries built with query builders as a SQL comment.
+// Keys set with the query Comment method take precedence.
+func WithQueryComment(fn func(ctx context.Context) map[string]string) DBOption {
+	return func(db *DB) {
+		db.queryComment = fn
+	}
+}
+
+// WithLowercaseKeywords makes query builders generate lowercase SQL keywords,
+// for example, `select ... from ... where ...`. Only the keywords generated by
+// query builders are lowercased; raw SQL passed to Where, ColumnExpr, and similar
+// methods is kept as is.
+func WithLowercaseKeywords() DBOption {
+	return func(db *DB) {
+		db.fmter = db.fmter.WithLowerKeywords()
+	}
+}
+
+// WithScanLocation converts the time values scanned into struct fields to the location,
+// for example, time.UTC, regardless of the location returned by the driver.
+// Use the `location` field tag to override the location for a field.
+func WithScanLocation(loc *time.Location) DBOption {
+	return func(db *DB) {
+		db.scanLocation = loc
+	}
+}
+
+// WithRetryPolicy configures the DB to retry queries built with query builders
+// when they fail with a transient error. See RetryPolicy.
+func WithRetryPolicy(policy RetryPolicy) DBOption {
+	return func(db *DB) {
+		db.retryPolicy = &policy
+	}
+}
+
 type DB struct {
 	*sql.DB
 	dialect  schema.Dialect
 	features feature.Feature
 
-	queryHooks []QueryHook
+	queryHooks   []QueryHook
+	queryComment func(ctx context.Context) map[string]string
+	scanLocation *time.Location
+	retryPolicy  *RetryPolicy
+	stmtCache    *stmtCache
 
 	fmter schema.Formatter
 	flags internal.Flag
@@ -84,6 +127,11 @@ func (db *DB) NewValues(model interface{}) *ValuesQuery {
 	return NewValuesQuery(db, model)
 }
 
+// NewRaw returns a raw SQL query that is formatted and scanned like other queries.
+func (db *DB) NewRaw(query string, args ...interface{}) *RawQuery {
+	return NewRawQuery(db, query, args...)
+}
+
 func (db *DB) NewSelect() *SelectQuery {
 	return NewSelectQuery(db)
 }
@@ -100,6 +148,14 @@ func (db *DB) NewDelete() *DeleteQuery {
 	return NewDeleteQuery(db)
 }
 
+func (db *DB) NewMerge() *MergeQuery {
+	return NewMergeQuery(db)
+}
+
+func (db *DB) NewCopyFrom() *CopyFromQuery {
+	return NewCopyFromQuery(db)
+}
+
 func (db *DB) NewCreateTable() *CreateTableQuery {
 	return NewCreateTableQuery(db)
 }
@@ -128,6 +184,14 @@ func (db *DB) NewDropColumn() *DropColumnQuery {
 	return NewDropColumnQuery(db)
 }
 
+func (db *DB) NewRenameColumn() *RenameColumnQuery {
+	return NewRenameColumnQuery(db)
+}
+
+func (db *DB) NewModifyColumn() *ModifyColumnQuery {
+	return NewModifyColumnQuery(db)
+}
+
 func (db *DB) ResetModel(ctx context.Context, models ...interface{}) error {
 	for _, model := range models {
 		if _, err := db.NewDropTable().Model(model).IfExists().Exec(ctx); err != nil {
@@ -154,6 +218,29 @@ func (db *DB) ScanRows(ctx context.Context, rows *sql.Rows, dest ...interface{})
 	return err
 }
 
+// ScanResultSets scans multiple result sets, for example, returned by a stored procedure.
+// Each result set is scanned into the corresponding destination.
+func (db *DB) ScanResultSets(ctx context.Context, rows *sql.Rows, dests ...interface{}) error {
+	for i, dest := range dests {
+		if i > 0 && !rows.NextResultSet() {
+			if err := rows.Err(); err != nil {
+				return err
+			}
+			return fmt.Errorf("bun: got %d result sets, wanted %d", i, len(dests))
+		}
+
+		model, err := _newModel(db, dest, true)
+		if err != nil {
+			return err
+		}
+
+		if _, err := model.ScanRows(ctx, rows); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (db *DB) ScanRow(ctx context.Context, rows *sql.Rows, dest ...interface{}) error {
 	model, err := newModel(db, dest)
 	if err != nil {
@@ -176,6 +263,18 @@ func (db *DB) Table(typ reflect.Type) *schema.Table {
 	return db.dialect.Tables().Get(typ)
 }
 
+// isCustomType reports whether the type is registered with schema.Tables.RegisterType
+// and should be scanned as a single column instead of a table.
+func (db *DB) isCustomType(typ reflect.Type) bool {
+	return db.dialect.Tables().TypeScanner(typ) != nil
+}
+
+// isScalarType reports whether the slice type is scanned as a single value,
+// for example, []byte or json.RawMessage, instead of a value per row.
+func (db *DB) isScalarType(typ reflect.Type) bool {
+	return typ.Elem().Kind() == reflect.Uint8 || db.isCustomType(typ)
+}
+
 func (db *DB) RegisterModel(models ...interface{}) {
 	db.dialect.Tables().Register(models...)
 }
@@ -289,10 +388,46 @@ func (c Conn) QueryRowContext(ctx context.Context, query string, args ...interfa
 	return row
 }
 
+// Dialect returns the dialect of the DB that owns the connection.
+func (c Conn) Dialect() schema.Dialect {
+	return c.db.Dialect()
+}
+
+// BeginBunTx starts a transaction on the connection like BeginTx,
+// but returns Tx that creates queries and calls the DB query hooks.
+func (c Conn) BeginBunTx(ctx context.Context, opts *sql.TxOptions) (Tx, error) {
+	tx, err := c.Conn.BeginTx(ctx, opts)
+	if err != nil {
+		return Tx{}, err
+	}
+	return c.db.newTx(tx, opts), nil
+}
+
+// RunInTx runs the function in a transaction on the connection. If the function
+// returns an error, the transaction is rolled back. Otherwise, the transaction is committed.
+func (c Conn) RunInTx(
+	ctx context.Context, opts *sql.TxOptions, fn func(ctx context.Context, tx Tx) error,
+) error {
+	tx, err := c.BeginBunTx(ctx, opts)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	if err := fn(ctx, tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
 func (c Conn) NewValues(model interface{}) *ValuesQuery {
 	return NewValuesQuery(c.db, model).Conn(c)
 }
 
+func (c Conn) NewRaw(query string, args ...interface{}) *RawQuery {
+	return NewRawQuery(c.db, query, args...).Conn(c)
+}
+
 func (c Conn) NewSelect() *SelectQuery {
 	return NewSelectQuery(c.db).Conn(c)
 }
@@ -309,6 +444,14 @@ func (c Conn) NewDelete() *DeleteQuery {
 	return NewDeleteQuery(c.db).Conn(c)
 }
 
+func (c Conn) NewMerge() *MergeQuery {
+	return NewMergeQuery(c.db).Conn(c)
+}
+
+func (c Conn) NewCopyFrom() *CopyFromQuery {
+	return NewCopyFromQuery(c.db).Conn(c)
+}
+
 func (c Conn) NewCreateTable() *CreateTableQuery {
 	return NewCreateTableQuery(c.db).Conn(c)
 }
@@ -337,10 +480,24 @@ func (c Conn) NewDropColumn() *DropColumnQuery {
 	return NewDropColumnQuery(c.db).Conn(c)
 }
 
+func (c Conn) NewRenameColumn() *RenameColumnQuery {
+	return NewRenameColumnQuery(c.db).Conn(c)
+}
+
+func (c Conn) NewModifyColumn() *ModifyColumnQuery {
+	return NewModifyColumnQuery(c.db).Conn(c)
+}
+
 //------------------------------------------------------------------------------
 
 type Stmt struct {
+	db *DB
 	*sql.Stmt
+
+	queryApp schema.QueryAppender
+	query    string
+	// args are the args bound when the statement was prepared from a query.
+	args []interface{}
 }
 
 func (db *DB) Prepare(query string) (Stmt, error) {
@@ -352,7 +509,60 @@ func (db *DB) PrepareContext(ctx context.Context, query string) (Stmt, error) {
 	if err != nil {
 		return Stmt{}, err
 	}
-	return Stmt{Stmt: stmt}, nil
+	return Stmt{db: db, Stmt: stmt, query: query}, nil
+}
+
+// ExecContext executes the prepared query with the args and runs the query hooks.
+// Without args, it uses the args bound when the statement was prepared.
+func (stmt Stmt) ExecContext(ctx context.Context, args ...interface{}) (sql.Result, error) {
+	args = stmt.bindArgs(args)
+
+	ctx, event := stmt.db.beforeQuery(ctx, stmt.queryApp, stmt.query, args)
+	res, err := stmt.Stmt.ExecContext(ctx, args...)
+	stmt.db.afterQuery(ctx, event, res, err)
+	return res, err
+}
+
+// Scan executes the prepared query with the args and scans the result into the dest.
+// Without args, it uses the args bound when the statement was prepared.
+func (stmt Stmt) Scan(ctx context.Context, dest interface{}, args ...interface{}) error {
+	model, err := newModel(stmt.db, []interface{}{dest})
+	if err != nil {
+		return err
+	}
+
+	args = stmt.bindArgs(args)
+
+	ctx, event := stmt.db.beforeQuery(ctx, stmt.queryApp, stmt.query, args)
+	res, err := stmt.scan(ctx, model, args)
+	stmt.db.afterQuery(ctx, event, res, err)
+	return err
+}
+
+func (stmt Stmt) scan(ctx context.Context, model Model, args []interface{}) (res result, _ error) {
+	rows, err := stmt.QueryContext(ctx, args...)
+	if err != nil {
+		return res, err
+	}
+	defer rows.Close()
+
+	n, err := model.ScanRows(ctx, rows)
+	if err != nil {
+		return res, err
+	}
+
+	res.n = n
+	if n == 0 && isSingleRowModel(model) {
+		return res, sql.ErrNoRows
+	}
+	return res, nil
+}
+
+func (stmt Stmt) bindArgs(args []interface{}) []interface{} {
+	if len(args) == 0 {
+		return stmt.args
+	}
+	return args
 }
 
 //------------------------------------------------------------------------------
@@ -360,6 +570,10 @@ func (db *DB) PrepareContext(ctx context.Context, query string) (Stmt, error) {
 type Tx struct {
 	db *DB
 	*sql.Tx
+
+	isolation sql.IsolationLevel
+	// savepoints is the number of the nested RunInTx calls.
+	savepoints int
 }
 
 // RunInTx runs the function in a transaction. If the function returns an error,
@@ -379,6 +593,125 @@ func (db *DB) RunInTx(
 	return tx.Commit()
 }
 
+// RunInTxWithRetry runs the function in a transaction like RunInTx and retries
+// the whole transaction up to maxRetries times when it fails with a transient error
+// such as a serialization failure or a deadlock. The function must be safe to run
+// more than once. Transient errors are detected by the dialect.
+// On CockroachDB, the function is retried in the same transaction
+// using the cockroach_restart savepoint.
+func (db *DB) RunInTxWithRetry(
+	ctx context.Context,
+	opts *sql.TxOptions,
+	maxRetries int,
+	fn func(ctx context.Context, tx Tx) error,
+) error {
+	if db.features.Has(feature.SavepointRetry) {
+		return db.runInTxWithSavepoint(ctx, opts, maxRetries, fn)
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= maxRetries; attempt++ {
+		if attempt > 0 {
+			if err := internal.Sleep(ctx, internal.RetryBackoff(attempt-1)); err != nil {
+				return err
+			}
+		}
+
+		lastErr = db.RunInTx(ctx, opts, fn)
+		if lastErr == nil || !db.isRetryableError(lastErr) {
+			return lastErr
+		}
+	}
+	return lastErr
+}
+
+// runInTxWithSavepoint retries the function in the same transaction using
+// the CockroachDB client-side retry protocol with the cockroach_restart savepoint.
+func (db *DB) runInTxWithSavepoint(
+	ctx context.Context,
+	opts *sql.TxOptions,
+	maxRetries int,
+	fn func(ctx context.Context, tx Tx) error,
+) error {
+	tx, err := db.BeginTx(ctx, opts)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	if _, err := tx.ExecContext(ctx, "SAVEPOINT cockroach_restart"); err != nil {
+		return err
+	}
+
+	for attempt := 0; ; attempt++ {
+		err := fn(ctx, tx)
+		if err == nil {
+			_, err = tx.ExecContext(ctx, "RELEASE SAVEPOINT cockroach_restart")
+		}
+		if err == nil {
+			return tx.Commit()
+		}
+
+		if attempt >= maxRetries || !db.isRetryableError(err) {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT cockroach_restart"); err != nil {
+			return err
+		}
+	}
+}
+
+// RetryPolicy describes how queries that fail with a transient error,
+// for example, a serialization failure or a deadlock, are retried.
+// Queries that run in a transaction are not retried, because the database
+// aborts the whole transaction; use RunInTxWithRetry instead.
+// Query hooks are called for each attempt with QueryEvent.Attempt set.
+type RetryPolicy struct {
+	// MaxRetries is the maximum number of retries after the first attempt.
+	MaxRetries int
+	// Backoff returns the delay before the retry (starting from 0).
+	// Defaults to an exponential backoff with jitter.
+	Backoff func(retry int) time.Duration
+	// IsRetryableError reports whether the err is transient.
+	// Defaults to the dialect classifier.
+	IsRetryableError func(err error) bool
+}
+
+// retryQuery reports whether the failed query attempt should be retried
+// and waits for the backoff.
+func (db *DB) retryQuery(ctx context.Context, inTx bool, attempt int, err error) bool {
+	policy := db.retryPolicy
+	if policy == nil || inTx || attempt >= policy.MaxRetries {
+		return false
+	}
+
+	if policy.IsRetryableError != nil {
+		if !policy.IsRetryableError(err) {
+			return false
+		}
+	} else if !db.isRetryableError(err) {
+		return false
+	}
+
+	backoff := internal.RetryBackoff
+	if policy.Backoff != nil {
+		backoff = policy.Backoff
+	}
+	return internal.Sleep(ctx, backoff(attempt)) == nil
+}
+
+func (db *DB) isRetryableError(err error) bool {
+	if d, ok := db.dialect.(retryableErrorDialect); ok {
+		return d.IsRetryableError(err)
+	}
+	return false
+}
+
+// retryableErrorDialect is implemented by dialects that can detect transient errors.
+type retryableErrorDialect interface {
+	IsRetryableError(err error) bool
+}
+
 func (db *DB) Begin() (Tx, error) {
 	return db.BeginTx(context.Background(), nil)
 }
@@ -388,10 +721,56 @@ func (db *DB) BeginTx(ctx context.Context, opts *sql.TxOptions) (Tx, error) {
 	if err != nil {
 		return Tx{}, err
 	}
+	return db.newTx(tx, opts), nil
+}
+
+func (db *DB) newTx(tx *sql.Tx, opts *sql.TxOptions) Tx {
+	var isolation sql.IsolationLevel
+	if opts != nil {
+		isolation = opts.Isolation
+	}
+
 	return Tx{
-		db: db,
-		Tx: tx,
-	}, nil
+		db:        db,
+		Tx:        tx,
+		isolation: isolation,
+	}
+}
+
+// Dialect returns the dialect of the DB that started the transaction.
+func (tx Tx) Dialect() schema.Dialect {
+	return tx.db.Dialect()
+}
+
+// RunInTx runs the function in the current transaction so code that accepts IDB
+// can call RunInTx without knowing whether it already runs in a transaction.
+// The transaction is committed or rolled back by its owner.
+// RunInTx runs the function in a savepoint of the transaction. If the function returns
+// an error, only the changes made by the function are rolled back using
+// `ROLLBACK TO SAVEPOINT`. The options can't be changed in the transaction, so opts must be nil.
+func (tx Tx) RunInTx(
+	ctx context.Context, opts *sql.TxOptions, fn func(ctx context.Context, tx Tx) error,
+) error {
+	if opts != nil {
+		return errors.New("bun: nested RunInTx does not support TxOptions")
+	}
+
+	tx.savepoints++
+	name := "bun_sp_" + strconv.Itoa(tx.savepoints)
+
+	if _, err := tx.ExecContext(ctx, "SAVEPOINT "+name); err != nil {
+		return err
+	}
+
+	if err := fn(ctx, tx); err != nil {
+		if _, rbErr := tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT "+name); rbErr != nil {
+			return rbErr
+		}
+		return err
+	}
+
+	_, err := tx.ExecContext(ctx, "RELEASE SAVEPOINT "+name)
+	return err
 }
 
 func (tx Tx) Exec(query string, args ...interface{}) (sql.Result, error) {
@@ -437,6 +816,10 @@ func (tx Tx) NewValues(model interface{}) *ValuesQuery {
 	return NewValuesQuery(tx.db, model).Conn(tx)
 }
 
+func (tx Tx) NewRaw(query string, args ...interface{}) *RawQuery {
+	return NewRawQuery(tx.db, query, args...).Conn(tx)
+}
+
 func (tx Tx) NewSelect() *SelectQuery {
 	return NewSelectQuery(tx.db).Conn(tx)
 }
@@ -453,6 +836,10 @@ func (tx Tx) NewDelete() *DeleteQuery {
 	return NewDeleteQuery(tx.db).Conn(tx)
 }
 
+func (tx Tx) NewMerge() *MergeQuery {
+	return NewMergeQuery(tx.db).Conn(tx)
+}
+
 func (tx Tx) NewCreateTable() *CreateTableQuery {
 	return NewCreateTableQuery(tx.db).Conn(tx)
 }
@@ -481,6 +868,14 @@ func (tx Tx) NewDropColumn() *DropColumnQuery {
 	return NewDropColumnQuery(tx.db).Conn(tx)
 }
 
+func (tx Tx) NewRenameColumn() *RenameColumnQuery {
+	return NewRenameColumnQuery(tx.db).Conn(tx)
+}
+
+func (tx Tx) NewModifyColumn() *ModifyColumnQuery {
+	return NewModifyColumnQuery(tx.db).Conn(tx)
+}
+
 //------------------------------------------------------------------------------0
 
 func (db *DB) makeQueryBytes() []byte {
@@ -114,6 +114,7 @@ func (l *Fixture) load(ctx context.Context, fsys fs.FS, name string) error {
 	if err != nil {
 		return err
 	}
+	defer fh.Close()
 
 	var fixtures []fixtureData
 
@@ -0,0 +1,117 @@
+package chdialect
+
+import (
+	"reflect"
+	"time"
+
+	"github.com/uptrace/bun/dialect"
+	"github.com/uptrace/bun/extra/bunjson"
+	"github.com/uptrace/bun/schema"
+)
+
+var bytesType = reflect.TypeOf((*[]byte)(nil)).Elem()
+
+func appender(typ reflect.Type) schema.AppenderFunc {
+	switch typ {
+	case timeType:
+		return appendTimeValue
+	case bytesType:
+		return appendBytesValue
+	}
+	return schema.Appender(typ, customAppender)
+}
+
+func customAppender(typ reflect.Type) schema.AppenderFunc {
+	switch typ.Kind() {
+	case reflect.String:
+		return appendStringValue
+	case reflect.Slice, reflect.Array:
+		if typ.Elem().Kind() == reflect.Uint8 {
+			return appendBytesValue
+		}
+		return arrayAppender(typ)
+	case reflect.Map, reflect.Struct:
+		return appendJSONValue
+	}
+	return nil
+}
+
+// arrayAppender appends Go slices as ClickHouse arrays, for example, `[1, 2, 3]`.
+func arrayAppender(typ reflect.Type) schema.AppenderFunc {
+	elemAppender := appender(typ.Elem())
+
+	return func(fmter schema.Formatter, b []byte, v reflect.Value) []byte {
+		b = append(b, '[')
+		for i := 0; i < v.Len(); i++ {
+			if i > 0 {
+				b = append(b, ", "...)
+			}
+			b = elemAppender(fmter, b, v.Index(i))
+		}
+		b = append(b, ']')
+		return b
+	}
+}
+
+func appendStringValue(fmter schema.Formatter, b []byte, v reflect.Value) []byte {
+	return appendString(b, v.String())
+}
+
+func appendBytesValue(fmter schema.Formatter, b []byte, v reflect.Value) []byte {
+	if v.Kind() == reflect.Array {
+		bytes := make([]byte, v.Len())
+		reflect.Copy(reflect.ValueOf(bytes), v)
+		return appendBytes(b, bytes)
+	}
+	return appendBytes(b, v.Bytes())
+}
+
+func appendTimeValue(fmter schema.Formatter, b []byte, v reflect.Value) []byte {
+	return appendTime(b, v.Interface().(time.Time))
+}
+
+func appendJSONValue(fmter schema.Formatter, b []byte, v reflect.Value) []byte {
+	bb, err := bunjson.Marshal(v.Interface())
+	if err != nil {
+		return dialect.AppendError(b, err)
+	}
+
+	if len(bb) > 0 && bb[len(bb)-1] == '\n' {
+		bb = bb[:len(bb)-1]
+	}
+
+	return appendBytes(b, bb)
+}
+
+func appendTime(b []byte, tm time.Time) []byte {
+	b = append(b, '\'')
+	b = tm.UTC().AppendFormat(b, "2006-01-02 15:04:05.999999")
+	b = append(b, '\'')
+	return b
+}
+
+func appendBytes(b []byte, bytes []byte) []byte {
+	if bytes == nil {
+		return dialect.AppendNull(b)
+	}
+	return appendString(b, string(bytes))
+}
+
+// appendString quotes the string escaping backslashes that ClickHouse treats as escape sequences.
+func appendString(b []byte, s string) []byte {
+	b = append(b, '\'')
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '\'':
+			b = append(b, `\'`...)
+		case '\\':
+			b = append(b, `\\`...)
+		case '\000':
+			b = append(b, `\0`...)
+		default:
+			b = append(b, c)
+		}
+	}
+	b = append(b, '\'')
+	return b
+}
@@ -0,0 +1,127 @@
+// Package chdialect implements the ClickHouse dialect.
+//
+// ClickHouse does not support transactions, RETURNING, or foreign keys,
+// so use the DB directly instead of RunInTx. Tables are created with
+// CreateTableQuery.Engine, PartitionBy, and OrderBy, for example,
+// `Engine("MergeTree()").OrderBy("(id)")`.
+package chdialect
+
+import (
+	"database/sql"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/uptrace/bun/dialect"
+	"github.com/uptrace/bun/dialect/feature"
+	"github.com/uptrace/bun/dialect/sqltype"
+	"github.com/uptrace/bun/schema"
+)
+
+type Dialect struct {
+	tables   *schema.Tables
+	features feature.Feature
+
+	appenderMap sync.Map
+	scannerMap  sync.Map
+}
+
+func New() *Dialect {
+	d := new(Dialect)
+	d.tables = schema.NewTables(d)
+	// DEFAULT keeps the same columns for every row of a batch insert.
+	d.features = feature.DefaultPlaceholder |
+		feature.HavingAlias |
+		feature.TableTruncate |
+		feature.WindowFunc |
+		feature.TableEngine |
+		feature.TableOrderBy |
+		feature.TablePartition |
+		feature.ILike |
+		feature.RenameColumn |
+		feature.OffsetFetch
+	return d
+}
+
+func (d *Dialect) Init(*sql.DB) {}
+
+func (d *Dialect) Name() dialect.Name {
+	return dialect.ClickHouse
+}
+
+func (d *Dialect) Features() feature.Feature {
+	return d.features
+}
+
+// IsRetryableError reports whether the err is caused by too many parts
+// that are merged in the background.
+func (d *Dialect) IsRetryableError(err error) bool {
+	return strings.Contains(err.Error(), "TOO_MANY_PARTS")
+}
+
+func (d *Dialect) Tables() *schema.Tables {
+	return d.tables
+}
+
+func (d *Dialect) OnTable(table *schema.Table) {
+	for _, field := range table.FieldMap {
+		field.DiscoveredSQLType = sqlType(field)
+	}
+}
+
+func (d *Dialect) IdentQuote() byte {
+	return '"'
+}
+
+func (d *Dialect) Append(fmter schema.Formatter, b []byte, v interface{}) []byte {
+	switch v := v.(type) {
+	case string:
+		return appendString(b, v)
+	case []byte:
+		return appendBytes(b, v)
+	case time.Time:
+		return appendTime(b, v)
+	default:
+		return schema.Append(fmter, b, v, customAppender)
+	}
+}
+
+func (d *Dialect) Appender(typ reflect.Type) schema.AppenderFunc {
+	if v, ok := d.appenderMap.Load(typ); ok {
+		return v.(schema.AppenderFunc)
+	}
+
+	fn := appender(typ)
+
+	if v, ok := d.appenderMap.LoadOrStore(typ, fn); ok {
+		return v.(schema.AppenderFunc)
+	}
+	return fn
+}
+
+func (d *Dialect) FieldAppender(field *schema.Field) schema.AppenderFunc {
+	if field.Tag.HasOption("json") {
+		return appendJSONValue
+	}
+
+	switch strings.ToUpper(field.UserSQLType) {
+	case sqltype.JSON:
+		return appendJSONValue
+	}
+
+	return schema.FieldAppender(d, field)
+}
+
+func (d *Dialect) Scanner(typ reflect.Type) schema.ScannerFunc {
+	if v, ok := d.scannerMap.Load(typ); ok {
+		return v.(schema.ScannerFunc)
+	}
+
+	fn := scanner(typ)
+
+	if v, ok := d.scannerMap.LoadOrStore(typ, fn); ok {
+		return v.(schema.ScannerFunc)
+	}
+	return fn
+}
@@ -0,0 +1,107 @@
+package chdialect
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/schema"
+)
+
+func TestSQLType(t *testing.T) {
+	type Model struct {
+		ID        uint64
+		Name      string `bun:",lowcardinality"`
+		Nickname  *string
+		Tags      []string
+		Scores    []int32
+		Attrs     map[string]interface{} `bun:",json"`
+		CreatedAt time.Time
+	}
+
+	table := New().Tables().Get(reflect.TypeOf((*Model)(nil)).Elem())
+
+	tests := map[string]string{
+		"id":         "UInt64",
+		"name":       "LowCardinality(String)",
+		"nickname":   "Nullable(String)",
+		"tags":       "Array(String)",
+		"scores":     "Array(Int32)",
+		"attrs":      "String",
+		"created_at": "DateTime64(6)",
+	}
+	for column, typ := range tests {
+		field := table.FieldMap[column]
+		if field.CreateTableSQLType != typ {
+			t.Fatalf("%s: got %s, wanted %s", column, field.CreateTableSQLType, typ)
+		}
+	}
+}
+
+func TestAppend(t *testing.T) {
+	fmter := schema.NewFormatter(New())
+
+	tests := []struct {
+		src   interface{}
+		query string
+	}{
+		{`it's a \ test`, `'it\'s a \\ test'`},
+		{[]string{"a", "b'c"}, `['a', 'b\'c']`},
+		{[]int64{1, 2}, `[1, 2]`},
+		{[]int64{}, `[]`},
+		{time.Date(2021, 7, 1, 12, 30, 0, 0, time.UTC), `'2021-07-01 12:30:00'`},
+		{map[string]string{"a": `"b"`}, `'{"a":"\\"b\\""}'`},
+	}
+
+	for i, test := range tests {
+		b := fmter.Dialect().Append(fmter, nil, test.src)
+		if string(b) != test.query {
+			t.Fatalf("test #%d: got %s, wanted %s", i, b, test.query)
+		}
+	}
+}
+
+func TestArrayScanner(t *testing.T) {
+	var dest []int64
+	v := reflect.ValueOf(&dest).Elem()
+
+	if err := scanner(v.Type())(v, []int32{1, 2, 3}); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(dest, []int64{1, 2, 3}) {
+		t.Fatalf("got %v, wanted [1 2 3]", dest)
+	}
+
+	if err := scanner(v.Type())(v, []byte("[4,5]")); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(dest, []int64{4, 5}) {
+		t.Fatalf("got %v, wanted [4 5]", dest)
+	}
+}
+
+func TestCreateTable(t *testing.T) {
+	type Model struct {
+		ID  uint64
+		Str string
+	}
+
+	db := bun.NewDB(nil, New())
+	q := db.NewCreateTable().
+		Model((*Model)(nil)).
+		Engine("MergeTree()").
+		PartitionBy("str").
+		OrderBy("(id)")
+
+	b, err := q.AppendQuery(db.Formatter(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wanted := `CREATE TABLE "models" ("id" UInt64 NOT NULL, "str" String, PRIMARY KEY ("id")) ` +
+		`ENGINE = MergeTree() PARTITION BY str ORDER BY (id)`
+	if string(b) != wanted {
+		t.Fatalf("got %s, wanted %s", b, wanted)
+	}
+}
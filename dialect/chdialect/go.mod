@@ -0,0 +1,7 @@
+module github.com/uptrace/bun/dialect/chdialect
+
+go 1.16
+
+replace github.com/uptrace/bun => ../..
+
+require github.com/uptrace/bun v0.4.0
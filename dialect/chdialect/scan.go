@@ -0,0 +1,89 @@
+package chdialect
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/uptrace/bun/schema"
+)
+
+func scanner(typ reflect.Type) schema.ScannerFunc {
+	switch typ.Kind() {
+	case reflect.Interface:
+		return scanInterface
+	case reflect.Slice:
+		if typ.Elem().Kind() != reflect.Uint8 {
+			return arrayScanner(typ)
+		}
+	}
+	return schema.Scanner(typ)
+}
+
+func scanInterface(dest reflect.Value, src interface{}) error {
+	if src == nil {
+		dest.Set(reflect.Zero(dest.Type()))
+		return nil
+	}
+
+	// Only scan in place when the interface holds a pointer,
+	// because values stored in an interface are not addressable.
+	if dest.IsNil() || dest.Elem().Kind() != reflect.Ptr {
+		dest.Set(reflect.ValueOf(src))
+		return nil
+	}
+
+	dest = dest.Elem()
+	if fn := scanner(dest.Type()); fn != nil {
+		return fn(dest, src)
+	}
+	return fmt.Errorf("bun: can't scan %#v into %s", src, dest.Type())
+}
+
+// arrayScanner scans ClickHouse arrays that the driver returns as Go slices,
+// for example, []int32 into []int64.
+func arrayScanner(typ reflect.Type) schema.ScannerFunc {
+	elemType := typ.Elem()
+	elemScanner := scanner(elemType)
+
+	return func(dest reflect.Value, src interface{}) error {
+		switch src := src.(type) {
+		case nil:
+			dest.Set(reflect.Zero(dest.Type()))
+			return nil
+		case []byte:
+			return json.Unmarshal(src, dest.Addr().Interface())
+		case string:
+			return json.Unmarshal([]byte(src), dest.Addr().Interface())
+		}
+
+		srcv := reflect.ValueOf(src)
+		if srcv.Kind() != reflect.Slice && srcv.Kind() != reflect.Array {
+			return fmt.Errorf("bun: can't scan %#v into %s", src, dest.Type())
+		}
+
+		slice := reflect.MakeSlice(dest.Type(), srcv.Len(), srcv.Len())
+		for i := 0; i < srcv.Len(); i++ {
+			elem := srcv.Index(i)
+			if isNumber(elem.Kind()) && isNumber(elemType.Kind()) {
+				slice.Index(i).Set(elem.Convert(elemType))
+				continue
+			}
+			if err := elemScanner(slice.Index(i), elem.Interface()); err != nil {
+				return err
+			}
+		}
+		dest.Set(slice)
+		return nil
+	}
+}
+
+func isNumber(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
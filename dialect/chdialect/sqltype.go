@@ -0,0 +1,97 @@
+package chdialect
+
+import (
+	"reflect"
+	"time"
+
+	"github.com/uptrace/bun/dialect/sqltype"
+	"github.com/uptrace/bun/schema"
+)
+
+const (
+	chTypeString   = "String"
+	chTypeDateTime = "DateTime64(6)"
+)
+
+var timeType = reflect.TypeOf((*time.Time)(nil)).Elem()
+
+func sqlType(field *schema.Field) string {
+	var typ string
+
+	switch field.DiscoveredSQLType {
+	case sqltype.JSON, sqltype.Blob:
+		typ = chTypeString
+	default:
+		typ = goSQLType(field.IndirectType, field.DiscoveredSQLType)
+	}
+
+	if field.StructField.Type.Kind() == reflect.Ptr && !field.NotNull {
+		typ = "Nullable(" + typ + ")"
+	}
+	if field.Tag.HasOption("lowcardinality") {
+		typ = "LowCardinality(" + typ + ")"
+	}
+	return typ
+}
+
+// goSQLType returns the ClickHouse type for the Go type, for example,
+// Int32 for int32 and Array(String) for []string.
+func goSQLType(typ reflect.Type, discovered string) string {
+	if typ == timeType {
+		return chTypeDateTime
+	}
+
+	switch typ.Kind() {
+	case reflect.Bool:
+		return "Bool"
+	case reflect.Int8:
+		return "Int8"
+	case reflect.Int16:
+		return "Int16"
+	case reflect.Int32:
+		return "Int32"
+	case reflect.Int, reflect.Int64:
+		return "Int64"
+	case reflect.Uint8:
+		return "UInt8"
+	case reflect.Uint16:
+		return "UInt16"
+	case reflect.Uint32:
+		return "UInt32"
+	case reflect.Uint, reflect.Uint64, reflect.Uintptr:
+		return "UInt64"
+	case reflect.Float32:
+		return "Float32"
+	case reflect.Float64:
+		return "Float64"
+	case reflect.String:
+		return chTypeString
+	case reflect.Ptr:
+		return goSQLType(typ.Elem(), schema.DiscoverSQLType(typ.Elem()))
+	case reflect.Slice, reflect.Array:
+		elem := typ.Elem()
+		if elem.Kind() == reflect.Uint8 {
+			return chTypeString
+		}
+		return "Array(" + goSQLType(elem, schema.DiscoverSQLType(elem)) + ")"
+	}
+
+	// sql.NullString and friends.
+	switch discovered {
+	case sqltype.Boolean:
+		return "Bool"
+	case sqltype.SmallInt:
+		return "Int16"
+	case sqltype.Integer:
+		return "Int32"
+	case sqltype.BigInt:
+		return "Int64"
+	case sqltype.Real:
+		return "Float32"
+	case sqltype.DoublePrecision:
+		return "Float64"
+	case sqltype.Timestamp:
+		return chTypeDateTime
+	}
+	return chTypeString
+}
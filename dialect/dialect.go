@@ -12,6 +12,8 @@ func (n Name) String() string {
 		return "mysql5"
 	case MySQL8:
 		return "mysql8"
+	case ClickHouse:
+		return "clickhouse"
 	default:
 		return "invalid"
 	}
@@ -23,4 +25,5 @@ const (
 	SQLite
 	MySQL5
 	MySQL8
+	ClickHouse
 )
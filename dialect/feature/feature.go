@@ -19,4 +19,32 @@ const (
 	TableIdentity
 	TableTruncate
 	OnDuplicateKey
+	Merge
+	HavingAlias
+	FromDual
+	CopyFrom
+	DollarPlaceholder
+	LockWait
+	InsertDefaultValues
+	RandFunc
+	WindowFunc
+	AlterColumnType
+	ModifyColumn
+	OffsetFetch
+	SelectTop
+	AsOfSystemTime
+	ReturningNothing
+	SavepointRetry
+	DeleteOrderLimit
+	CTID
+	TableEngine
+	TableOrderBy
+	TablePartition
+	TableSpace
+	LoadData
+	ILike
+	NullSafeEqual
+	TableSpaceFirst
+	RenameColumn
+	DeleteUsing
 )
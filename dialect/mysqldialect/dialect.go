@@ -2,6 +2,8 @@ package mysqldialect
 
 import (
 	"database/sql"
+	"errors"
+	"io"
 	"log"
 	"reflect"
 	"strings"
@@ -26,9 +28,14 @@ type Dialect struct {
 
 	appenderMap sync.Map
 	scannerMap  sync.Map
+
+	registerReader   func(name string, handler func() io.Reader)
+	deregisterReader func(name string)
 }
 
-func New() *Dialect {
+type Option func(d *Dialect)
+
+func New(opts ...Option) *Dialect {
 	d := new(Dialect)
 	d.name = dialect.MySQL5
 	d.tables = schema.NewTables(d)
@@ -37,7 +44,20 @@ func New() *Dialect {
 		feature.UpdateMultiTable |
 		feature.ValuesRow |
 		feature.TableTruncate |
-		feature.OnDuplicateKey
+		feature.OnDuplicateKey |
+		feature.HavingAlias |
+		feature.FromDual |
+		feature.RandFunc |
+		feature.ModifyColumn |
+		feature.DeleteOrderLimit |
+		feature.TableEngine |
+		feature.TablePartition |
+		feature.TableSpace |
+		feature.NullSafeEqual |
+		feature.TableSpaceFirst
+	for _, opt := range opts {
+		opt(d)
+	}
 	return d
 }
 
@@ -48,10 +68,14 @@ func (d *Dialect) Init(db *sql.DB) {
 		return
 	}
 
+	if strings.Contains(version, "MariaDB") {
+		d.features |= feature.LockWait
+	}
+
 	version = semver.MajorMinor("v" + cleanupVersion(version))
 	if semver.Compare(version, "v8.0") >= 0 {
 		d.name = dialect.MySQL8
-		d.features |= feature.DeleteTableAlias
+		d.features |= feature.DeleteTableAlias | feature.WindowFunc | feature.RenameColumn
 	}
 }
 
@@ -70,6 +94,19 @@ func (d *Dialect) Features() feature.Feature {
 	return d.features
 }
 
+// IsRetryableError reports whether the err is a deadlock or a lock wait timeout.
+func (d *Dialect) IsRetryableError(err error) bool {
+	for ; err != nil; err = errors.Unwrap(err) {
+		// go-sql-driver/mysql formats errors as "Error 1213: ..." or "Error 1213 (40001): ...".
+		msg := err.Error()
+		if strings.HasPrefix(msg, "Error 1213") || // ER_LOCK_DEADLOCK
+			strings.HasPrefix(msg, "Error 1205") { // ER_LOCK_WAIT_TIMEOUT
+			return true
+		}
+	}
+	return false
+}
+
 func (d *Dialect) Tables() *schema.Tables {
 	return d.tables
 }
@@ -107,6 +144,10 @@ func (d *Dialect) Appender(typ reflect.Type) schema.AppenderFunc {
 }
 
 func (d *Dialect) FieldAppender(field *schema.Field) schema.AppenderFunc {
+	if field.Tag.HasOption("json") {
+		return appendJSONValue
+	}
+
 	switch strings.ToUpper(field.UserSQLType) {
 	case sqltype.JSON:
 		return appendJSONValue
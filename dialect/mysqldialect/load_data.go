@@ -0,0 +1,39 @@
+package mysqldialect
+
+import (
+	"io"
+	"strconv"
+	"sync/atomic"
+
+	"github.com/uptrace/bun/dialect/feature"
+)
+
+var readerSeq uint64
+
+// WithLoadData makes InsertQuery.Copy bulk insert slices with
+// `LOAD DATA LOCAL INFILE 'Reader::name'` instead of INSERT. The register and deregister
+// funcs register the reader with the driver, for example, mysql.RegisterReaderHandler
+// and mysql.DeregisterReaderHandler from github.com/go-sql-driver/mysql.
+// The server must allow it with `local_infile=ON`.
+func WithLoadData(
+	register func(name string, handler func() io.Reader),
+	deregister func(name string),
+) Option {
+	return func(d *Dialect) {
+		d.registerReader = register
+		d.deregisterReader = deregister
+		d.features |= feature.LoadData
+	}
+}
+
+// RegisterReader registers the reader with the driver using the funcs passed
+// to WithLoadData and returns the name to load the data from.
+func (d *Dialect) RegisterReader(r io.Reader) (string, func()) {
+	name := "bun" + strconv.FormatUint(atomic.AddUint64(&readerSeq, 1), 10)
+	d.registerReader(name, func() io.Reader {
+		return r
+	})
+	return name, func() {
+		d.deregisterReader(name)
+	}
+}
@@ -15,7 +15,14 @@ func scanner(typ reflect.Type) schema.ScannerFunc {
 }
 
 func scanInterface(dest reflect.Value, src interface{}) error {
-	if dest.IsNil() {
+	if src == nil {
+		dest.Set(reflect.Zero(dest.Type()))
+		return nil
+	}
+
+	// Only scan in place when the interface holds a pointer,
+	// because values stored in an interface are not addressable.
+	if dest.IsNil() || dest.Elem().Kind() != reflect.Ptr {
 		dest.Set(reflect.ValueOf(src))
 		return nil
 	}
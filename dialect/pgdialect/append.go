@@ -75,9 +75,28 @@ func arrayElemAppender(typ reflect.Type) schema.AppenderFunc {
 	if typ.Implements(driverValuerType) {
 		return arrayAppendDriverValue
 	}
+	if isArrayType(typ) {
+		return subArrayAppender(typ)
+	}
 	return schema.Appender(typ, customAppender)
 }
 
+// subArrayAppender appends a nested array of a multi-dimensional array,
+// for example, `{1,2}` in `'{{1,2},{3,4}}'`.
+func subArrayAppender(typ reflect.Type) schema.AppenderFunc {
+	appendArray := arrayAppender(typ)
+	return func(fmter schema.Formatter, b []byte, v reflect.Value) []byte {
+		start := len(b)
+		b = appendArray(fmter, b, v)
+		if len(b)-start >= 2 && b[start] == '\'' {
+			// Strip quotes.
+			copy(b[start:], b[start+1:len(b)-1])
+			b = b[:len(b)-2]
+		}
+		return b
+	}
+}
+
 func arrayAppendStringValue(fmter schema.Formatter, b []byte, v reflect.Value) []byte {
 	return arrayAppendString(b, v.String())
 }
@@ -92,6 +111,14 @@ func arrayAppendDriverValue(fmter schema.Formatter, b []byte, v reflect.Value) [
 
 //------------------------------------------------------------------------------
 
+func isArrayType(typ reflect.Type) bool {
+	switch typ.Kind() {
+	case reflect.Slice, reflect.Array:
+		return typ.Elem().Kind() != reflect.Uint8
+	}
+	return false
+}
+
 func arrayAppender(typ reflect.Type) schema.AppenderFunc {
 	kind := typ.Kind()
 	if kind == reflect.Ptr {
@@ -18,9 +18,12 @@ type ArrayValue struct {
 // Array accepts a slice and returns a wrapper for working with PostgreSQL
 // array data type.
 //
-// For struct fields you can use array tag:
+// For struct fields you can use array tag or an array SQL type:
 //
 //    Emails  []string `bun:",array"`
+//    Matrix  [][]int64 `bun:"type:bigint[][]"`
+//
+// Multi-dimensional arrays are supported using nested slices.
 func Array(vi interface{}) *ArrayValue {
 	v := reflect.ValueOf(vi)
 	if !v.IsValid() {
@@ -38,6 +38,17 @@ func (p *arrayParser) NextElem() ([]byte, error) {
 	switch c {
 	case '}':
 		return nil, io.EOF
+	case '{':
+		b, err := p.readSubarray()
+		if err != nil {
+			return nil, err
+		}
+
+		if p.peek() == ',' {
+			p.skipNext()
+		}
+
+		return b, nil
 	case '"':
 		b, err := p.readSubstring()
 		if err != nil {
@@ -117,6 +128,32 @@ func (p *arrayParser) readSubstring() ([]byte, error) {
 	return p.buf, nil
 }
 
+// readSubarray reads a nested array of a multi-dimensional array including the braces.
+func (p *arrayParser) readSubarray() ([]byte, error) {
+	start := p.i - 1
+	depth := 1
+	quoted := false
+	for depth > 0 {
+		c, err := p.readByte()
+		if err != nil {
+			return nil, fmt.Errorf("bun: can't parse array: %q", p.b)
+		}
+
+		switch {
+		case quoted && c == '\\':
+			p.skipNext()
+		case c == '"':
+			quoted = !quoted
+		case quoted:
+		case c == '{':
+			depth++
+		case c == '}':
+			depth--
+		}
+	}
+	return p.b[start:p.i], nil
+}
+
 func (p *arrayParser) valid() bool {
 	return p.i < len(p.b)
 }
@@ -21,6 +21,9 @@ func TestArrayParser(t *testing.T) {
 		{"{1,NULL}", []string{"1", ""}},
 		{`{"1","2"}`, []string{"1", "2"}},
 		{`{"{1}","{2}"}`, []string{"{1}", "{2}"}},
+		{`{{1,2},{3,4}}`, []string{"{1,2}", "{3,4}"}},
+		{`{{"a}","b"},{NULL}}`, []string{`{"a}","b"}`, "{NULL}"}},
+		{`{{{1},{2}},{{3},{4}}}`, []string{"{{1},{2}}", "{{3},{4}}"}},
 	}
 
 	for testi, test := range tests {
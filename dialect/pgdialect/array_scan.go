@@ -40,6 +40,9 @@ func arrayScanner(typ reflect.Type) schema.ScannerFunc {
 	}
 
 	scanElem := schema.Scanner(elemType)
+	if isArrayType(elemType) {
+		scanElem = subArrayScanner(elemType)
+	}
 	return func(dest reflect.Value, src interface{}) error {
 		dest = reflect.Indirect(dest)
 		if !dest.CanSet() {
@@ -89,6 +92,17 @@ func arrayScanner(typ reflect.Type) schema.ScannerFunc {
 	}
 }
 
+// subArrayScanner scans a nested array of a multi-dimensional array.
+func subArrayScanner(typ reflect.Type) schema.ScannerFunc {
+	scanArray := arrayScanner(typ)
+	return func(dest reflect.Value, src interface{}) error {
+		if b, ok := src.([]byte); ok && b == nil {
+			return scanArray(dest, nil)
+		}
+		return scanArray(dest, src)
+	}
+}
+
 func scanStringSliceValue(dest reflect.Value, src interface{}) error {
 	dest = reflect.Indirect(dest)
 	if !dest.CanSet() {
@@ -253,7 +267,7 @@ func scanFloat64SliceValue(dest reflect.Value, src interface{}) error {
 }
 
 func scanFloat64Slice(src interface{}) ([]float64, error) {
-	if src == -1 {
+	if src == nil {
 		return nil, nil
 	}
 
@@ -0,0 +1,41 @@
+package pgdialect
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/uptrace/bun/schema"
+)
+
+func TestMultiDimArray(t *testing.T) {
+	fmter := schema.NewFormatter(New())
+
+	tests := []struct {
+		src   interface{}
+		query string
+	}{
+		{[][]int64{{1, 2}, {3, 4}}, `'{{1,2},{3,4}}'`},
+		{[][]string{{"a", "b"}, {"c", "d"}}, `'{{"a","b"},{"c","d"}}'`},
+		{[][][]int{{{1}, {2}}, {{3}, {4}}}, `'{{{1},{2}},{{3},{4}}}'`},
+		{[][]float64{}, `'{}'`},
+	}
+
+	for i, test := range tests {
+		b, err := Array(test.src).AppendQuery(fmter, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != test.query {
+			t.Fatalf("test #%d: got %s, wanted %s", i, b, test.query)
+		}
+
+		dest := reflect.New(reflect.TypeOf(test.src))
+		src := b[1 : len(b)-1] // strip quotes
+		if err := Array(dest.Interface()).Scan(src); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(dest.Elem().Interface(), test.src) {
+			t.Fatalf("test #%d: got %v, wanted %v", i, dest.Elem().Interface(), test.src)
+		}
+	}
+}
@@ -2,8 +2,11 @@ package pgdialect
 
 import (
 	"database/sql"
+	"errors"
+	"log"
 	"reflect"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -19,9 +22,31 @@ type Dialect struct {
 
 	appenderMap sync.Map
 	scannerMap  sync.Map
+
+	cockroachDB bool
+}
+
+type Option func(d *Dialect)
+
+// WithCockroachDB enables the CockroachDB features: AS OF SYSTEM TIME, RETURNING NOTHING,
+// and savepoint retries. Init detects CockroachDB using `SELECT version()`,
+// so the option is only needed to skip the detection.
+func WithCockroachDB() Option {
+	return func(d *Dialect) {
+		d.cockroachDB = true
+		d.enableCockroachDB()
+	}
+}
+
+func (d *Dialect) enableCockroachDB() {
+	d.features |= feature.AsOfSystemTime |
+		feature.ReturningNothing |
+		feature.SavepointRetry |
+		feature.DeleteOrderLimit
+	d.features &^= feature.Merge | feature.CTID
 }
 
-func New() *Dialect {
+func New(opts ...Option) *Dialect {
 	d := new(Dialect)
 	d.tables = schema.NewTables(d)
 	d.features = feature.Returning |
@@ -31,11 +56,64 @@ func New() *Dialect {
 		feature.DeleteTableAlias |
 		feature.TableCascade |
 		feature.TableIdentity |
-		feature.TableTruncate
+		feature.TableTruncate |
+		feature.Merge |
+		feature.CopyFrom |
+		feature.DollarPlaceholder |
+		feature.InsertDefaultValues |
+		feature.WindowFunc |
+		feature.AlterColumnType |
+		feature.CTID |
+		feature.TablePartition |
+		feature.TableSpace |
+		feature.ILike |
+		feature.RenameColumn |
+		feature.DeleteUsing |
+		feature.OffsetFetch
+
+	for _, opt := range opts {
+		opt(d)
+	}
+
 	return d
 }
 
-func (d *Dialect) Init(*sql.DB) {}
+func (d *Dialect) Init(db *sql.DB) {
+	if db == nil || d.cockroachDB {
+		return
+	}
+
+	var version string
+	if err := db.QueryRow("SELECT version()").Scan(&version); err != nil {
+		log.Printf("can't discover Postgres version: %s", err)
+		return
+	}
+
+	if strings.Contains(version, "CockroachDB") {
+		d.cockroachDB = true
+		d.enableCockroachDB()
+		return
+	}
+
+	// MERGE requires PostgreSQL 15.
+	if major, ok := parseMajorVersion(version); ok && major < 15 {
+		d.features &^= feature.Merge
+	}
+}
+
+// parseMajorVersion parses the major version from the version string,
+// for example, `PostgreSQL 14.5 on x86_64-pc-linux-gnu, ...`.
+func parseMajorVersion(version string) (int, bool) {
+	version = strings.TrimPrefix(version, "PostgreSQL ")
+	if i := strings.IndexAny(version, ". "); i >= 0 {
+		version = version[:i]
+	}
+	major, err := strconv.Atoi(version)
+	if err != nil {
+		return 0, false
+	}
+	return major, true
+}
 
 func (d *Dialect) Name() dialect.Name {
 	return dialect.PG
@@ -45,6 +123,30 @@ func (d *Dialect) Features() feature.Feature {
 	return d.features
 }
 
+// IsRetryableError reports whether the err is a serialization failure or a deadlock.
+func (d *Dialect) IsRetryableError(err error) bool {
+	var code string
+
+	// pgx and lib/pq errors.
+	var sqlState interface{ SQLState() string }
+	// pgdriver errors.
+	var fielder interface{ Field(byte) string }
+
+	switch {
+	case errors.As(err, &sqlState):
+		code = sqlState.SQLState()
+	case errors.As(err, &fielder):
+		code = fielder.Field('C')
+	}
+
+	switch code {
+	case "40001", // serialization_failure
+		"40P01": // deadlock_detected
+		return true
+	}
+	return false
+}
+
 func (d *Dialect) Tables() *schema.Tables {
 	return d.tables
 }
@@ -69,10 +171,15 @@ func (d *Dialect) onField(field *schema.Field) {
 		}
 	}
 
-	if field.Tag.HasOption("array") {
+	if field.Tag.HasOption("array") || strings.HasSuffix(field.UserSQLType, "[]") {
 		field.Append = arrayAppender(field.IndirectType)
 		field.Scan = arrayScanner(field.IndirectType)
 	}
+
+	if field.Tag.HasOption("hstore") || field.UserSQLType == "hstore" {
+		field.Append = hstoreAppender(field.IndirectType)
+		field.Scan = hstoreScanner(field.IndirectType)
+	}
 }
 
 func (d *Dialect) IdentQuote() byte {
@@ -0,0 +1,139 @@
+package pgdialect
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/feature"
+)
+
+func TestWithCockroachDB(t *testing.T) {
+	d := New()
+	if d.Features().Has(feature.AsOfSystemTime) {
+		t.Fatal("Postgres must not have AS OF SYSTEM TIME")
+	}
+
+	d = New(WithCockroachDB())
+	if !d.Features().Has(feature.AsOfSystemTime | feature.ReturningNothing | feature.SavepointRetry) {
+		t.Fatal("CockroachDB features are not enabled")
+	}
+	if d.Features().Has(feature.Merge) || d.Features().Has(feature.CTID) {
+		t.Fatal("CockroachDB does not support MERGE and ctid")
+	}
+}
+
+func init() {
+	sql.Register("pgdialect-version", versionDriver{})
+}
+
+func initDialect(t *testing.T, version string) *Dialect {
+	db, err := sql.Open("pgdialect-version", version)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	d := New()
+	d.Init(db)
+	return d
+}
+
+func TestInitCockroachDB(t *testing.T) {
+	d := initDialect(t,
+		"CockroachDB CCL v21.1.7 (x86_64-unknown-linux-gnu, built 2021/08/09 17:55:28, go1.15.14)")
+	if !d.Features().Has(feature.AsOfSystemTime) {
+		t.Fatal("CockroachDB is not detected")
+	}
+	if d.Features().Has(feature.Merge) {
+		t.Fatal("CockroachDB does not support MERGE")
+	}
+
+	d = New()
+	d.Init(nil)
+	if d.Features().Has(feature.AsOfSystemTime) {
+		t.Fatal("Postgres must not have AS OF SYSTEM TIME")
+	}
+}
+
+func TestInitMerge(t *testing.T) {
+	d := initDialect(t, "PostgreSQL 14.5 on x86_64-pc-linux-gnu, compiled by gcc, 64-bit")
+	if d.Features().Has(feature.Merge) {
+		t.Fatal("PostgreSQL 14 does not support MERGE")
+	}
+
+	d = initDialect(t, "PostgreSQL 15.1 (Debian 15.1-1.pgdg110+1) on x86_64-pc-linux-gnu")
+	if !d.Features().Has(feature.Merge) {
+		t.Fatal("PostgreSQL 15 supports MERGE")
+	}
+}
+
+// versionDriver returns the DSN as the result of any query.
+type versionDriver struct{}
+
+func (versionDriver) Open(name string) (driver.Conn, error) {
+	return versionConn(name), nil
+}
+
+type versionConn string
+
+func (cn versionConn) Prepare(query string) (driver.Stmt, error) { return versionStmt(cn), nil }
+func (cn versionConn) Close() error                              { return nil }
+func (cn versionConn) Begin() (driver.Tx, error)                 { return nil, errors.New("not supported") }
+
+type versionStmt string
+
+func (s versionStmt) Close() error  { return nil }
+func (s versionStmt) NumInput() int { return 0 }
+
+func (s versionStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return nil, errors.New("not supported")
+}
+
+func (s versionStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return &versionRows{version: string(s)}, nil
+}
+
+type versionRows struct {
+	version string
+	done    bool
+}
+
+func (r *versionRows) Columns() []string { return []string{"version"} }
+func (r *versionRows) Close() error      { return nil }
+
+func (r *versionRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	dest[0] = r.version
+	return nil
+}
+
+func TestCockroachDBDeleteJoin(t *testing.T) {
+	type Model struct {
+		ID int64 `bun:",pk"`
+	}
+
+	db := bun.NewDB(nil, New(WithCockroachDB()))
+	q := db.NewDelete().
+		Model((*Model)(nil)).
+		Join("JOIN authors AS a").
+		JoinOn("a.id = model.id").
+		Where("a.name = ?", "hello")
+
+	b, err := q.AppendQuery(db.Formatter(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const wanted = `DELETE FROM "models" AS "model" USING authors AS a ` +
+		`WHERE ((a.id = model.id)) AND (a.name = 'hello')`
+	if string(b) != wanted {
+		t.Fatalf("got %s, wanted %s", b, wanted)
+	}
+}
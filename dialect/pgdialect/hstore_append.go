@@ -0,0 +1,104 @@
+package pgdialect
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"unicode/utf8"
+
+	"github.com/uptrace/bun/dialect"
+	"github.com/uptrace/bun/schema"
+)
+
+var mapStringStringType = reflect.TypeOf(map[string]string(nil))
+
+func hstoreAppender(typ reflect.Type) schema.AppenderFunc {
+	kind := typ.Kind()
+	if kind == reflect.Ptr {
+		typ = typ.Elem()
+		kind = typ.Kind()
+	}
+
+	switch kind {
+	case reflect.Map:
+		// ok:
+	default:
+		return nil
+	}
+
+	if typ.Key() == stringType && typ.Elem() == stringType {
+		return appendMapStringStringValue
+	}
+
+	return func(fmter schema.Formatter, b []byte, v reflect.Value) []byte {
+		err := fmt.Errorf("bun: HStore(unsupported %s)", v.Type())
+		return dialect.AppendError(b, err)
+	}
+}
+
+func appendMapStringStringValue(fmter schema.Formatter, b []byte, v reflect.Value) []byte {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return dialect.AppendNull(b)
+		}
+		v = v.Elem()
+	}
+	m := v.Convert(mapStringStringType).Interface().(map[string]string)
+	return appendMapStringString(b, m)
+}
+
+func appendMapStringString(b []byte, m map[string]string) []byte {
+	if m == nil {
+		return dialect.AppendNull(b)
+	}
+
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	b = append(b, '\'')
+
+	for i, key := range keys {
+		if i > 0 {
+			b = append(b, ',')
+		}
+		b = hstoreAppendString(b, key)
+		b = append(b, "=>"...)
+		b = hstoreAppendString(b, m[key])
+	}
+
+	b = append(b, '\'')
+
+	return b
+}
+
+func hstoreAppendString(b []byte, s string) []byte {
+	b = append(b, '"')
+	for _, r := range s {
+		switch r {
+		case 0:
+			// ignore
+		case '\'':
+			b = append(b, "''"...)
+		case '"':
+			b = append(b, '\\', '"')
+		case '\\':
+			b = append(b, '\\', '\\')
+		default:
+			if r < utf8.RuneSelf {
+				b = append(b, byte(r))
+				break
+			}
+			l := len(b)
+			if cap(b)-l < utf8.UTFMax {
+				b = append(b, make([]byte, utf8.UTFMax)...)
+			}
+			n := utf8.EncodeRune(b[l:l+utf8.UTFMax], r)
+			b = b[:l+n]
+		}
+	}
+	b = append(b, '"')
+	return b
+}
@@ -0,0 +1,134 @@
+package pgdialect
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+)
+
+type hstoreParser struct {
+	b []byte
+	i int
+
+	hasKey bool
+	buf    []byte
+	err    error
+}
+
+func newHStoreParser(b []byte) *hstoreParser {
+	return &hstoreParser{
+		b: b,
+	}
+}
+
+func (p *hstoreParser) NextKey() (string, error) {
+	if p.err != nil {
+		return "", p.err
+	}
+
+	p.skipSpace()
+	if !p.valid() {
+		return "", io.EOF
+	}
+
+	if p.hasKey {
+		if p.b[p.i] != ',' {
+			return "", p.error()
+		}
+		p.i++
+		p.skipSpace()
+	}
+	p.hasKey = true
+
+	if p.peek() != '"' {
+		return "", p.error()
+	}
+	p.i++
+
+	key, err := p.readSubstring()
+	if err != nil {
+		return "", err
+	}
+
+	p.skipSpace()
+	if !bytes.HasPrefix(p.b[p.i:], []byte("=>")) {
+		return "", p.error()
+	}
+	p.i += 2
+
+	return key, nil
+}
+
+// NextValue returns the value of the last key. NULL values are returned as empty strings.
+func (p *hstoreParser) NextValue() (string, error) {
+	if p.err != nil {
+		return "", p.err
+	}
+
+	p.skipSpace()
+
+	if bytes.HasPrefix(p.b[p.i:], []byte("NULL")) {
+		p.i += len("NULL")
+		return "", nil
+	}
+
+	if p.peek() != '"' {
+		return "", p.error()
+	}
+	p.i++
+
+	return p.readSubstring()
+}
+
+func (p *hstoreParser) readSubstring() (string, error) {
+	p.buf = p.buf[:0]
+	for {
+		c, err := p.readByte()
+		if err != nil {
+			return "", p.error()
+		}
+
+		switch c {
+		case '"':
+			return string(p.buf), nil
+		case '\\':
+			c, err = p.readByte()
+			if err != nil {
+				return "", p.error()
+			}
+		}
+
+		p.buf = append(p.buf, c)
+	}
+}
+
+func (p *hstoreParser) error() error {
+	p.err = fmt.Errorf("bun: can't parse hstore: %q", p.b)
+	return p.err
+}
+
+func (p *hstoreParser) valid() bool {
+	return p.i < len(p.b)
+}
+
+func (p *hstoreParser) readByte() (byte, error) {
+	if p.valid() {
+		c := p.b[p.i]
+		p.i++
+		return c, nil
+	}
+	return 0, io.EOF
+}
+
+func (p *hstoreParser) peek() byte {
+	if p.valid() {
+		return p.b[p.i]
+	}
+	return 0
+}
+
+func (p *hstoreParser) skipSpace() {
+	for p.valid() && p.b[p.i] == ' ' {
+		p.i++
+	}
+}
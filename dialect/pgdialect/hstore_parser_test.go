@@ -0,0 +1,52 @@
+package pgdialect
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/uptrace/bun/schema"
+)
+
+func TestHStoreParser(t *testing.T) {
+	tests := []struct {
+		s string
+		m map[string]string
+	}{
+		{``, map[string]string{}},
+		{`"a"=>"1"`, map[string]string{"a": "1"}},
+		{`"a"=>"1", "b"=>"2"`, map[string]string{"a": "1", "b": "2"}},
+		{` "a" => NULL `, map[string]string{"a": ""}},
+		{`"\"k\""=>"\\v,=>"`, map[string]string{`"k"`: `\v,=>`}},
+	}
+
+	for testi, test := range tests {
+		m, err := decodeMapStringString([]byte(test.s))
+		if err != nil {
+			t.Fatalf("test #%d: %s", testi, err)
+		}
+		if !reflect.DeepEqual(m, test.m) {
+			t.Fatalf("test #%d: got %#v, wanted %#v", testi, m, test.m)
+		}
+	}
+
+	for _, s := range []string{`"a"`, `"a"=>`, `"a"=>"1" "b"=>"2"`, `a=>1`} {
+		if _, err := decodeMapStringString([]byte(s)); err == nil {
+			t.Fatalf("parsing %q: expected an error", s)
+		}
+	}
+}
+
+func TestHStoreAppend(t *testing.T) {
+	fmter := schema.NewFormatter(New())
+
+	m := map[string]string{"b": `say "hi"`, "a": "it's"}
+	b, err := HStore(m).AppendQuery(fmter, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wanted := `'"a"=>"it''s","b"=>"say \"hi\""'`
+	if string(b) != wanted {
+		t.Fatalf("got %s, wanted %s", b, wanted)
+	}
+}
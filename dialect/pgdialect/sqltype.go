@@ -60,17 +60,34 @@ func fieldSQLType(field *schema.Field) string {
 		return "hstore"
 	}
 
+	if _, ok := field.Tag.Options["json"]; ok {
+		return pgTypeJSONB
+	}
+
+	if _, ok := field.Tag.Options["msgpack"]; ok {
+		return pgTypeBytea
+	}
+
 	if _, ok := field.Tag.Options["array"]; ok {
 		switch field.IndirectType.Kind() {
 		case reflect.Slice, reflect.Array:
-			sqlType := sqlType(field.IndirectType.Elem())
-			return sqlType + "[]"
+			return arraySQLType(field.IndirectType)
 		}
 	}
 
 	return sqlType(field.IndirectType)
 }
 
+// arraySQLType returns the SQL type of the array including all dimensions,
+// for example, `BIGINT[][]` for [][]int64.
+func arraySQLType(typ reflect.Type) string {
+	elemType := typ.Elem()
+	if isArrayType(elemType) {
+		return arraySQLType(elemType) + "[]"
+	}
+	return sqlType(elemType) + "[]"
+}
+
 func sqlType(typ reflect.Type) string {
 	switch typ {
 	case ipType:
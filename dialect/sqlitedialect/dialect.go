@@ -3,6 +3,7 @@ package sqlitedialect
 import (
 	"database/sql"
 	"reflect"
+	"strings"
 	"sync"
 
 	"github.com/uptrace/bun/dialect"
@@ -22,7 +23,13 @@ type Dialect struct {
 func New() *Dialect {
 	d := new(Dialect)
 	d.tables = schema.NewTables(d)
-	d.features = feature.Returning | feature.InsertTableAlias | feature.DeleteTableAlias
+	d.features = feature.Returning |
+		feature.InsertTableAlias |
+		feature.DeleteTableAlias |
+		feature.HavingAlias |
+		feature.InsertDefaultValues |
+		feature.WindowFunc |
+		feature.RenameColumn
 	return d
 }
 
@@ -36,6 +43,11 @@ func (d *Dialect) Features() feature.Feature {
 	return d.features
 }
 
+// IsRetryableError reports whether the err is caused by a locked database.
+func (d *Dialect) IsRetryableError(err error) bool {
+	return strings.Contains(err.Error(), "database is locked")
+}
+
 func (d *Dialect) Tables() *schema.Tables {
 	return d.tables
 }
@@ -52,6 +64,8 @@ func (d *Dialect) onField(field *schema.Field) {
 	switch field.DiscoveredSQLType {
 	case sqltype.SmallInt, sqltype.BigInt:
 		field.DiscoveredSQLType = sqltype.Integer
+	case sqltype.JSON:
+		field.DiscoveredSQLType = "TEXT"
 	}
 }
 
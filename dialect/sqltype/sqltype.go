@@ -11,4 +11,5 @@ const (
 	Timestamp       = "TIMESTAMP"
 	JSON            = "JSON"
 	JSONB           = "JSONB"
+	Blob            = "BLOB"
 )
@@ -0,0 +1,245 @@
+package pgdriver
+
+import (
+	"bufio"
+	"context"
+	"database/sql/driver"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+const copyBufSize = 64 << 10
+
+// CopyFrom executes the `COPY ... FROM STDIN` query streaming the data from the reader
+// using the COPY protocol. It returns the number of copied rows.
+func (cn *Conn) CopyFrom(ctx context.Context, r io.Reader, query string) (int64, error) {
+	if cn.isClosed() {
+		return 0, driver.ErrBadConn
+	}
+	n, err := cn.copyFrom(ctx, r, query)
+	if err != nil {
+		return 0, cn.checkBadConn(err)
+	}
+	return n, nil
+}
+
+func (cn *Conn) copyFrom(ctx context.Context, r io.Reader, query string) (int64, error) {
+	if err := writeQuery(ctx, cn, query); err != nil {
+		return 0, err
+	}
+	if err := readCopyInResponse(ctx, cn); err != nil {
+		return 0, err
+	}
+
+	buf := make([]byte, 5+copyBufSize)
+	buf[0] = copyDataMsg
+
+	for {
+		n, readErr := r.Read(buf[5:])
+		if n > 0 {
+			binary.BigEndian.PutUint32(buf[1:5], uint32(n+4))
+			if err := cn.withWriter(ctx, -1, func(wr *bufio.Writer) error {
+				_, err := wr.Write(buf[:5+n])
+				return err
+			}); err != nil {
+				return 0, err
+			}
+		}
+
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			// Abort the COPY and wait for the server to acknowledge it.
+			if err := writeCopyFail(ctx, cn, readErr.Error()); err != nil {
+				return 0, err
+			}
+			_, _ = readQuery(ctx, cn)
+			return 0, readErr
+		}
+	}
+
+	if err := writeCopyDone(ctx, cn); err != nil {
+		return 0, err
+	}
+
+	res, err := readQuery(ctx, cn)
+	if err != nil {
+		return 0, err
+	}
+	if res == nil {
+		return 0, nil
+	}
+	return res.RowsAffected()
+}
+
+func readCopyInResponse(ctx context.Context, cn *Conn) error {
+	rd := cn.reader(ctx, -1)
+
+	var firstErr error
+	for {
+		c, msgLen, err := readMessageType(rd)
+		if err != nil {
+			return err
+		}
+
+		switch c {
+		case copyInResponseMsg:
+			if err := rd.Discard(msgLen); err != nil {
+				return err
+			}
+			return firstErr
+		case errorResponseMsg:
+			e, err := readError(rd)
+			if err != nil {
+				return err
+			}
+			if firstErr == nil {
+				firstErr = e
+			}
+		case emptyQueryResponseMsg:
+			if firstErr == nil {
+				firstErr = errEmptyQuery
+			}
+		case commandCompleteMsg,
+			rowDescriptionMsg,
+			dataRowMsg,
+			noticeResponseMsg,
+			parameterStatusMsg:
+			if err := rd.Discard(msgLen); err != nil {
+				return err
+			}
+		case readyForQueryMsg:
+			if err := rd.Discard(msgLen); err != nil {
+				return err
+			}
+			if firstErr == nil {
+				firstErr = errors.New("pgdriver: CopyFrom: query is not COPY FROM STDIN")
+			}
+			return firstErr
+		default:
+			return fmt.Errorf("pgdriver: CopyFrom: unexpected message %q", c)
+		}
+	}
+}
+
+// CopyTo executes the `COPY ... TO STDOUT` query writing the data to the writer
+// using the COPY protocol. It returns the number of copied rows.
+func (cn *Conn) CopyTo(ctx context.Context, w io.Writer, query string) (int64, error) {
+	if cn.isClosed() {
+		return 0, driver.ErrBadConn
+	}
+	n, err := cn.copyTo(ctx, w, query)
+	if err != nil {
+		return 0, cn.checkBadConn(err)
+	}
+	return n, nil
+}
+
+func (cn *Conn) copyTo(ctx context.Context, w io.Writer, query string) (int64, error) {
+	if err := writeQuery(ctx, cn, query); err != nil {
+		return 0, err
+	}
+
+	rd := cn.reader(ctx, -1)
+
+	var res driver.Result
+	var firstErr error
+	for {
+		c, msgLen, err := readMessageType(rd)
+		if err != nil {
+			return 0, err
+		}
+
+		switch c {
+		case copyDataMsg:
+			tmp, err := rd.ReadTemp(msgLen)
+			if err != nil {
+				return 0, err
+			}
+			// Keep reading the data after a write error to finish the COPY.
+			if firstErr == nil {
+				if _, err := w.Write(tmp); err != nil {
+					firstErr = err
+				}
+			}
+		case errorResponseMsg:
+			e, err := readError(rd)
+			if err != nil {
+				return 0, err
+			}
+			if firstErr == nil {
+				firstErr = e
+			}
+		case emptyQueryResponseMsg:
+			if firstErr == nil {
+				firstErr = errEmptyQuery
+			}
+		case commandCompleteMsg:
+			tmp, err := rd.ReadTemp(msgLen)
+			if err != nil {
+				return 0, err
+			}
+
+			r, err := parseResult(tmp)
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+				}
+			} else {
+				res = r
+			}
+		case copyOutResponseMsg,
+			copyDoneMsg,
+			rowDescriptionMsg,
+			dataRowMsg,
+			noticeResponseMsg,
+			parameterStatusMsg:
+			if err := rd.Discard(msgLen); err != nil {
+				return 0, err
+			}
+		case readyForQueryMsg:
+			if err := rd.Discard(msgLen); err != nil {
+				return 0, err
+			}
+			if firstErr != nil {
+				return 0, firstErr
+			}
+			if res == nil {
+				return 0, nil
+			}
+			return res.RowsAffected()
+		default:
+			return 0, fmt.Errorf("pgdriver: CopyTo: unexpected message %q", c)
+		}
+	}
+}
+
+func writeCopyDone(ctx context.Context, cn *Conn) error {
+	wb := getWriteBuffer()
+	defer putWriteBuffer(wb)
+
+	wb.StartMessage(copyDoneMsg)
+	wb.FinishMessage()
+
+	return cn.withWriter(ctx, -1, func(wr *bufio.Writer) error {
+		_, err := wr.Write(wb.Bytes)
+		return err
+	})
+}
+
+func writeCopyFail(ctx context.Context, cn *Conn, reason string) error {
+	wb := getWriteBuffer()
+	defer putWriteBuffer(wb)
+
+	wb.StartMessage(copyFailMsg)
+	wb.WriteString(reason)
+	wb.FinishMessage()
+
+	return cn.withWriter(ctx, -1, func(wr *bufio.Writer) error {
+		_, err := wr.Write(wb.Bytes)
+		return err
+	})
+}
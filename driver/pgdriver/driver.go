@@ -6,13 +6,13 @@ import (
 	"context"
 	"database/sql"
 	"database/sql/driver"
-	"errors"
 	"fmt"
 	"io"
 	"log"
 	"net"
 	"os"
 	"strconv"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -222,14 +222,24 @@ var _ driver.ConnBeginTx = (*Conn)(nil)
 func (cn *Conn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
 	// No need to check if the conn is closed. ExecContext below handles that.
 
-	if sql.IsolationLevel(opts.Isolation) != sql.LevelDefault {
-		return nil, errors.New("pgdriver: custom IsolationLevel is not supported")
+	query := "BEGIN"
+
+	switch isolation := sql.IsolationLevel(opts.Isolation); isolation {
+	case sql.LevelDefault:
+	case sql.LevelReadUncommitted,
+		sql.LevelReadCommitted,
+		sql.LevelRepeatableRead,
+		sql.LevelSerializable:
+		query += " ISOLATION LEVEL " + strings.ToUpper(isolation.String())
+	default:
+		return nil, fmt.Errorf("pgdriver: unsupported IsolationLevel: %s", isolation)
 	}
+
 	if opts.ReadOnly {
-		return nil, errors.New("pgdriver: ReadOnly transactions are not supported")
+		query += " READ ONLY"
 	}
 
-	if _, err := cn.ExecContext(ctx, "BEGIN", nil); err != nil {
+	if _, err := cn.ExecContext(ctx, query, nil); err != nil {
 		return nil, err
 	}
 	return tx{cn: cn}, nil
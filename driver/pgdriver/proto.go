@@ -73,6 +73,7 @@ const (
 	copyOutResponseMsg = 'H'
 	copyDataMsg        = 'd'
 	copyDoneMsg        = 'c'
+	copyFailMsg        = 'f'
 )
 
 var errEmptyQuery = errors.New("pgdriver: query is empty")
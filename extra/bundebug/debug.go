@@ -4,8 +4,9 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"io"
+	"os"
 	"reflect"
-	"strings"
 	"time"
 
 	"github.com/fatih/color"
@@ -15,20 +16,77 @@ import (
 
 type ConfigOption func(*QueryHook)
 
+// WithEnabled enables or disables the hook.
+func WithEnabled(on bool) ConfigOption {
+	return func(h *QueryHook) {
+		h.enabled = on
+	}
+}
+
+// WithVerbose configures the hook to log all queries
+// (by default, only failed queries are logged).
 func WithVerbose() ConfigOption {
 	return func(h *QueryHook) {
 		h.verbose = true
 	}
 }
 
+// FromEnv configures the hook using the environment variable value
+// (BUNDEBUG if the key is empty).
+// For example, FromEnv("BUNDEBUG"):
+//   - BUNDEBUG=0 - disables the hook.
+//   - BUNDEBUG=1 - enables the hook.
+//   - BUNDEBUG=2 - enables the hook and verbose mode.
+func FromEnv(key string) ConfigOption {
+	if key == "" {
+		key = "BUNDEBUG"
+	}
+	return func(h *QueryHook) {
+		if env, ok := os.LookupEnv(key); ok {
+			h.enabled = env != "" && env != "0"
+			h.verbose = env == "2"
+		}
+	}
+}
+
+// WithSlowQueryThreshold configures the hook to log successful queries only when
+// they take longer than the threshold. Failed queries are always logged.
+func WithSlowQueryThreshold(threshold time.Duration) ConfigOption {
+	return func(h *QueryHook) {
+		h.slowThreshold = threshold
+	}
+}
+
+// WithWriter sets the writer for the logs (os.Stdout by default).
+func WithWriter(w io.Writer) ConfigOption {
+	return func(h *QueryHook) {
+		h.writer = w
+	}
+}
+
+// WithColor enables or disables colorized output.
+func WithColor(on bool) ConfigOption {
+	return func(h *QueryHook) {
+		h.noColor = !on
+	}
+}
+
 type QueryHook struct {
-	verbose bool
+	enabled       bool
+	verbose       bool
+	noColor       bool
+	slowThreshold time.Duration
+	writer        io.Writer
 }
 
 var _ bun.QueryHook = (*QueryHook)(nil)
 
 func NewQueryHook(opts ...ConfigOption) *QueryHook {
-	h := new(QueryHook)
+	h := &QueryHook{
+		enabled: true,
+		noColor: color.NoColor,
+		writer:  os.Stdout,
+	}
 	for _, opt := range opts {
 		opt(h)
 	}
@@ -42,20 +100,21 @@ func (h *QueryHook) BeforeQuery(
 }
 
 func (h *QueryHook) AfterQuery(ctx context.Context, event *bun.QueryEvent) {
-	if !h.verbose {
-		switch event.Err {
-		case nil, sql.ErrNoRows:
-			return
-		}
+	if !h.enabled {
+		return
 	}
 
 	now := time.Now()
 	dur := now.Sub(event.StartTime)
 
+	if !h.shouldLog(event, dur) {
+		return
+	}
+
 	args := []interface{}{
 		"[bun]",
 		now.Format(" 15:04:05.000 "),
-		formatOperation(event),
+		h.formatOperation(event),
 		fmt.Sprintf(" %10s ", dur.Round(time.Microsecond)),
 		event.Query,
 	}
@@ -64,44 +123,37 @@ func (h *QueryHook) AfterQuery(ctx context.Context, event *bun.QueryEvent) {
 		typ := reflect.TypeOf(event.Err).String()
 		args = append(args,
 			"\t",
-			color.New(color.BgRed).Sprintf(" %s ", typ+": "+event.Err.Error()),
+			h.colorize(color.New(color.BgRed)).Sprintf(" %s ", typ+": "+event.Err.Error()),
 		)
 	}
 
-	fmt.Println(args...)
+	fmt.Fprintln(h.writer, args...)
 }
 
-func formatOperation(event *bun.QueryEvent) string {
-	operation := eventOperation(event)
-	return operationColor(operation).Sprintf(" %-16s ", operation)
+func (h *QueryHook) shouldLog(event *bun.QueryEvent, dur time.Duration) bool {
+	switch event.Err {
+	case nil, sql.ErrNoRows:
+	default:
+		return true
+	}
+	if h.slowThreshold > 0 {
+		return dur >= h.slowThreshold
+	}
+	return h.verbose
 }
 
-func eventOperation(event *bun.QueryEvent) string {
-	switch event.QueryAppender.(type) {
-	case *bun.SelectQuery:
-		return "SELECT"
-	case *bun.InsertQuery:
-		return "INSERT"
-	case *bun.UpdateQuery:
-		return "UPDATE"
-	case *bun.DeleteQuery:
-		return "DELETE"
-	case *bun.CreateTableQuery:
-		return "CREATE TABLE"
-	case *bun.DropTableQuery:
-		return "DROP TABLE"
-	}
-	return queryOperation(event.Query)
+func (h *QueryHook) formatOperation(event *bun.QueryEvent) string {
+	operation := event.Operation()
+	return h.colorize(operationColor(operation)).Sprintf(" %-16s ", operation)
 }
 
-func queryOperation(name string) string {
-	if idx := strings.IndexByte(name, ' '); idx > 0 {
-		name = name[:idx]
-	}
-	if len(name) > 16 {
-		name = name[:16]
+func (h *QueryHook) colorize(c *color.Color) *color.Color {
+	if h.noColor {
+		c.DisableColor()
+	} else {
+		c.EnableColor()
 	}
-	return name
+	return c
 }
 
 func operationColor(operation string) *color.Color {
@@ -0,0 +1,88 @@
+package bunotel
+
+import (
+	"context"
+	"database/sql"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// ReportDBStatsMetrics reports sql.DBStats metrics, for example, the number of open
+// and idle connections, using the meter from the provider.
+//
+//	bunotel.ReportDBStatsMetrics(sqldb, provider, attribute.String("db.name", "mydb"))
+func ReportDBStatsMetrics(
+	db *sql.DB, provider metric.MeterProvider, attrs ...attribute.KeyValue,
+) error {
+	meter := provider.Meter(instrumName)
+
+	maxOpenConns, _ := meter.Int64ObservableGauge(
+		"go.sql.connections_max_open",
+		metric.WithDescription("Maximum number of open connections to the database"),
+	)
+	openConns, _ := meter.Int64ObservableGauge(
+		"go.sql.connections_open",
+		metric.WithDescription("The number of established connections both in use and idle"),
+	)
+	inUseConns, _ := meter.Int64ObservableGauge(
+		"go.sql.connections_in_use",
+		metric.WithDescription("The number of connections currently in use"),
+	)
+	idleConns, _ := meter.Int64ObservableGauge(
+		"go.sql.connections_idle",
+		metric.WithDescription("The number of idle connections"),
+	)
+	connsWaitCount, _ := meter.Int64ObservableCounter(
+		"go.sql.connections_wait_count",
+		metric.WithDescription("The total number of connections waited for"),
+	)
+	connsWaitDuration, _ := meter.Int64ObservableCounter(
+		"go.sql.connections_wait_duration",
+		metric.WithDescription("The total time blocked waiting for a new connection"),
+		metric.WithUnit("ns"),
+	)
+	connsClosedMaxIdle, _ := meter.Int64ObservableCounter(
+		"go.sql.connections_closed_max_idle",
+		metric.WithDescription("The total number of connections closed due to SetMaxIdleConns"),
+	)
+	connsClosedMaxIdleTime, _ := meter.Int64ObservableCounter(
+		"go.sql.connections_closed_max_idle_time",
+		metric.WithDescription("The total number of connections closed due to SetConnMaxIdleTime"),
+	)
+	connsClosedMaxLifetime, _ := meter.Int64ObservableCounter(
+		"go.sql.connections_closed_max_lifetime",
+		metric.WithDescription("The total number of connections closed due to SetConnMaxLifetime"),
+	)
+
+	opt := metric.WithAttributes(attrs...)
+	_, err := meter.RegisterCallback(
+		func(ctx context.Context, o metric.Observer) error {
+			stats := db.Stats()
+
+			o.ObserveInt64(maxOpenConns, int64(stats.MaxOpenConnections), opt)
+
+			o.ObserveInt64(openConns, int64(stats.OpenConnections), opt)
+			o.ObserveInt64(inUseConns, int64(stats.InUse), opt)
+			o.ObserveInt64(idleConns, int64(stats.Idle), opt)
+
+			o.ObserveInt64(connsWaitCount, stats.WaitCount, opt)
+			o.ObserveInt64(connsWaitDuration, int64(stats.WaitDuration), opt)
+			o.ObserveInt64(connsClosedMaxIdle, stats.MaxIdleClosed, opt)
+			o.ObserveInt64(connsClosedMaxIdleTime, stats.MaxIdleTimeClosed, opt)
+			o.ObserveInt64(connsClosedMaxLifetime, stats.MaxLifetimeClosed, opt)
+
+			return nil
+		},
+		maxOpenConns,
+		openConns,
+		inUseConns,
+		idleConns,
+		connsWaitCount,
+		connsWaitDuration,
+		connsClosedMaxIdle,
+		connsClosedMaxIdleTime,
+		connsClosedMaxLifetime,
+	)
+	return err
+}
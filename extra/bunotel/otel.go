@@ -5,10 +5,12 @@ import (
 	"database/sql"
 	"runtime"
 	"strings"
+	"time"
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/trace"
 
 	"github.com/uptrace/bun"
@@ -16,11 +18,44 @@ import (
 	"github.com/uptrace/bun/schema"
 )
 
+const instrumName = "github.com/uptrace/bun/extra/bunotel"
+
 var tracer = otel.Tracer("github.com/uptrace/bun")
 
 type ConfigOption func(*QueryHook)
 
-type QueryHook struct{}
+// WithAttributes configures attributes that are added to every span and metric.
+func WithAttributes(attrs ...attribute.KeyValue) ConfigOption {
+	return func(h *QueryHook) {
+		h.attrs = append(h.attrs, attrs...)
+	}
+}
+
+// WithDBName configures the db.name attribute.
+func WithDBName(name string) ConfigOption {
+	return func(h *QueryHook) {
+		h.attrs = append(h.attrs, attribute.String("db.name", name))
+	}
+}
+
+// WithMeterProvider configures the meter provider used to record the query
+// duration histogram. By default, metrics are disabled.
+func WithMeterProvider(provider metric.MeterProvider) ConfigOption {
+	return func(h *QueryHook) {
+		h.meter = provider.Meter(instrumName)
+	}
+}
+
+// QueryHook creates a span for each query and, if a meter provider is configured,
+// records the query duration.
+//
+//	db.AddQueryHook(bunotel.NewQueryHook(bunotel.WithDBName("mydb")))
+type QueryHook struct {
+	attrs []attribute.KeyValue
+
+	meter       metric.Meter
+	queryTiming metric.Int64Histogram
+}
 
 var _ bun.QueryHook = (*QueryHook)(nil)
 
@@ -29,6 +64,13 @@ func NewQueryHook(opts ...ConfigOption) *QueryHook {
 	for _, opt := range opts {
 		opt(h)
 	}
+	if h.meter != nil {
+		h.queryTiming, _ = h.meter.Int64Histogram(
+			"go.sql.query_timing",
+			metric.WithDescription("Timing of processed queries"),
+			metric.WithUnit("ms"),
+		)
+	}
 	return h
 }
 
@@ -37,31 +79,54 @@ func (h *QueryHook) BeforeQuery(ctx context.Context, event *bun.QueryEvent) cont
 		return ctx
 	}
 
-	ctx, _ = tracer.Start(ctx, "")
+	ctx, _ = tracer.Start(ctx, "", trace.WithSpanKind(trace.SpanKindClient))
 	return ctx
 }
 
 func (h *QueryHook) AfterQuery(ctx context.Context, event *bun.QueryEvent) {
+	operation := event.Operation()
+
+	if h.queryTiming != nil {
+		labels := make([]attribute.KeyValue, 0, len(h.attrs)+2)
+		labels = append(labels, h.attrs...)
+		labels = append(labels,
+			attribute.String("db.system", dbSystem(event.DB)),
+			attribute.String("db.operation", operation),
+		)
+
+		dur := time.Since(event.StartTime)
+		h.queryTiming.Record(ctx, dur.Milliseconds(), metric.WithAttributes(labels...))
+	}
+
 	span := trace.SpanFromContext(ctx)
 	if !span.IsRecording() {
 		return
 	}
 	defer span.End()
 
-	operation := eventOperation(event)
+	span.SetName(operation)
+
 	query := eventQuery(event, operation)
 	fn, file, line := funcFileLine("github.com/uptrace/bun")
 
-	attrs := make([]attribute.KeyValue, 0, 10)
+	attrs := make([]attribute.KeyValue, 0, 10+len(h.attrs))
+	attrs = append(attrs, h.attrs...)
 	attrs = append(attrs,
 		attribute.String("db.system", dbSystem(event.DB)),
 		attribute.String("db.statement", query),
+		attribute.String("db.operation", operation),
 
 		attribute.String("code.function", fn),
 		attribute.String("code.filepath", file),
 		attribute.Int("code.lineno", line),
 	)
 
+	if event.Result != nil {
+		if n, err := event.Result.RowsAffected(); err == nil {
+			attrs = append(attrs, attribute.Int64("db.rows_affected", n))
+		}
+	}
+
 	if event.Err != nil {
 		switch event.Err {
 		case sql.ErrNoRows:
@@ -100,34 +165,6 @@ func funcFileLine(pkg string) (string, string, int) {
 	return fn, file, line
 }
 
-func eventOperation(event *bun.QueryEvent) string {
-	switch event.QueryAppender.(type) {
-	case *bun.SelectQuery:
-		return "SELECT"
-	case *bun.InsertQuery:
-		return "INSERT"
-	case *bun.UpdateQuery:
-		return "UPDATE"
-	case *bun.DeleteQuery:
-		return "DELETE"
-	case *bun.CreateTableQuery:
-		return "CREATE TABLE"
-	case *bun.DropTableQuery:
-		return "DROP TABLE"
-	}
-	return queryOperation(event.Query)
-}
-
-func queryOperation(name string) string {
-	if idx := strings.IndexByte(name, ' '); idx > 0 {
-		name = name[:idx]
-	}
-	if len(name) > 16 {
-		name = name[:16]
-	}
-	return name
-}
-
 func eventQuery(event *bun.QueryEvent, operation string) string {
 	const softQueryLimit = 5000
 	const hardQueryLimit = 10000
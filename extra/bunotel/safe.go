@@ -1,6 +1,6 @@
 // +build appengine
 
-package internal
+package bunotel
 
 func bytesToString(b []byte) string {
 	return string(b)
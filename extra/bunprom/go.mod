@@ -0,0 +1,11 @@
+module github.com/uptrace/bun/extra/bunprom
+
+go 1.16
+
+replace github.com/uptrace/bun => ../..
+
+require (
+	github.com/prometheus/client_golang v1.13.0
+	github.com/uptrace/bun v0.4.0
+	golang.org/x/sys v0.13.0 // indirect
+)
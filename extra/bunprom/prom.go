@@ -0,0 +1,123 @@
+package bunprom
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/schema"
+)
+
+type ConfigOption func(*QueryHook)
+
+// WithNamespace sets the namespace of the metrics ("bun" by default).
+func WithNamespace(namespace string) ConfigOption {
+	return func(h *QueryHook) {
+		h.namespace = namespace
+	}
+}
+
+// WithBuckets sets the buckets of the query duration histogram.
+func WithBuckets(buckets []float64) ConfigOption {
+	return func(h *QueryHook) {
+		h.buckets = buckets
+	}
+}
+
+// QueryHook counts queries and records their duration labeled by the operation,
+// the table, and whether the query failed. It also implements prometheus.Collector:
+//
+//	hook := bunprom.NewQueryHook()
+//	db.AddQueryHook(hook)
+//	prometheus.MustRegister(hook)
+type QueryHook struct {
+	namespace string
+	buckets   []float64
+
+	queries  *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+}
+
+var (
+	_ bun.QueryHook        = (*QueryHook)(nil)
+	_ prometheus.Collector = (*QueryHook)(nil)
+)
+
+func NewQueryHook(opts ...ConfigOption) *QueryHook {
+	h := &QueryHook{
+		namespace: "bun",
+		buckets:   prometheus.DefBuckets,
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	labels := []string{"operation", "table", "error"}
+	h.queries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: h.namespace,
+		Name:      "queries_total",
+		Help:      "The total number of processed queries.",
+	}, labels)
+	h.duration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: h.namespace,
+		Name:      "query_duration_seconds",
+		Help:      "The duration of processed queries.",
+		Buckets:   h.buckets,
+	}, labels)
+
+	return h
+}
+
+func (h *QueryHook) BeforeQuery(ctx context.Context, event *bun.QueryEvent) context.Context {
+	return ctx
+}
+
+func (h *QueryHook) AfterQuery(ctx context.Context, event *bun.QueryEvent) {
+	dur := time.Since(event.StartTime)
+
+	labels := prometheus.Labels{
+		"operation": event.Operation(),
+		"table":     eventTable(event),
+		"error":     eventError(event),
+	}
+
+	h.queries.With(labels).Inc()
+	h.duration.With(labels).Observe(dur.Seconds())
+}
+
+func (h *QueryHook) Describe(ch chan<- *prometheus.Desc) {
+	h.queries.Describe(ch)
+	h.duration.Describe(ch)
+}
+
+func (h *QueryHook) Collect(ch chan<- prometheus.Metric) {
+	h.queries.Collect(ch)
+	h.duration.Collect(ch)
+}
+
+// eventTable returns the model table name. Other tables are not labeled,
+// because table expressions, for example, subqueries, would make the label unbounded.
+func eventTable(event *bun.QueryEvent) string {
+	q, ok := event.QueryAppender.(interface{ GetModel() bun.Model })
+	if !ok {
+		return ""
+	}
+	if m, ok := q.GetModel().(interface{ Table() *schema.Table }); ok {
+		if table := m.Table(); table != nil {
+			return table.Name
+		}
+	}
+	return ""
+}
+
+func eventError(event *bun.QueryEvent) string {
+	switch event.Err {
+	case nil, sql.ErrNoRows:
+		return "false"
+	default:
+		return "true"
+	}
+}
@@ -0,0 +1,16 @@
+package bunprom
+
+import (
+	"database/sql"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+)
+
+// NewDBStatsCollector returns a collector that exports sql.DBStats,
+// for example, the number of open and idle connections, labeled with the db name.
+//
+//	prometheus.MustRegister(bunprom.NewDBStatsCollector(sqldb, "mydb"))
+func NewDBStatsCollector(db *sql.DB, dbName string) prometheus.Collector {
+	return collectors.NewDBStatsCollector(db, dbName)
+}
@@ -0,0 +1,77 @@
+package gen
+
+import (
+	"github.com/uptrace/bun"
+)
+
+// Column is a model column referenced by the generated code. The column is qualified
+// with the default model table alias, for example, "user"."email"; use As when the query
+// uses another alias.
+//
+// The generator uses Column for the fields without a typed column, for example, StringColumn,
+// whose conditions only accept values of the field type.
+type Column struct {
+	Table string
+	Name  string
+}
+
+func (c Column) String() string {
+	return c.Name
+}
+
+// As returns the column qualified with the table alias used by the query.
+func (c Column) As(alias string) Column {
+	c.Table = alias
+	return c
+}
+
+// Ident returns the column identifier qualified with the table alias.
+func (c Column) Ident() bun.Ident {
+	return bun.Ident(c.Table + "." + c.Name)
+}
+
+// Eq returns the `column = value` condition. The results can be passed directly to Where,
+// for example, `q.Where(UserCols.Email.Eq("hello@example.com"))`.
+func (c Column) Eq(value interface{}) (string, interface{}, interface{}) {
+	return "? = ?", c.Ident(), value
+}
+
+// Ne returns the `column <> value` condition.
+func (c Column) Ne(value interface{}) (string, interface{}, interface{}) {
+	return "? <> ?", c.Ident(), value
+}
+
+// Gt returns the `column > value` condition.
+func (c Column) Gt(value interface{}) (string, interface{}, interface{}) {
+	return "? > ?", c.Ident(), value
+}
+
+// Ge returns the `column >= value` condition.
+func (c Column) Ge(value interface{}) (string, interface{}, interface{}) {
+	return "? >= ?", c.Ident(), value
+}
+
+// Lt returns the `column < value` condition.
+func (c Column) Lt(value interface{}) (string, interface{}, interface{}) {
+	return "? < ?", c.Ident(), value
+}
+
+// Le returns the `column <= value` condition.
+func (c Column) Le(value interface{}) (string, interface{}, interface{}) {
+	return "? <= ?", c.Ident(), value
+}
+
+// In returns the `column IN (values)` condition, where values is a slice.
+func (c Column) In(values interface{}) (string, interface{}, interface{}) {
+	return "? IN (?)", c.Ident(), bun.In(values)
+}
+
+// IsNull returns the `column IS NULL` condition.
+func (c Column) IsNull() (string, interface{}) {
+	return "? IS NULL", c.Ident()
+}
+
+// IsNotNull returns the `column IS NOT NULL` condition.
+func (c Column) IsNotNull() (string, interface{}) {
+	return "? IS NOT NULL", c.Ident()
+}
@@ -0,0 +1,531 @@
+package gen
+
+import (
+	"time"
+
+	"github.com/uptrace/bun"
+)
+
+//------------------------------------------------------------------------------
+
+// StringColumn is a column of the string model field.
+type StringColumn struct {
+	Column
+}
+
+// As returns the column qualified with the table alias used by the query.
+func (c StringColumn) As(alias string) StringColumn {
+	return StringColumn{c.Column.As(alias)}
+}
+
+// Eq returns the `column = value` condition.
+func (c StringColumn) Eq(value string) (string, interface{}, interface{}) {
+	return c.Column.Eq(value)
+}
+
+// Ne returns the `column <> value` condition.
+func (c StringColumn) Ne(value string) (string, interface{}, interface{}) {
+	return c.Column.Ne(value)
+}
+
+// Gt returns the `column > value` condition.
+func (c StringColumn) Gt(value string) (string, interface{}, interface{}) {
+	return c.Column.Gt(value)
+}
+
+// Ge returns the `column >= value` condition.
+func (c StringColumn) Ge(value string) (string, interface{}, interface{}) {
+	return c.Column.Ge(value)
+}
+
+// Lt returns the `column < value` condition.
+func (c StringColumn) Lt(value string) (string, interface{}, interface{}) {
+	return c.Column.Lt(value)
+}
+
+// Le returns the `column <= value` condition.
+func (c StringColumn) Le(value string) (string, interface{}, interface{}) {
+	return c.Column.Le(value)
+}
+
+// In returns the `column IN (values)` condition.
+func (c StringColumn) In(values ...string) (string, interface{}, interface{}) {
+	return "? IN (?)", c.Ident(), bun.In(values)
+}
+
+//------------------------------------------------------------------------------
+
+// BoolColumn is a column of the bool model field.
+type BoolColumn struct {
+	Column
+}
+
+// As returns the column qualified with the table alias used by the query.
+func (c BoolColumn) As(alias string) BoolColumn {
+	return BoolColumn{c.Column.As(alias)}
+}
+
+// Eq returns the `column = value` condition.
+func (c BoolColumn) Eq(value bool) (string, interface{}, interface{}) {
+	return c.Column.Eq(value)
+}
+
+// Ne returns the `column <> value` condition.
+func (c BoolColumn) Ne(value bool) (string, interface{}, interface{}) {
+	return c.Column.Ne(value)
+}
+
+// In returns the `column IN (values)` condition.
+func (c BoolColumn) In(values ...bool) (string, interface{}, interface{}) {
+	return "? IN (?)", c.Ident(), bun.In(values)
+}
+
+//------------------------------------------------------------------------------
+
+// IntColumn is a column of the int model field.
+type IntColumn struct {
+	Column
+}
+
+// As returns the column qualified with the table alias used by the query.
+func (c IntColumn) As(alias string) IntColumn {
+	return IntColumn{c.Column.As(alias)}
+}
+
+// Eq returns the `column = value` condition.
+func (c IntColumn) Eq(value int) (string, interface{}, interface{}) {
+	return c.Column.Eq(value)
+}
+
+// Ne returns the `column <> value` condition.
+func (c IntColumn) Ne(value int) (string, interface{}, interface{}) {
+	return c.Column.Ne(value)
+}
+
+// Gt returns the `column > value` condition.
+func (c IntColumn) Gt(value int) (string, interface{}, interface{}) {
+	return c.Column.Gt(value)
+}
+
+// Ge returns the `column >= value` condition.
+func (c IntColumn) Ge(value int) (string, interface{}, interface{}) {
+	return c.Column.Ge(value)
+}
+
+// Lt returns the `column < value` condition.
+func (c IntColumn) Lt(value int) (string, interface{}, interface{}) {
+	return c.Column.Lt(value)
+}
+
+// Le returns the `column <= value` condition.
+func (c IntColumn) Le(value int) (string, interface{}, interface{}) {
+	return c.Column.Le(value)
+}
+
+// In returns the `column IN (values)` condition.
+func (c IntColumn) In(values ...int) (string, interface{}, interface{}) {
+	return "? IN (?)", c.Ident(), bun.In(values)
+}
+
+//------------------------------------------------------------------------------
+
+// Int32Column is a column of the int32 model field.
+type Int32Column struct {
+	Column
+}
+
+// As returns the column qualified with the table alias used by the query.
+func (c Int32Column) As(alias string) Int32Column {
+	return Int32Column{c.Column.As(alias)}
+}
+
+// Eq returns the `column = value` condition.
+func (c Int32Column) Eq(value int32) (string, interface{}, interface{}) {
+	return c.Column.Eq(value)
+}
+
+// Ne returns the `column <> value` condition.
+func (c Int32Column) Ne(value int32) (string, interface{}, interface{}) {
+	return c.Column.Ne(value)
+}
+
+// Gt returns the `column > value` condition.
+func (c Int32Column) Gt(value int32) (string, interface{}, interface{}) {
+	return c.Column.Gt(value)
+}
+
+// Ge returns the `column >= value` condition.
+func (c Int32Column) Ge(value int32) (string, interface{}, interface{}) {
+	return c.Column.Ge(value)
+}
+
+// Lt returns the `column < value` condition.
+func (c Int32Column) Lt(value int32) (string, interface{}, interface{}) {
+	return c.Column.Lt(value)
+}
+
+// Le returns the `column <= value` condition.
+func (c Int32Column) Le(value int32) (string, interface{}, interface{}) {
+	return c.Column.Le(value)
+}
+
+// In returns the `column IN (values)` condition.
+func (c Int32Column) In(values ...int32) (string, interface{}, interface{}) {
+	return "? IN (?)", c.Ident(), bun.In(values)
+}
+
+//------------------------------------------------------------------------------
+
+// Int64Column is a column of the int64 model field.
+type Int64Column struct {
+	Column
+}
+
+// As returns the column qualified with the table alias used by the query.
+func (c Int64Column) As(alias string) Int64Column {
+	return Int64Column{c.Column.As(alias)}
+}
+
+// Eq returns the `column = value` condition.
+func (c Int64Column) Eq(value int64) (string, interface{}, interface{}) {
+	return c.Column.Eq(value)
+}
+
+// Ne returns the `column <> value` condition.
+func (c Int64Column) Ne(value int64) (string, interface{}, interface{}) {
+	return c.Column.Ne(value)
+}
+
+// Gt returns the `column > value` condition.
+func (c Int64Column) Gt(value int64) (string, interface{}, interface{}) {
+	return c.Column.Gt(value)
+}
+
+// Ge returns the `column >= value` condition.
+func (c Int64Column) Ge(value int64) (string, interface{}, interface{}) {
+	return c.Column.Ge(value)
+}
+
+// Lt returns the `column < value` condition.
+func (c Int64Column) Lt(value int64) (string, interface{}, interface{}) {
+	return c.Column.Lt(value)
+}
+
+// Le returns the `column <= value` condition.
+func (c Int64Column) Le(value int64) (string, interface{}, interface{}) {
+	return c.Column.Le(value)
+}
+
+// In returns the `column IN (values)` condition.
+func (c Int64Column) In(values ...int64) (string, interface{}, interface{}) {
+	return "? IN (?)", c.Ident(), bun.In(values)
+}
+
+//------------------------------------------------------------------------------
+
+// UintColumn is a column of the uint model field.
+type UintColumn struct {
+	Column
+}
+
+// As returns the column qualified with the table alias used by the query.
+func (c UintColumn) As(alias string) UintColumn {
+	return UintColumn{c.Column.As(alias)}
+}
+
+// Eq returns the `column = value` condition.
+func (c UintColumn) Eq(value uint) (string, interface{}, interface{}) {
+	return c.Column.Eq(value)
+}
+
+// Ne returns the `column <> value` condition.
+func (c UintColumn) Ne(value uint) (string, interface{}, interface{}) {
+	return c.Column.Ne(value)
+}
+
+// Gt returns the `column > value` condition.
+func (c UintColumn) Gt(value uint) (string, interface{}, interface{}) {
+	return c.Column.Gt(value)
+}
+
+// Ge returns the `column >= value` condition.
+func (c UintColumn) Ge(value uint) (string, interface{}, interface{}) {
+	return c.Column.Ge(value)
+}
+
+// Lt returns the `column < value` condition.
+func (c UintColumn) Lt(value uint) (string, interface{}, interface{}) {
+	return c.Column.Lt(value)
+}
+
+// Le returns the `column <= value` condition.
+func (c UintColumn) Le(value uint) (string, interface{}, interface{}) {
+	return c.Column.Le(value)
+}
+
+// In returns the `column IN (values)` condition.
+func (c UintColumn) In(values ...uint) (string, interface{}, interface{}) {
+	return "? IN (?)", c.Ident(), bun.In(values)
+}
+
+//------------------------------------------------------------------------------
+
+// Uint32Column is a column of the uint32 model field.
+type Uint32Column struct {
+	Column
+}
+
+// As returns the column qualified with the table alias used by the query.
+func (c Uint32Column) As(alias string) Uint32Column {
+	return Uint32Column{c.Column.As(alias)}
+}
+
+// Eq returns the `column = value` condition.
+func (c Uint32Column) Eq(value uint32) (string, interface{}, interface{}) {
+	return c.Column.Eq(value)
+}
+
+// Ne returns the `column <> value` condition.
+func (c Uint32Column) Ne(value uint32) (string, interface{}, interface{}) {
+	return c.Column.Ne(value)
+}
+
+// Gt returns the `column > value` condition.
+func (c Uint32Column) Gt(value uint32) (string, interface{}, interface{}) {
+	return c.Column.Gt(value)
+}
+
+// Ge returns the `column >= value` condition.
+func (c Uint32Column) Ge(value uint32) (string, interface{}, interface{}) {
+	return c.Column.Ge(value)
+}
+
+// Lt returns the `column < value` condition.
+func (c Uint32Column) Lt(value uint32) (string, interface{}, interface{}) {
+	return c.Column.Lt(value)
+}
+
+// Le returns the `column <= value` condition.
+func (c Uint32Column) Le(value uint32) (string, interface{}, interface{}) {
+	return c.Column.Le(value)
+}
+
+// In returns the `column IN (values)` condition.
+func (c Uint32Column) In(values ...uint32) (string, interface{}, interface{}) {
+	return "? IN (?)", c.Ident(), bun.In(values)
+}
+
+//------------------------------------------------------------------------------
+
+// Uint64Column is a column of the uint64 model field.
+type Uint64Column struct {
+	Column
+}
+
+// As returns the column qualified with the table alias used by the query.
+func (c Uint64Column) As(alias string) Uint64Column {
+	return Uint64Column{c.Column.As(alias)}
+}
+
+// Eq returns the `column = value` condition.
+func (c Uint64Column) Eq(value uint64) (string, interface{}, interface{}) {
+	return c.Column.Eq(value)
+}
+
+// Ne returns the `column <> value` condition.
+func (c Uint64Column) Ne(value uint64) (string, interface{}, interface{}) {
+	return c.Column.Ne(value)
+}
+
+// Gt returns the `column > value` condition.
+func (c Uint64Column) Gt(value uint64) (string, interface{}, interface{}) {
+	return c.Column.Gt(value)
+}
+
+// Ge returns the `column >= value` condition.
+func (c Uint64Column) Ge(value uint64) (string, interface{}, interface{}) {
+	return c.Column.Ge(value)
+}
+
+// Lt returns the `column < value` condition.
+func (c Uint64Column) Lt(value uint64) (string, interface{}, interface{}) {
+	return c.Column.Lt(value)
+}
+
+// Le returns the `column <= value` condition.
+func (c Uint64Column) Le(value uint64) (string, interface{}, interface{}) {
+	return c.Column.Le(value)
+}
+
+// In returns the `column IN (values)` condition.
+func (c Uint64Column) In(values ...uint64) (string, interface{}, interface{}) {
+	return "? IN (?)", c.Ident(), bun.In(values)
+}
+
+//------------------------------------------------------------------------------
+
+// Float32Column is a column of the float32 model field.
+type Float32Column struct {
+	Column
+}
+
+// As returns the column qualified with the table alias used by the query.
+func (c Float32Column) As(alias string) Float32Column {
+	return Float32Column{c.Column.As(alias)}
+}
+
+// Eq returns the `column = value` condition.
+func (c Float32Column) Eq(value float32) (string, interface{}, interface{}) {
+	return c.Column.Eq(value)
+}
+
+// Ne returns the `column <> value` condition.
+func (c Float32Column) Ne(value float32) (string, interface{}, interface{}) {
+	return c.Column.Ne(value)
+}
+
+// Gt returns the `column > value` condition.
+func (c Float32Column) Gt(value float32) (string, interface{}, interface{}) {
+	return c.Column.Gt(value)
+}
+
+// Ge returns the `column >= value` condition.
+func (c Float32Column) Ge(value float32) (string, interface{}, interface{}) {
+	return c.Column.Ge(value)
+}
+
+// Lt returns the `column < value` condition.
+func (c Float32Column) Lt(value float32) (string, interface{}, interface{}) {
+	return c.Column.Lt(value)
+}
+
+// Le returns the `column <= value` condition.
+func (c Float32Column) Le(value float32) (string, interface{}, interface{}) {
+	return c.Column.Le(value)
+}
+
+// In returns the `column IN (values)` condition.
+func (c Float32Column) In(values ...float32) (string, interface{}, interface{}) {
+	return "? IN (?)", c.Ident(), bun.In(values)
+}
+
+//------------------------------------------------------------------------------
+
+// Float64Column is a column of the float64 model field.
+type Float64Column struct {
+	Column
+}
+
+// As returns the column qualified with the table alias used by the query.
+func (c Float64Column) As(alias string) Float64Column {
+	return Float64Column{c.Column.As(alias)}
+}
+
+// Eq returns the `column = value` condition.
+func (c Float64Column) Eq(value float64) (string, interface{}, interface{}) {
+	return c.Column.Eq(value)
+}
+
+// Ne returns the `column <> value` condition.
+func (c Float64Column) Ne(value float64) (string, interface{}, interface{}) {
+	return c.Column.Ne(value)
+}
+
+// Gt returns the `column > value` condition.
+func (c Float64Column) Gt(value float64) (string, interface{}, interface{}) {
+	return c.Column.Gt(value)
+}
+
+// Ge returns the `column >= value` condition.
+func (c Float64Column) Ge(value float64) (string, interface{}, interface{}) {
+	return c.Column.Ge(value)
+}
+
+// Lt returns the `column < value` condition.
+func (c Float64Column) Lt(value float64) (string, interface{}, interface{}) {
+	return c.Column.Lt(value)
+}
+
+// Le returns the `column <= value` condition.
+func (c Float64Column) Le(value float64) (string, interface{}, interface{}) {
+	return c.Column.Le(value)
+}
+
+// In returns the `column IN (values)` condition.
+func (c Float64Column) In(values ...float64) (string, interface{}, interface{}) {
+	return "? IN (?)", c.Ident(), bun.In(values)
+}
+
+//------------------------------------------------------------------------------
+
+// TimeColumn is a column of the time.Time model field.
+type TimeColumn struct {
+	Column
+}
+
+// As returns the column qualified with the table alias used by the query.
+func (c TimeColumn) As(alias string) TimeColumn {
+	return TimeColumn{c.Column.As(alias)}
+}
+
+// Eq returns the `column = value` condition.
+func (c TimeColumn) Eq(value time.Time) (string, interface{}, interface{}) {
+	return c.Column.Eq(value)
+}
+
+// Ne returns the `column <> value` condition.
+func (c TimeColumn) Ne(value time.Time) (string, interface{}, interface{}) {
+	return c.Column.Ne(value)
+}
+
+// Gt returns the `column > value` condition.
+func (c TimeColumn) Gt(value time.Time) (string, interface{}, interface{}) {
+	return c.Column.Gt(value)
+}
+
+// Ge returns the `column >= value` condition.
+func (c TimeColumn) Ge(value time.Time) (string, interface{}, interface{}) {
+	return c.Column.Ge(value)
+}
+
+// Lt returns the `column < value` condition.
+func (c TimeColumn) Lt(value time.Time) (string, interface{}, interface{}) {
+	return c.Column.Lt(value)
+}
+
+// Le returns the `column <= value` condition.
+func (c TimeColumn) Le(value time.Time) (string, interface{}, interface{}) {
+	return c.Column.Le(value)
+}
+
+// In returns the `column IN (values)` condition.
+func (c TimeColumn) In(values ...time.Time) (string, interface{}, interface{}) {
+	return "? IN (?)", c.Ident(), bun.In(values)
+}
+
+//------------------------------------------------------------------------------
+
+// BytesColumn is a column of the []byte model field.
+type BytesColumn struct {
+	Column
+}
+
+// As returns the column qualified with the table alias used by the query.
+func (c BytesColumn) As(alias string) BytesColumn {
+	return BytesColumn{c.Column.As(alias)}
+}
+
+// Eq returns the `column = value` condition.
+func (c BytesColumn) Eq(value []byte) (string, interface{}, interface{}) {
+	return c.Column.Eq(value)
+}
+
+// Ne returns the `column <> value` condition.
+func (c BytesColumn) Ne(value []byte) (string, interface{}, interface{}) {
+	return c.Column.Ne(value)
+}
+
+// In returns the `column IN (values)` condition.
+func (c BytesColumn) In(values ...[]byte) (string, interface{}, interface{}) {
+	return "? IN (?)", c.Ident(), bun.In(values)
+}
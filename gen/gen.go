@@ -0,0 +1,167 @@
+// Package gen generates table and column constants for bun models, so queries can
+// reference columns without hardcoding identifiers, for example:
+//
+//	q.Where(models.UserCols.Email.Eq("hello@example.com")).Order(models.UserCols.ID.Name)
+//
+// The generator is a small program that is run with go:generate:
+//
+//	//go:generate go run ./gen
+//
+//	func main() {
+//		db := bun.NewDB(nil, pgdialect.New())
+//		if err := gen.WriteFile("models_gen.go", db, "models", (*User)(nil)); err != nil {
+//			panic(err)
+//		}
+//	}
+package gen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io"
+	"os"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/internal"
+	"github.com/uptrace/bun/schema"
+)
+
+// Generate writes the Go source of the package pkg with the table and column constants
+// of the models. For each model, for example, User, it declares UserTable and UserAlias
+// constants and UserCols variable with a typed column, for example, StringColumn,
+// for each model column.
+func Generate(w io.Writer, db *bun.DB, pkg string, models ...interface{}) error {
+	var buf bytes.Buffer
+
+	buf.WriteString("// Code generated by bun/gen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "package %s\n\n", pkg)
+	buf.WriteString("import \"github.com/uptrace/bun/gen\"\n")
+
+	seen := make(map[string]struct{}, len(models))
+	for _, model := range models {
+		typ := reflect.TypeOf(model)
+		for typ != nil && typ.Kind() == reflect.Ptr {
+			typ = typ.Elem()
+		}
+		if typ == nil || typ.Kind() != reflect.Struct {
+			return fmt.Errorf("bun: gen: got %T, wanted a struct or a struct pointer", model)
+		}
+
+		table := db.Table(typ)
+		if _, ok := seen[table.TypeName]; ok {
+			return fmt.Errorf("bun: gen: duplicate model %s", table.TypeName)
+		}
+		seen[table.TypeName] = struct{}{}
+
+		writeTable(&buf, table)
+	}
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(src)
+	return err
+}
+
+// WriteFile is like Generate, but writes the source to the named file.
+func WriteFile(filename string, db *bun.DB, pkg string, models ...interface{}) error {
+	var buf bytes.Buffer
+	if err := Generate(&buf, db, pkg, models...); err != nil {
+		return err
+	}
+	return os.WriteFile(filename, buf.Bytes(), 0o644)
+}
+
+func writeTable(buf *bytes.Buffer, table *schema.Table) {
+	name := table.TypeName
+
+	fmt.Fprintf(buf, "\n// %sTable is the name of the %s model table.\n", name, name)
+	fmt.Fprintf(buf, "const %sTable = %s\n", name, strconv.Quote(table.Name))
+	fmt.Fprintf(buf, "\n// %sAlias is the alias of the %s model table.\n", name, name)
+	fmt.Fprintf(buf, "const %sAlias = %s\n", name, strconv.Quote(table.Alias))
+
+	goNames := fieldNames(table.Fields)
+
+	fmt.Fprintf(buf, "\n// %sCols contains the %s model columns.\n", name, name)
+	fmt.Fprintf(buf, "var %sCols = struct {\n", name)
+	for i, goName := range goNames {
+		fmt.Fprintf(buf, "%s gen.%s\n", goName, columnType(table.Fields[i].IndirectType))
+	}
+	buf.WriteString("}{\n")
+	for i, f := range table.Fields {
+		col := fmt.Sprintf("gen.Column{Table: %s, Name: %s}",
+			strconv.Quote(table.Alias), strconv.Quote(f.Name))
+		if typ := columnType(f.IndirectType); typ != "Column" {
+			col = fmt.Sprintf("gen.%s{Column: %s}", typ, col)
+		}
+		fmt.Fprintf(buf, "%s: %s,\n", goNames[i], col)
+	}
+	buf.WriteString("}\n")
+}
+
+var (
+	timeType  = reflect.TypeOf((*time.Time)(nil)).Elem()
+	bytesType = reflect.TypeOf((*[]byte)(nil)).Elem()
+)
+
+// columnType returns the name of the gen column type for the field type.
+func columnType(typ reflect.Type) string {
+	switch typ {
+	case timeType:
+		return "TimeColumn"
+	case bytesType:
+		return "BytesColumn"
+	}
+	if typ.PkgPath() != "" {
+		// Named types, for example, enums, use the untyped column.
+		return "Column"
+	}
+	switch typ.Kind() {
+	case reflect.String:
+		return "StringColumn"
+	case reflect.Bool:
+		return "BoolColumn"
+	case reflect.Int:
+		return "IntColumn"
+	case reflect.Int32:
+		return "Int32Column"
+	case reflect.Int64:
+		return "Int64Column"
+	case reflect.Uint:
+		return "UintColumn"
+	case reflect.Uint32:
+		return "Uint32Column"
+	case reflect.Uint64:
+		return "Uint64Column"
+	case reflect.Float32:
+		return "Float32Column"
+	case reflect.Float64:
+		return "Float64Column"
+	}
+	return "Column"
+}
+
+// fieldNames returns the Go names of the fields. Fields with the same Go name,
+// for example, from structs embedded with different prefixes, use the column name instead.
+func fieldNames(fields []*schema.Field) []string {
+	counts := make(map[string]int, len(fields))
+	for _, f := range fields {
+		counts[f.GoName]++
+	}
+
+	names := make([]string, len(fields))
+	for i, f := range fields {
+		if counts[f.GoName] > 1 {
+			names[i] = internal.CamelCased(f.Name)
+		} else {
+			names[i] = f.GoName
+		}
+	}
+	return names
+}
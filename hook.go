@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"reflect"
+	"strings"
 	"sync/atomic"
 	"time"
 
@@ -21,9 +22,48 @@ type QueryEvent struct {
 	Result    sql.Result
 	Err       error
 
+	// Attempt is the number of the query retry, starting from 0.
+	// See WithRetryPolicy.
+	Attempt int
+
 	Stash map[interface{}]interface{}
 }
 
+// Args returns the args for the `?` placeholders of the query template returned by
+// `event.QueryAppender.AppendQuery(schema.NewNopFormatter(), nil)`,
+// for example, to log the parameterized query with the args separately.
+func (e *QueryEvent) Args() []interface{} {
+	if e.QueryAppender == nil {
+		return e.QueryArgs
+	}
+
+	var args []interface{}
+	if _, err := e.QueryAppender.AppendQuery(schema.NewNopFormatter().WithParams(&args), nil); err != nil {
+		return nil
+	}
+	return args
+}
+
+// Operation returns the query operation, for example, "SELECT" or "CREATE TABLE".
+func (e *QueryEvent) Operation() string {
+	if q, ok := e.QueryAppender.(Query); ok {
+		return q.Operation()
+	}
+	return queryOperation(e.Query)
+}
+
+// queryOperation returns the first word of the query, for example, "SELECT".
+func queryOperation(query string) string {
+	query = strings.TrimSpace(query)
+	if i := strings.IndexAny(query, " \t\n("); i > 0 {
+		query = query[:i]
+	}
+	if len(query) > 16 {
+		query = query[:16]
+	}
+	return strings.ToUpper(query)
+}
+
 type QueryHook interface {
 	BeforeQuery(context.Context, *QueryEvent) context.Context
 	AfterQuery(context.Context, *QueryEvent)
@@ -34,6 +74,16 @@ func (db *DB) beforeQuery(
 	queryApp schema.QueryAppender,
 	query string,
 	queryArgs []interface{},
+) (context.Context, *QueryEvent) {
+	return db.beforeQueryAttempt(ctx, queryApp, query, queryArgs, 0)
+}
+
+func (db *DB) beforeQueryAttempt(
+	ctx context.Context,
+	queryApp schema.QueryAppender,
+	query string,
+	queryArgs []interface{},
+	attempt int,
 ) (context.Context, *QueryEvent) {
 	atomic.AddUint64(&db.stats.Queries, 1)
 
@@ -49,6 +99,7 @@ func (db *DB) beforeQuery(
 		QueryArgs:     queryArgs,
 
 		StartTime: time.Now(),
+		Attempt:   attempt,
 	}
 
 	for _, hook := range db.queryHooks {
@@ -5,20 +5,28 @@ import (
 	"database/sql"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/uptrace/bun"
 	"github.com/uptrace/bun/dialect"
+	"github.com/uptrace/bun/dialect/feature"
 	"github.com/uptrace/bun/dialect/mysqldialect"
 	"github.com/uptrace/bun/dialect/pgdialect"
 	"github.com/uptrace/bun/dialect/sqlitedialect"
 	"github.com/uptrace/bun/driver/pgdriver"
 	"github.com/uptrace/bun/driver/sqliteshim"
 	"github.com/uptrace/bun/extra/bundebug"
+	"github.com/uptrace/bun/schema"
 
-	_ "github.com/go-sql-driver/mysql"
+	"github.com/go-sql-driver/mysql"
 	_ "github.com/jackc/pgx/v4/stdlib"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -79,7 +87,8 @@ func mysql8(tb testing.TB) *bun.DB {
 		assert.NoError(tb, sqldb.Close())
 	})
 
-	db := bun.NewDB(sqldb, mysqldialect.New())
+	loadData := mysqldialect.WithLoadData(mysql.RegisterReaderHandler, mysql.DeregisterReaderHandler)
+	db := bun.NewDB(sqldb, mysqldialect.New(loadData))
 	require.Equal(tb, "DB<dialect=mysql8>", db.String())
 	return db
 }
@@ -136,6 +145,23 @@ func TestDB(t *testing.T) {
 		{"testNilModel", testNilModel},
 		{"testSelectScan", testSelectScan},
 		{"testSelectCount", testSelectCount},
+		{"testSelectCountWrapperName", testSelectCountWrapperName},
+		{"testSelectViewColumns", testSelectViewColumns},
+		{"testSelectFromCTE", testSelectFromCTE},
+		{"testRequireIsolation", testRequireIsolation},
+		{"testLowercaseKeywords", testLowercaseKeywords},
+		{"testScanLocation", testScanLocation},
+		{"testInsertSliceIDs", testInsertSliceIDs},
+		{"testEmptyColumn", testEmptyColumn},
+		{"testDeleteWhereIn", testDeleteWhereIn},
+		{"testReturningFallback", testReturningFallback},
+		{"testReturningScan", testReturningScan},
+		{"testInsertDefaultValues", testInsertDefaultValues},
+		{"testSelectTotalCount", testSelectTotalCount},
+		{"testSelectCursor", testSelectCursor},
+		{"testUpsertExcludedColumns", testUpsertExcludedColumns},
+		{"testRenameColumn", testRenameColumn},
+		{"testSelectAggregate", testSelectAggregate},
 		{"testSelectMap", testSelectMap},
 		{"testSelectMapSlice", testSelectMapSlice},
 		{"testSelectStruct", testSelectStruct},
@@ -147,14 +173,38 @@ func TestDB(t *testing.T) {
 		{"testSelectJSONMap", testSelectJSONMap},
 		{"testSelectJSONStruct", testSelectJSONStruct},
 		{"testJSONSpecialChars", testJSONSpecialChars},
+		{"testJSONTag", testJSONTag},
+		{"testCustomType", testCustomType},
+		{"testExplain", testExplain},
+		{"testStmtCache", testStmtCache},
+		{"testCockroachFeatures", testCockroachFeatures},
+		{"testInsertCopy", testInsertCopy},
+		{"testInsertBatchSize", testInsertBatchSize},
+		{"testUpdateJoin", testUpdateJoin},
+		{"testNullZeroDefault", testNullZeroDefault},
+		{"testEmbedPrefix", testEmbedPrefix},
+		{"testExtendModel", testExtendModel},
+		{"testFKActions", testFKActions},
 		{"testSelectRawMessage", testSelectRawMessage},
 		{"testScanNullVar", testScanNullVar},
 		{"testScanSingleRow", testScanSingleRow},
 		{"testScanSingleRowByRow", testScanSingleRowByRow},
+		{"testScanModelRowByRow", testScanModelRowByRow},
 		{"testScanRows", testScanRows},
 		{"testRunInTx", testRunInTx},
 		{"testInsertIface", testInsertIface},
+		{"testSelectIface", testSelectIface},
 		{"testSelectBool", testSelectBool},
+		{"testConnNamedArg", testConnNamedArg},
+		{"testQueryNamedArg", testQueryNamedArg},
+		{"testWarnOnSelectStar", testWarnOnSelectStar},
+		{"testSelectColumnTypes", testSelectColumnTypes},
+		{"testRunInTxWithRetry", testRunInTxWithRetry},
+		{"testScanResultSets", testScanResultSets},
+		{"testRetryPolicy", testRetryPolicy},
+		{"testIDB", testIDB},
+		{"testNestedRunInTx", testNestedRunInTx},
+		{"testQueryTimeout", testQueryTimeout},
 	}
 
 	testEachDB(t, func(t *testing.T, db *bun.DB) {
@@ -208,431 +258,2056 @@ func testSelectCount(t *testing.T, db *bun.DB) {
 	require.Equal(t, 3, count)
 }
 
-func testSelectMap(t *testing.T, db *bun.DB) {
-	var m map[string]interface{}
-	err := db.NewSelect().
-		ColumnExpr("10 AS num").
-		Scan(ctx, &m)
-	require.NoError(t, err)
-	require.Equal(t, map[string]interface{}{
-		"num": int64(10),
-	}, m)
-}
-
-func testSelectMapSlice(t *testing.T, db *bun.DB) {
+func testSelectCountWrapperName(t *testing.T, db *bun.DB) {
 	if db.Dialect().Name() == dialect.MySQL5 {
 		t.Skip()
 	}
 
 	values := db.NewValues(&[]map[string]interface{}{
-		{"column1": 1},
-		{"column1": 2},
-		{"column1": 3},
+		{"num": 1},
+		{"num": 2},
+		{"num": 2},
 	})
 
-	var ms []map[string]interface{}
-	err := db.NewSelect().
-		With("t", values).
-		TableExpr("t").
-		Scan(ctx, &ms)
-	require.NoError(t, err)
-	require.Len(t, ms, 3)
-	for i, m := range ms {
-		require.Equal(t, map[string]interface{}{
-			"column1": int64(i + 1),
-		}, m)
+	db = bun.NewDB(db.DB, db.Dialect())
+
+	hook := &queryHook{}
+	db.AddQueryHook(hook)
+
+	hook.beforeQuery = func(ctx context.Context, event *bun.QueryEvent) context.Context {
+		require.Contains(t, event.Query, "WITH _count_wrapper_1 AS (")
+		require.True(t, strings.HasSuffix(event.Query, "SELECT count(*) FROM _count_wrapper_1"))
+		return ctx
 	}
+
+	count, err := db.NewSelect().
+		With("_count_wrapper", values).
+		TableExpr("_count_wrapper").
+		ColumnExpr("_count_wrapper.num").
+		Group("num").
+		Count(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 2, count)
+	hook.require(t)
 }
 
-func testSelectStruct(t *testing.T, db *bun.DB) {
-	type Model struct {
-		Num int
-		Str string
+func testLowercaseKeywords(t *testing.T, db *bun.DB) {
+	db = bun.NewDB(db.DB, db.Dialect(), bun.WithLowercaseKeywords())
+
+	hook := &queryHook{}
+	db.AddQueryHook(hook)
+
+	quote := string(db.Dialect().IdentQuote())
+	hook.beforeQuery = func(ctx context.Context, event *bun.QueryEvent) context.Context {
+		require.True(t, strings.HasPrefix(event.Query, "select 'SELECT 1 FROM dual', 1 AS "+quote+"WHERE"+quote))
+		require.True(t, strings.HasSuffix(event.Query, " where (1 IN (1)) limit 1"))
+		return ctx
 	}
 
-	model := new(Model)
+	var str string
+	var num int
 	err := db.NewSelect().
-		ColumnExpr("10 AS num, 'hello' as str").
-		Scan(ctx, model)
+		ColumnExpr("?", "SELECT 1 FROM dual").
+		ColumnExpr("1 AS ?", bun.Ident("WHERE")).
+		Where("1 IN (?)", bun.In([]int{1})).
+		Limit(1).
+		Scan(ctx, &str, &num)
 	require.NoError(t, err)
-	require.Equal(t, 10, model.Num)
-	require.Equal(t, "hello", model.Str)
-
-	err = db.NewSelect().TableExpr("(SELECT 42) AS t").Where("FALSE").Scan(ctx, model)
-	require.Equal(t, sql.ErrNoRows, err)
-
-	err = db.NewSelect().ColumnExpr("1 as unknown_column").Scan(ctx, model)
-	require.Error(t, err)
-	require.Contains(t, err.Error(), "Model does not have column")
+	require.Equal(t, "SELECT 1 FROM dual", str)
+	require.Equal(t, 1, num)
+	hook.require(t)
 }
 
-func testSelectNestedStructValue(t *testing.T, db *bun.DB) {
+func testScanLocation(t *testing.T, db *bun.DB) {
+	if db.Dialect().Name() == dialect.MySQL5 {
+		t.Skip()
+	}
+
 	type Model struct {
-		Num int
-		Sub struct {
-			Str string
-		}
+		ID      int64
+		Time    time.Time
+		TimePtr *time.Time
+		TimeUTC time.Time `bun:",location:UTC"`
 	}
 
+	loc := time.FixedZone("UTC+3", 3*3600)
+	db = bun.NewDB(db.DB, db.Dialect(), bun.WithScanLocation(loc))
+
+	tm := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+	values := db.NewValues(&[]Model{{ID: 1, Time: tm, TimePtr: &tm, TimeUTC: tm}})
+
 	model := new(Model)
 	err := db.NewSelect().
-		ColumnExpr("10 AS num, 'hello' as sub__str").
-		Scan(ctx, model)
+		With("v", values).
+		Model(model).
+		FromCTE("v").
+		Scan(ctx)
 	require.NoError(t, err)
-	require.Equal(t, 10, model.Num)
-	require.Equal(t, "hello", model.Sub.Str)
-}
 
-func testSelectNestedStructPtr(t *testing.T, db *bun.DB) {
-	type Sub struct {
-		Str string
-	}
+	require.Equal(t, loc, model.Time.Location())
+	require.True(t, model.Time.Equal(tm))
+	require.NotNil(t, model.TimePtr)
+	require.Equal(t, loc, model.TimePtr.Location())
+	require.True(t, model.TimePtr.Equal(tm))
+	require.Equal(t, time.UTC, model.TimeUTC.Location())
+	require.True(t, model.TimeUTC.Equal(tm))
+}
 
+func testSelectViewColumns(t *testing.T, db *bun.DB) {
 	type Model struct {
-		Num int
-		Sub *Sub
+		ID  int64
+		Str string
 	}
 
-	model := new(Model)
+	models := make([]Model, 0)
 	err := db.NewSelect().
-		ColumnExpr("10 AS num").
-		Scan(ctx, model)
+		Model(&models).
+		ModelTableExpr("(SELECT 1 AS model_id, 'hello' AS label) AS model").
+		ViewColumns(map[string]string{"id": "model_id", "str": "label"}).
+		Scan(ctx)
 	require.NoError(t, err)
-	require.Equal(t, 10, model.Num)
-	require.Nil(t, model.Sub)
+	require.Equal(t, []Model{{ID: 1, Str: "hello"}}, models)
 
-	model = new(Model)
+	model := new(Model)
 	err = db.NewSelect().
-		ColumnExpr("10 AS num, 'hello' AS sub__str").
-		Scan(ctx, model)
+		Model(model).
+		ModelTableExpr("(SELECT 1 AS id, 'hello' AS label) AS model").
+		Column("str").
+		ViewColumns(map[string]string{"str": "label"}).
+		Scan(ctx)
 	require.NoError(t, err)
-	require.Equal(t, 10, model.Num)
-	require.Equal(t, "hello", model.Sub.Str)
+	require.Equal(t, "hello", model.Str)
 
-	model = new(Model)
 	err = db.NewSelect().
-		ColumnExpr("10 AS num, NULL AS sub__str").
-		Scan(ctx, model)
-	require.NoError(t, err)
-	require.Equal(t, 10, model.Num)
-	require.Nil(t, model.Sub)
+		Model(model).
+		ViewColumns(map[string]string{"unknown": "label"}).
+		Scan(ctx)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "does not have column=unknown")
 }
 
-func testSelectStructSlice(t *testing.T, db *bun.DB) {
-	if db.Dialect().Name() == dialect.MySQL5 {
-		t.Skip()
-	}
-
+func testEmptyColumn(t *testing.T, db *bun.DB) {
 	type Model struct {
-		Num int `bun:"column1"`
+		ID  int64
+		Str string
 	}
 
-	values := db.NewValues(&[]map[string]interface{}{
-		{"column1": 1},
-		{"column1": 2},
-		{"column1": 3},
-	})
+	var num int
+	err := db.NewSelect().ColumnExpr(" ").Scan(ctx, &num)
+	require.EqualError(t, err, "bun: column name or expression is empty")
 
-	models := make([]Model, 0)
-	err := db.NewSelect().
-		With("t", values).
-		TableExpr("t").
-		Scan(ctx, &models)
-	require.NoError(t, err)
-	require.Len(t, models, 3)
-	for i, model := range models {
-		require.Equal(t, i+1, model.Num)
-	}
+	_, err = db.NewUpdate().Model(&Model{ID: 1}).Column("str", "").WherePK().Exec(ctx)
+	require.EqualError(t, err, "bun: column name or expression is empty")
 }
 
-func testSelectSingleSlice(t *testing.T, db *bun.DB) {
-	if db.Dialect().Name() == dialect.MySQL5 {
-		t.Skip()
+func testDeleteWhereIn(t *testing.T, db *bun.DB) {
+	type Model struct {
+		ID int64
 	}
 
-	values := db.NewValues(&[]map[string]interface{}{
-		{"column1": 1},
-		{"column1": 2},
-		{"column1": 3},
-	})
+	type Staging struct {
+		ID  int64
+		Str string
+	}
 
-	var ns []int
-	err := db.NewSelect().
-		With("t", values).
-		TableExpr("t").
-		Scan(ctx, &ns)
+	err := db.ResetModel(ctx, (*Model)(nil), (*Staging)(nil))
 	require.NoError(t, err)
-	require.Equal(t, []int{1, 2, 3}, ns)
-}
 
-func testSelectMultiSlice(t *testing.T, db *bun.DB) {
-	if db.Dialect().Name() == dialect.MySQL5 {
-		t.Skip()
-	}
+	models := []Model{{ID: 1}, {ID: 2}, {ID: 3}}
+	_, err = db.NewInsert().Model(&models).Exec(ctx)
+	require.NoError(t, err)
 
-	values := db.NewValues(&[]map[string]interface{}{
-		{"a": 1, "b": "foo"},
-		{"a": 2, "b": "bar"},
-		{"a": 3, "b": ""},
-	})
+	staging := []Staging{{ID: 1, Str: "a"}, {ID: 2, Str: "b"}, {ID: 3, Str: "b"}}
+	_, err = db.NewInsert().Model(&staging).Exec(ctx)
+	require.NoError(t, err)
 
-	var ns []int
-	var ss []string
-	err := db.NewSelect().
-		With("t", values).
-		TableExpr("t").
-		Scan(ctx, &ns, &ss)
+	subq := db.NewSelect().Model((*Staging)(nil)).Column("id").Where("str = ?", "b")
+	res, err := db.NewDelete().
+		Model((*Model)(nil)).
+		WhereIn("id", subq).
+		Where("id <> ?", 3).
+		Exec(ctx)
 	require.NoError(t, err)
-	require.Equal(t, []int{1, 2, 3}, ns)
-	require.Equal(t, []string{"foo", "bar", ""}, ss)
+
+	n, err := res.RowsAffected()
+	require.NoError(t, err)
+	require.Equal(t, int64(1), n)
+
+	var ids []int64
+	err = db.NewSelect().Model((*Model)(nil)).Column("id").OrderExpr("id ASC").Scan(ctx, &ids)
+	require.NoError(t, err)
+	require.Equal(t, []int64{1, 3}, ids)
 }
 
-func testSelectJSONMap(t *testing.T, db *bun.DB) {
+func testReturningFallback(t *testing.T, db *bun.DB) {
 	type Model struct {
-		Map map[string]string
+		ID  int64 `bun:",pk,autoincrement"`
+		Str string
+		Num int64 `bun:",nullzero,notnull,default:42"`
 	}
 
-	model := new(Model)
-	err := db.NewSelect().
-		ColumnExpr("? AS map", map[string]string{"hello": "world"}).
-		Scan(ctx, model)
-	require.NoError(t, err)
-	require.Equal(t, map[string]string{"hello": "world"}, model.Map)
+	// Postgres does not support LastInsertId that is required to select the inserted rows.
+	if db.Dialect().Name() == dialect.PG {
+		t.Skip()
+	}
 
-	err = db.NewSelect().
-		ColumnExpr("NULL AS map").
-		Scan(ctx, model)
+	db = bun.NewDB(db.DB, noReturningDialect{db.Dialect()})
+
+	err := db.ResetModel(ctx, (*Model)(nil))
 	require.NoError(t, err)
-	require.Equal(t, map[string]string(nil), model.Map)
-}
 
-func testSelectJSONStruct(t *testing.T, db *bun.DB) {
-	type Struct struct {
-		Hello string
+	model := &Model{Str: "foo"}
+	_, err = db.NewInsert().Model(model).Returning("*").Exec(ctx)
+	require.NoError(t, err)
+	require.NotZero(t, model.ID)
+	require.Equal(t, int64(42), model.Num)
+
+	// SQLite LastInsertId returns the id of the last inserted row instead of the first one.
+	if db.Dialect().Name() != dialect.SQLite {
+		models := []Model{{Str: "bar"}, {Str: "baz"}}
+		err = db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+			_, err := tx.NewInsert().Model(&models).Returning("num").Exec(ctx)
+			return err
+		})
+		require.NoError(t, err)
+		require.Equal(t, int64(42), models[0].Num)
+		require.Equal(t, int64(42), models[1].Num)
 	}
 
-	type Model struct {
-		Struct Struct
+	_, err = db.NewUpdate().Model(model).Set("str = ?", "qux").WherePK().Returning("*").Exec(ctx)
+	require.EqualError(t, err, fmt.Sprintf("bun: RETURNING is not supported by %s", db.Dialect().Name()))
+
+	type Unique struct {
+		ID  int64  `bun:",pk,autoincrement"`
+		Str string `bun:",unique"`
+		Num int64  `bun:",nullzero,notnull,default:42"`
 	}
 
-	model := new(Model)
-	err := db.NewSelect().
-		ColumnExpr("? AS struct", Struct{Hello: "world"}).
-		Scan(ctx, model)
+	err = db.ResetModel(ctx, (*Unique)(nil))
 	require.NoError(t, err)
-	require.Equal(t, Struct{Hello: "world"}, model.Struct)
 
-	err = db.NewSelect().
-		ColumnExpr("NULL AS struct").
-		Scan(ctx, model)
+	_, err = db.NewInsert().Model(&Unique{Str: "foo"}).Exec(ctx)
 	require.NoError(t, err)
-	require.Equal(t, Struct{}, model.Struct)
+
+	// The ids of the inserted rows are unknown when some rows are ignored.
+	uniques := []Unique{{Str: "foo"}, {Str: "bar"}}
+	q := db.NewInsert().Model(&uniques).Returning("num")
+	if db.Dialect().Features().Has(feature.OnDuplicateKey) {
+		q = q.Ignore()
+	} else {
+		q = q.On("CONFLICT DO NOTHING")
+	}
+	_, err = q.Exec(ctx)
+	require.EqualError(t, err, "bun: RETURNING emulation requires the primary keys of the inserted rows")
 }
 
-func testSelectRawMessage(t *testing.T, db *bun.DB) {
+func testReturningScan(t *testing.T, db *bun.DB) {
+	if !db.Dialect().Features().Has(feature.Returning) {
+		t.Skip()
+	}
+
 	type Model struct {
-		Raw json.RawMessage
+		ID  int64 `bun:",pk,autoincrement"`
+		Str string
+		Num int64 `bun:",nullzero,notnull,default:42"`
 	}
 
-	model := new(Model)
-	err := db.NewSelect().
-		ColumnExpr("? AS raw", map[string]string{"hello": "world"}).
-		Scan(ctx, model)
+	err := db.ResetModel(ctx, (*Model)(nil))
 	require.NoError(t, err)
-	require.Equal(t, `{"hello":"world"}`, string(model.Raw))
 
-	err = db.NewSelect().
-		ColumnExpr("NULL AS raw").
-		Scan(ctx, model)
+	models := []Model{{Str: "foo"}, {Str: "bar"}}
+	_, err = db.NewInsert().Model(&models).Returning("*").Exec(ctx)
 	require.NoError(t, err)
-	require.Nil(t, model.Raw)
-}
+	require.NotZero(t, models[0].ID)
+	require.NotEqual(t, models[0].ID, models[1].ID)
+	require.Equal(t, int64(42), models[1].Num)
+
+	model := &Model{ID: models[0].ID}
+	_, err = db.NewUpdate().
+		Model(model).
+		Set("num = num + 1").
+		WherePK().
+		Returning("str, num").
+		Exec(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "foo", model.Str)
+	require.Equal(t, int64(43), model.Num)
 
-func testScanNullVar(t *testing.T, db *bun.DB) {
-	num := int(42)
-	err := db.NewSelect().ColumnExpr("NULL").Scan(ctx, &num)
+	model = &Model{ID: models[1].ID}
+	_, err = db.NewDelete().Model(model).WherePK().Returning("*").Exec(ctx)
 	require.NoError(t, err)
-	require.Zero(t, num)
+	require.Equal(t, "bar", model.Str)
 }
 
-func testScanSingleRow(t *testing.T, db *bun.DB) {
-	rows, err := db.QueryContext(ctx, "SELECT 42")
-	require.NoError(t, err)
-	defer rows.Close()
+// noReturningDialect emulates a dialect without RETURNING support.
+type noReturningDialect struct {
+	schema.Dialect
+}
 
-	if !rows.Next() {
-		t.Fail()
-	}
+func (d noReturningDialect) Init(*sql.DB) {}
 
-	var num int
-	err = db.ScanRow(ctx, rows, &num)
-	require.NoError(t, err)
-	require.Equal(t, 42, num)
+func (d noReturningDialect) Features() feature.Feature {
+	return d.Dialect.Features() &^ feature.Returning
 }
 
-func testScanSingleRowByRow(t *testing.T, db *bun.DB) {
-	if db.Dialect().Name() == dialect.MySQL5 {
-		t.Skip()
-	}
+var errCockroachRestart = errors.New("restart transaction")
 
-	values := db.NewValues(&[]map[string]interface{}{
-		{"num": 1},
-		{"num": 2},
-		{"num": 3},
-	})
+// cockroachDialect emulates the CockroachDB features on top of the dialect.
+type cockroachDialect struct {
+	schema.Dialect
+}
 
-	rows, err := db.NewSelect().
-		With("t", values).
-		TableExpr("t").
-		OrderExpr("t.num DESC").
-		Rows(ctx)
-	require.NoError(t, err)
-	defer rows.Close()
+func (d cockroachDialect) Init(*sql.DB) {}
 
-	var nums []int
+func (d cockroachDialect) Features() feature.Feature {
+	return d.Dialect.Features() |
+		feature.AsOfSystemTime |
+		feature.ReturningNothing |
+		feature.SavepointRetry
+}
+
+func (d cockroachDialect) IsRetryableError(err error) bool {
+	return errors.Is(err, errCockroachRestart)
+}
+
+func testCockroachFeatures(t *testing.T, db *bun.DB) {
+	type Model struct {
+		ID  int64 `bun:",pk,autoincrement"`
+		Str string
+	}
+
+	err := db.NewSelect().Model((*Model)(nil)).AsOfSystemTime("'-10s'").Scan(ctx)
+	require.Error(t, err)
+
+	crdb := bun.NewDB(db.DB, cockroachDialect{db.Dialect()})
+
+	b, err := crdb.NewSelect().
+		Model((*Model)(nil)).
+		AsOfSystemTime("?", "-10s").
+		Where("id = 1").
+		AppendQuery(crdb.Formatter(), nil)
+	require.NoError(t, err)
+	require.Contains(t, string(b), ` AS OF SYSTEM TIME '-10s' WHERE (id = 1)`)
+
+	b, err = crdb.NewInsert().
+		Model(&Model{Str: "foo"}).
+		Returning("NOTHING").
+		AppendQuery(crdb.Formatter(), nil)
+	require.NoError(t, err)
+	require.True(t, strings.HasSuffix(string(b), " RETURNING NOTHING"), string(b))
+
+	b, err = db.NewInsert().
+		Model(&Model{Str: "foo"}).
+		Returning("NOTHING").
+		AppendQuery(db.Formatter(), nil)
+	require.NoError(t, err)
+	require.NotContains(t, string(b), "RETURNING")
+
+	err = db.ResetModel(ctx, (*Model)(nil))
+	require.NoError(t, err)
+
+	var attempts int
+	err = crdb.RunInTxWithRetry(ctx, nil, 3, func(ctx context.Context, tx bun.Tx) error {
+		attempts++
+		if _, err := tx.NewInsert().Model(&Model{Str: "foo"}).Exec(ctx); err != nil {
+			return err
+		}
+		if attempts < 2 {
+			return errCockroachRestart
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, 2, attempts)
+
+	count, err := db.NewSelect().Model((*Model)(nil)).Count(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+}
+
+func testInsertCopy(t *testing.T, db *bun.DB) {
+	type Model struct {
+		ID   int64 `bun:",pk,autoincrement"`
+		Str  string
+		Flag bool
+		Ptr  *string
+	}
+
+	err := db.ResetModel(ctx, (*Model)(nil))
+	require.NoError(t, err)
+
+	// Postgres uses COPY, MySQL 8 uses LOAD DATA, and other dialects use INSERT.
+	str := "it's a \\ \t test"
+	models := []Model{{Str: str, Flag: true}, {Str: "bar", Ptr: &str}}
+	_, err = db.NewInsert().Model(&models).CopyOrInsert().Exec(ctx)
+	require.NoError(t, err)
+
+	var got []Model
+	err = db.NewSelect().Model(&got).Order("id ASC").Scan(ctx)
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	require.Equal(t, str, got[0].Str)
+	require.True(t, got[0].Flag)
+	require.Nil(t, got[0].Ptr)
+	require.False(t, got[1].Flag)
+	require.Equal(t, str, *got[1].Ptr)
+
+	features := db.Dialect().Features()
+	if !features.Has(feature.CopyFrom) && !features.Has(feature.LoadData) {
+		_, err = db.NewInsert().Model(&models).Copy().Exec(ctx)
+		require.EqualError(t, err,
+			fmt.Sprintf("bun: COPY is not supported by %s", db.Dialect().Name()))
+
+		_, err = db.NewCopyFrom().Model((*Model)(nil)).Exec(ctx, strings.NewReader(""))
+		require.Error(t, err)
+	}
+}
+
+func testInsertBatchSize(t *testing.T, db *bun.DB) {
+	type Model struct {
+		ID  int64 `bun:",pk,autoincrement"`
+		Str string
+	}
+
+	err := db.ResetModel(ctx, (*Model)(nil))
+	require.NoError(t, err)
+
+	db = bun.NewDB(db.DB, db.Dialect())
+
+	var queries int
+	db.AddQueryHook(&queryHook{
+		beforeQuery: func(ctx context.Context, event *bun.QueryEvent) context.Context {
+			queries++
+			return ctx
+		},
+	})
+
+	models := make([]*Model, 5)
+	for i := range models {
+		models[i] = &Model{Str: strconv.Itoa(i)}
+	}
+
+	res, err := db.NewInsert().Model(&models).BatchSize(2).Exec(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 3, queries)
+
+	n, err := res.RowsAffected()
+	require.NoError(t, err)
+	require.Equal(t, int64(5), n)
+	require.Len(t, models, 5)
+
+	if !db.Dialect().Features().Has(feature.Returning) {
+		id, err := res.LastInsertId()
+		require.NoError(t, err)
+		require.NotZero(t, id)
+	}
+
+	if db.Dialect().Features().Has(feature.Returning) {
+		for i, model := range models {
+			require.NotZero(t, model.ID)
+			require.Equal(t, strconv.Itoa(i), model.Str)
+		}
+	}
+
+	values := []Model{{Str: "a"}, {Str: "b"}, {Str: "c"}}
+	_, err = db.NewInsert().Model(&values).BatchSize(2).Exec(ctx)
+	require.NoError(t, err)
+	if db.Dialect().Features().Has(feature.Returning) {
+		require.NotZero(t, values[2].ID)
+		require.Equal(t, "c", values[2].Str)
+	}
+
+	count, err := db.NewSelect().Model((*Model)(nil)).Count(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 8, count)
+
+	// The second batch fails, so the first one is rolled back too.
+	dups := []Model{{ID: 100, Str: "x"}, {ID: 101, Str: "y"}, {ID: 100, Str: "z"}}
+	_, err = db.NewInsert().Model(&dups).BatchSize(2).Exec(ctx)
+	require.Error(t, err)
+
+	count, err = db.NewSelect().Model((*Model)(nil)).Count(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 8, count)
+}
+
+func testUpdateJoin(t *testing.T, db *bun.DB) {
+	type Author struct {
+		bun.BaseModel `bun:"update_authors,alias:a"`
+		ID            int64 `bun:",pk"`
+		Name          string
+	}
+	type Book struct {
+		bun.BaseModel `bun:"update_books,alias:book"`
+		ID            int64 `bun:",pk"`
+		AuthorID      int64
+		Title         string
+	}
+
+	err := db.ResetModel(ctx, (*Author)(nil), (*Book)(nil))
+	require.NoError(t, err)
+
+	_, err = db.NewInsert().Model(&[]Author{{ID: 1, Name: "one"}, {ID: 2, Name: "two"}}).Exec(ctx)
+	require.NoError(t, err)
+	books := []Book{{ID: 10, AuthorID: 1}, {ID: 20, AuthorID: 2}, {ID: 30, AuthorID: 1}}
+	_, err = db.NewInsert().Model(&books).Exec(ctx)
+	require.NoError(t, err)
+
+	// The join is rewritten as FROM on dialects without the multi-table UPDATE.
+	res, err := db.NewUpdate().
+		Model((*Book)(nil)).
+		Set("title = a.name").
+		Join("JOIN update_authors AS a").
+		JoinOn("a.id = book.author_id").
+		Where("book.id IN (?)", bun.In([]int64{10, 20})).
+		Exec(ctx)
+	require.NoError(t, err)
+	n, err := res.RowsAffected()
+	require.NoError(t, err)
+	require.Equal(t, int64(2), n)
+
+	res, err = db.NewUpdate().
+		Model((*Book)(nil)).
+		Set("title = a.name").
+		TableExpr("update_books AS b2").
+		Join("JOIN update_authors AS a").
+		JoinOn("a.id = b2.author_id").
+		Where("b2.id = book.id").
+		Where("book.id = ?", 30).
+		Exec(ctx)
+	require.NoError(t, err)
+	n, err = res.RowsAffected()
+	require.NoError(t, err)
+	require.Equal(t, int64(1), n)
+
+	var titles []string
+	err = db.NewSelect().Model((*Book)(nil)).Column("title").OrderExpr("id ASC").Scan(ctx, &titles)
+	require.NoError(t, err)
+	require.Equal(t, []string{"one", "two", "one"}, titles)
+}
+
+func testNullZeroDefault(t *testing.T, db *bun.DB) {
+	type Model struct {
+		ID    int64  `bun:",pk,autoincrement"`
+		Str   string `bun:",nullzero,notnull,default:'hello'"`
+		Num   int    `bun:",nullzero,notnull,default:42"`
+		Note  string `bun:",nullzero"`
+		Value interface{}
+	}
+
+	b, err := db.NewCreateTable().Model((*Model)(nil)).AppendQuery(db.Formatter(), nil)
+	require.NoError(t, err)
+	require.Contains(t, string(b), "DEFAULT 'hello'")
+	require.Contains(t, string(b), "DEFAULT 42")
+
+	err = db.ResetModel(ctx, (*Model)(nil))
+	require.NoError(t, err)
+
+	model := new(Model)
+	_, err = db.NewInsert().Model(model).Exec(ctx)
+	require.NoError(t, err)
+
+	model = &Model{ID: model.ID, Note: "note", Value: "value"}
+	err = db.NewSelect().
+		Model(model).
+		Column("id", "str", "num").
+		ColumnExpr("NULL AS note, NULL AS value").
+		WherePK().
+		Scan(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "hello", model.Str)
+	require.Equal(t, 42, model.Num)
+	require.Equal(t, "", model.Note)
+	require.Nil(t, model.Value)
+}
+
+func testEmbedPrefix(t *testing.T, db *bun.DB) {
+	type Address struct {
+		Street string
+		City   string
+	}
+
+	type Model struct {
+		ID       int64    `bun:",pk"`
+		Billing  Address  `bun:"embed:billing_"`
+		Shipping *Address `bun:"embed:shipping_"`
+	}
+
+	table := db.Dialect().Tables().Get(reflect.TypeOf((*Model)(nil)).Elem())
+	for _, name := range []string{"billing_street", "billing_city", "shipping_street", "shipping_city"} {
+		require.True(t, table.HasField(name), name)
+	}
+
+	err := db.ResetModel(ctx, (*Model)(nil))
+	require.NoError(t, err)
+
+	models := []Model{
+		{ID: 1, Billing: Address{Street: "Main St", City: "Berlin"}},
+		{ID: 2, Billing: Address{City: "Paris"}, Shipping: &Address{Street: "Rue", City: "Lyon"}},
+	}
+	_, err = db.NewInsert().Model(&models).Exec(ctx)
+	require.NoError(t, err)
+
+	model := new(Model)
+	err = db.NewSelect().Model(model).Where("billing_city = ?", "Paris").Scan(ctx)
+	require.NoError(t, err)
+	require.Equal(t, models[1], *model)
+
+	var cities []string
+	err = db.NewSelect().
+		Model((*Model)(nil)).
+		Column("billing_city").
+		Order("id").
+		Scan(ctx, &cities)
+	require.NoError(t, err)
+	require.Equal(t, []string{"Berlin", "Paris"}, cities)
+}
+
+type ExtendBase struct {
+	ID     int64 `bun:",pk"`
+	Name   string
+	Secret string
+}
+
+func testExtendModel(t *testing.T, db *bun.DB) {
+	type TenantModel struct {
+		bun.BaseModel `bun:"tenant_models,alias:tm"`
+		ExtendBase    `bun:",extend"`
+
+		Secret string `bun:"-"`
+		Extra  int
+	}
+
+	type BaseWithCount struct {
+		ExtendBase `bun:",extend"`
+
+		Count int
+	}
+
+	tables := db.Dialect().Tables()
+
+	table := tables.Get(reflect.TypeOf((*BaseWithCount)(nil)).Elem())
+	require.Equal(t, "extend_bases", table.Name)
+	require.True(t, table.HasField("count"))
+
+	table = tables.Get(reflect.TypeOf((*TenantModel)(nil)).Elem())
+	require.Equal(t, "tenant_models", table.Name)
+	require.Equal(t, "tm", table.Alias)
+
+	var columns []string
+	for _, f := range table.Fields {
+		columns = append(columns, f.Name)
+	}
+	require.Equal(t, []string{"id", "name", "extra"}, columns)
+
+	err := db.ResetModel(ctx, (*TenantModel)(nil))
+	require.NoError(t, err)
+
+	model := &TenantModel{ExtendBase: ExtendBase{ID: 1, Name: "hello"}, Extra: 42}
+	_, err = db.NewInsert().Model(model).Exec(ctx)
+	require.NoError(t, err)
+
+	got := new(TenantModel)
+	err = db.NewSelect().Model(got).Where("tm.id = ?", 1).Scan(ctx)
+	require.NoError(t, err)
+	require.Equal(t, model, got)
+}
+
+func testFKActions(t *testing.T, db *bun.DB) {
+	type FKAuthor struct {
+		ID int64
+	}
+	type FKBook struct {
+		ID       int64
+		AuthorID int64
+		Author   *FKAuthor `bun:"rel:belongs-to,on_delete:set  null,on_update:cascade"`
+	}
+	type FKInvalid struct {
+		ID       int64
+		AuthorID int64
+		Author   *FKAuthor `bun:"rel:belongs-to,on_delete:drop table"`
+	}
+
+	tables := db.Dialect().Tables()
+
+	table := tables.Get(reflect.TypeOf((*FKBook)(nil)).Elem())
+	require.Equal(t, "SET NULL", table.FieldMap["author"].OnDelete)
+	require.Equal(t, "CASCADE", table.FieldMap["author"].OnUpdate)
+
+	require.PanicsWithError(t, `bun: FKInvalid.Author has invalid on_delete="drop table"`, func() {
+		tables.Get(reflect.TypeOf((*FKInvalid)(nil)).Elem())
+	})
+}
+
+func testInsertDefaultValues(t *testing.T, db *bun.DB) {
+	type Model struct {
+		ID int64 `bun:",pk,autoincrement"`
+	}
+
+	err := db.ResetModel(ctx, (*Model)(nil))
+	require.NoError(t, err)
+
+	model := new(Model)
+	_, err = db.NewInsert().Model(model).Exec(ctx)
+	require.NoError(t, err)
+	require.NotZero(t, model.ID)
+
+	count, err := db.NewSelect().Model((*Model)(nil)).Count(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+}
+
+func testSelectTotalCount(t *testing.T, db *bun.DB) {
+	if db.Dialect().Name() == dialect.MySQL5 {
+		t.Skip()
+	}
+
+	type Model struct {
+		ID int64
+	}
+
+	err := db.ResetModel(ctx, (*Model)(nil))
+	require.NoError(t, err)
+
+	models := []Model{{ID: 1}, {ID: 2}, {ID: 3}, {ID: 4}, {ID: 5}}
+	_, err = db.NewInsert().Model(&models).Exec(ctx)
+	require.NoError(t, err)
+
+	models = nil
+	q := db.NewSelect().
+		Model(&models).
+		WithTotalCount("total_count").
+		Order("id").
+		Limit(2).
+		Offset(1)
+	err = q.Scan(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []Model{{ID: 2}, {ID: 3}}, models)
+	require.Equal(t, 5, q.TotalCount())
+}
+
+func testSelectCursor(t *testing.T, db *bun.DB) {
+	type Model struct {
+		ID  int64
+		Num int
+	}
+
+	err := db.ResetModel(ctx, (*Model)(nil))
+	require.NoError(t, err)
+
+	models := []Model{{ID: 1, Num: 20}, {ID: 2, Num: 10}, {ID: 3, Num: 20}, {ID: 4, Num: 30}}
+	_, err = db.NewInsert().Model(&models).Exec(ctx)
+	require.NoError(t, err)
+
+	var ids []int64
+	var cursor string
+	for {
+		var page []Model
+		next, err := db.NewSelect().
+			Model(&page).
+			Cursor("num", "desc").
+			CursorAfter(cursor).
+			Limit(3).
+			ScanCursor(ctx)
+		require.NoError(t, err)
+
+		for _, m := range page {
+			ids = append(ids, m.ID)
+		}
+
+		cursor = next
+		if cursor == "" {
+			break
+		}
+	}
+	require.Equal(t, []int64{4, 3, 1, 2}, ids)
+
+	type NullModel struct {
+		ID  int64
+		Num *int
+	}
+
+	_, err = db.NewSelect().
+		Model(new([]NullModel)).
+		Cursor("num", "asc").
+		ScanCursor(ctx)
+	require.EqualError(t, err, `bun: Cursor requires NOT NULL column, got nullable "num"`)
+}
+
+func testUpsertExcludedColumns(t *testing.T, db *bun.DB) {
+	type Model struct {
+		ID  int64 `bun:",pk"`
+		Str string
+		Num int
+	}
+
+	err := db.ResetModel(ctx, (*Model)(nil))
+	require.NoError(t, err)
+
+	models := []Model{{ID: 1, Str: "a", Num: 1}, {ID: 2, Str: "b", Num: 2}}
+	_, err = db.NewInsert().Model(&models).Exec(ctx)
+	require.NoError(t, err)
+
+	q := db.NewInsert().Model(&[]Model{{ID: 2, Str: "c", Num: 3}, {ID: 3, Str: "d", Num: 4}})
+	if db.Dialect().Features().Has(feature.OnDuplicateKey) {
+		q = q.On("DUPLICATE KEY UPDATE")
+	} else {
+		q = q.On("CONFLICT (id) DO UPDATE")
+	}
+	_, err = q.SetExcludedColumns().Exec(ctx)
+	require.NoError(t, err)
+
+	models = nil
+	err = db.NewSelect().Model(&models).Order("id").Scan(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []Model{
+		{ID: 1, Str: "a", Num: 1},
+		{ID: 2, Str: "c", Num: 3},
+		{ID: 3, Str: "d", Num: 4},
+	}, models)
+}
+
+func testRenameColumn(t *testing.T, db *bun.DB) {
+	type Model struct {
+		ID  int64
+		Str string
+	}
+
+	type Renamed struct {
+		bun.BaseModel `bun:"models"`
+
+		ID    int64
+		Title string
+	}
+
+	err := db.ResetModel(ctx, (*Model)(nil))
+	require.NoError(t, err)
+
+	_, err = db.NewInsert().Model(&Model{ID: 1, Str: "hello"}).Exec(ctx)
+	require.NoError(t, err)
+
+	_, err = db.NewRenameColumn().Model((*Model)(nil)).Rename("str", "title").Exec(ctx)
+	require.NoError(t, err)
+
+	renamed := new(Renamed)
+	err = db.NewSelect().Model(renamed).Where("id = ?", 1).Scan(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "hello", renamed.Title)
+}
+
+func testSelectFromCTE(t *testing.T, db *bun.DB) {
+	if db.Dialect().Name() == dialect.MySQL5 {
+		t.Skip()
+	}
+
+	type Model struct {
+		ID  int64
+		Str string
+	}
+
+	values := db.NewValues(&[]Model{{ID: 1, Str: "foo"}, {ID: 2, Str: "bar"}})
+
+	models := make([]Model, 0)
+	err := db.NewSelect().
+		With("recent", values).
+		Model(&models).
+		FromCTE("recent").
+		Where("model.id > ?", 1).
+		Scan(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []Model{{ID: 2, Str: "bar"}}, models)
+
+	var ids []int64
+	err = db.NewSelect().
+		With("recent", values).
+		FromCTE("recent").
+		ColumnExpr("id").
+		OrderExpr("id ASC").
+		Scan(ctx, &ids)
+	require.NoError(t, err)
+	require.Equal(t, []int64{1, 2}, ids)
+
+	err = db.NewSelect().FromCTE("missing").ColumnExpr("1").Scan(ctx, &ids)
+	require.EqualError(t, err, `bun: FromCTE("missing") requires With("missing", ...)`)
+}
+
+func testRequireIsolation(t *testing.T, db *bun.DB) {
+	var num int
+	err := db.NewSelect().
+		ColumnExpr("1").
+		RequireIsolation(sql.LevelSerializable).
+		Scan(ctx, &num)
+	require.EqualError(t, err,
+		"bun: query requires a transaction with Serializable isolation level")
+
+	// The default isolation level depends on the database settings.
+	err = db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		return tx.NewSelect().
+			ColumnExpr("1").
+			RequireIsolation(sql.LevelSerializable).
+			Scan(ctx, &num)
+	})
+	require.NoError(t, err)
+
+	if db.Dialect().Name() != dialect.SQLite {
+		opts := &sql.TxOptions{Isolation: sql.LevelReadCommitted}
+		err = db.RunInTx(ctx, opts, func(ctx context.Context, tx bun.Tx) error {
+			return tx.NewSelect().
+				ColumnExpr("1").
+				RequireIsolation(sql.LevelSerializable).
+				Scan(ctx, &num)
+		})
+		require.EqualError(t, err,
+			"bun: query requires Serializable isolation level, but the transaction uses Read Committed")
+	}
+
+	opts := &sql.TxOptions{Isolation: sql.LevelSerializable}
+	err = db.RunInTx(ctx, opts, func(ctx context.Context, tx bun.Tx) error {
+		return tx.NewSelect().
+			ColumnExpr("1").
+			RequireIsolation(sql.LevelSerializable).
+			Scan(ctx, &num)
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, num)
+}
+
+func testSelectAggregate(t *testing.T, db *bun.DB) {
+	if db.Dialect().Name() == dialect.MySQL5 {
+		t.Skip()
+	}
+
+	values := db.NewValues(&[]map[string]interface{}{
+		{"num": 1},
+		{"num": 2},
+		{"num": 3},
+	})
+
+	q := db.NewSelect().With("t", values).TableExpr("t")
+
+	sum, err := q.Sum(ctx, "num")
+	require.NoError(t, err)
+	require.Equal(t, sql.NullFloat64{Float64: 6, Valid: true}, sum)
+
+	avg, err := q.Avg(ctx, "num")
+	require.NoError(t, err)
+	require.Equal(t, sql.NullFloat64{Float64: 2, Valid: true}, avg)
+
+	min, err := q.Min(ctx, "num")
+	require.NoError(t, err)
+	require.Equal(t, sql.NullFloat64{Float64: 1, Valid: true}, min)
+
+	max, err := q.Max(ctx, "num")
+	require.NoError(t, err)
+	require.Equal(t, sql.NullFloat64{Float64: 3, Valid: true}, max)
+
+	sum, err = q.Where("num > 3").Sum(ctx, "num")
+	require.NoError(t, err)
+	require.False(t, sum.Valid)
+}
+
+func testSelectMap(t *testing.T, db *bun.DB) {
+	var m map[string]interface{}
+	err := db.NewSelect().
+		ColumnExpr("10 AS num").
+		Scan(ctx, &m)
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{
+		"num": int64(10),
+	}, m)
+}
+
+func testSelectMapSlice(t *testing.T, db *bun.DB) {
+	if db.Dialect().Name() == dialect.MySQL5 {
+		t.Skip()
+	}
+
+	values := db.NewValues(&[]map[string]interface{}{
+		{"column1": 1},
+		{"column1": 2},
+		{"column1": 3},
+	})
+
+	var ms []map[string]interface{}
+	err := db.NewSelect().
+		With("t", values).
+		TableExpr("t").
+		Scan(ctx, &ms)
+	require.NoError(t, err)
+	require.Len(t, ms, 3)
+	for i, m := range ms {
+		require.Equal(t, map[string]interface{}{
+			"column1": int64(i + 1),
+		}, m)
+	}
+}
+
+func testSelectStruct(t *testing.T, db *bun.DB) {
+	type Model struct {
+		Num int
+		Str string
+	}
+
+	model := new(Model)
+	err := db.NewSelect().
+		ColumnExpr("10 AS num, 'hello' as str").
+		Scan(ctx, model)
+	require.NoError(t, err)
+	require.Equal(t, 10, model.Num)
+	require.Equal(t, "hello", model.Str)
+
+	err = db.NewSelect().TableExpr("(SELECT 42) AS t").Where("FALSE").Scan(ctx, model)
+	require.Equal(t, sql.ErrNoRows, err)
+
+	err = db.NewSelect().ColumnExpr("1 as unknown_column").Scan(ctx, model)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Model does not have column")
+}
+
+func testSelectNestedStructValue(t *testing.T, db *bun.DB) {
+	type Model struct {
+		Num int
+		Sub struct {
+			Str string
+		}
+	}
+
+	model := new(Model)
+	err := db.NewSelect().
+		ColumnExpr("10 AS num, 'hello' as sub__str").
+		Scan(ctx, model)
+	require.NoError(t, err)
+	require.Equal(t, 10, model.Num)
+	require.Equal(t, "hello", model.Sub.Str)
+}
+
+func testSelectNestedStructPtr(t *testing.T, db *bun.DB) {
+	type Sub struct {
+		Str string
+	}
+
+	type Model struct {
+		Num int
+		Sub *Sub
+	}
+
+	model := new(Model)
+	err := db.NewSelect().
+		ColumnExpr("10 AS num").
+		Scan(ctx, model)
+	require.NoError(t, err)
+	require.Equal(t, 10, model.Num)
+	require.Nil(t, model.Sub)
+
+	model = new(Model)
+	err = db.NewSelect().
+		ColumnExpr("10 AS num, 'hello' AS sub__str").
+		Scan(ctx, model)
+	require.NoError(t, err)
+	require.Equal(t, 10, model.Num)
+	require.Equal(t, "hello", model.Sub.Str)
+
+	model = new(Model)
+	err = db.NewSelect().
+		ColumnExpr("10 AS num, NULL AS sub__str").
+		Scan(ctx, model)
+	require.NoError(t, err)
+	require.Equal(t, 10, model.Num)
+	require.Nil(t, model.Sub)
+}
+
+func testSelectStructSlice(t *testing.T, db *bun.DB) {
+	if db.Dialect().Name() == dialect.MySQL5 {
+		t.Skip()
+	}
+
+	type Model struct {
+		Num int `bun:"column1"`
+	}
+
+	values := db.NewValues(&[]map[string]interface{}{
+		{"column1": 1},
+		{"column1": 2},
+		{"column1": 3},
+	})
+
+	models := make([]Model, 0)
+	err := db.NewSelect().
+		With("t", values).
+		TableExpr("t").
+		Scan(ctx, &models)
+	require.NoError(t, err)
+	require.Len(t, models, 3)
+	for i, model := range models {
+		require.Equal(t, i+1, model.Num)
+	}
+}
+
+func testSelectSingleSlice(t *testing.T, db *bun.DB) {
+	if db.Dialect().Name() == dialect.MySQL5 {
+		t.Skip()
+	}
+
+	values := db.NewValues(&[]map[string]interface{}{
+		{"column1": 1},
+		{"column1": 2},
+		{"column1": 3},
+	})
+
+	var ns []int
+	err := db.NewSelect().
+		With("t", values).
+		TableExpr("t").
+		Scan(ctx, &ns)
+	require.NoError(t, err)
+	require.Equal(t, []int{1, 2, 3}, ns)
+}
+
+func testSelectMultiSlice(t *testing.T, db *bun.DB) {
+	if db.Dialect().Name() == dialect.MySQL5 {
+		t.Skip()
+	}
+
+	values := db.NewValues(&[]map[string]interface{}{
+		{"a": 1, "b": "foo"},
+		{"a": 2, "b": "bar"},
+		{"a": 3, "b": ""},
+	})
+
+	var ns []int
+	var ss []string
+	err := db.NewSelect().
+		With("t", values).
+		TableExpr("t").
+		Scan(ctx, &ns, &ss)
+	require.NoError(t, err)
+	require.Equal(t, []int{1, 2, 3}, ns)
+	require.Equal(t, []string{"foo", "bar", ""}, ss)
+}
+
+func testSelectJSONMap(t *testing.T, db *bun.DB) {
+	type Model struct {
+		Map map[string]string
+	}
+
+	model := new(Model)
+	err := db.NewSelect().
+		ColumnExpr("? AS map", map[string]string{"hello": "world"}).
+		Scan(ctx, model)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"hello": "world"}, model.Map)
+
+	err = db.NewSelect().
+		ColumnExpr("NULL AS map").
+		Scan(ctx, model)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string(nil), model.Map)
+}
+
+func testSelectJSONStruct(t *testing.T, db *bun.DB) {
+	type Struct struct {
+		Hello string
+	}
+
+	type Model struct {
+		Struct Struct
+	}
+
+	model := new(Model)
+	err := db.NewSelect().
+		ColumnExpr("? AS struct", Struct{Hello: "world"}).
+		Scan(ctx, model)
+	require.NoError(t, err)
+	require.Equal(t, Struct{Hello: "world"}, model.Struct)
+
+	err = db.NewSelect().
+		ColumnExpr("NULL AS struct").
+		Scan(ctx, model)
+	require.NoError(t, err)
+	require.Equal(t, Struct{}, model.Struct)
+}
+
+func testSelectRawMessage(t *testing.T, db *bun.DB) {
+	type Model struct {
+		Raw json.RawMessage
+	}
+
+	model := new(Model)
+	err := db.NewSelect().
+		ColumnExpr("? AS raw", map[string]string{"hello": "world"}).
+		Scan(ctx, model)
+	require.NoError(t, err)
+	require.Equal(t, `{"hello":"world"}`, string(model.Raw))
+
+	err = db.NewSelect().
+		ColumnExpr("NULL AS raw").
+		Scan(ctx, model)
+	require.NoError(t, err)
+	require.Nil(t, model.Raw)
+}
+
+func testScanNullVar(t *testing.T, db *bun.DB) {
+	num := int(42)
+	err := db.NewSelect().ColumnExpr("NULL").Scan(ctx, &num)
+	require.NoError(t, err)
+	require.Zero(t, num)
+}
+
+func testScanSingleRow(t *testing.T, db *bun.DB) {
+	rows, err := db.QueryContext(ctx, "SELECT 42")
+	require.NoError(t, err)
+	defer rows.Close()
+
+	if !rows.Next() {
+		t.Fail()
+	}
+
+	var num int
+	err = db.ScanRow(ctx, rows, &num)
+	require.NoError(t, err)
+	require.Equal(t, 42, num)
+}
+
+func testScanSingleRowByRow(t *testing.T, db *bun.DB) {
+	if db.Dialect().Name() == dialect.MySQL5 {
+		t.Skip()
+	}
+
+	values := db.NewValues(&[]map[string]interface{}{
+		{"num": 1},
+		{"num": 2},
+		{"num": 3},
+	})
+
+	rows, err := db.NewSelect().
+		With("t", values).
+		TableExpr("t").
+		OrderExpr("t.num DESC").
+		Rows(ctx)
+	require.NoError(t, err)
+	defer rows.Close()
+
+	var nums []int
 
 	for rows.Next() {
 		var num int
 
-		err := db.ScanRow(ctx, rows, &num)
+		err := db.ScanRow(ctx, rows, &num)
+		require.NoError(t, err)
+
+		nums = append(nums, num)
+	}
+
+	require.NoError(t, rows.Err())
+	require.Equal(t, []int{3, 2, 1}, nums)
+}
+
+func testScanModelRowByRow(t *testing.T, db *bun.DB) {
+	type Model struct {
+		ID  int64
+		Str string
+	}
+
+	err := db.ResetModel(ctx, (*Model)(nil))
+	require.NoError(t, err)
+
+	models := []Model{{ID: 1, Str: "a"}, {ID: 2, Str: "b"}, {ID: 3, Str: "c"}}
+	_, err = db.NewInsert().Model(&models).Exec(ctx)
+	require.NoError(t, err)
+
+	rows, err := db.NewSelect().Model((*Model)(nil)).Order("id").Rows(ctx)
+	require.NoError(t, err)
+	defer rows.Close()
+
+	var scanned []Model
+	for rows.Next() {
+		model := new(Model)
+		err := db.ScanRow(ctx, rows, model)
+		require.NoError(t, err)
+		scanned = append(scanned, *model)
+	}
+	require.NoError(t, rows.Err())
+	require.Equal(t, models, scanned)
+}
+
+func testScanRows(t *testing.T, db *bun.DB) {
+	if db.Dialect().Name() == dialect.MySQL5 {
+		t.Skip()
+	}
+
+	values := db.NewValues(&[]map[string]interface{}{
+		{"num": 1},
+		{"num": 2},
+		{"num": 3},
+	})
+
+	rows, err := db.NewSelect().
+		With("t", values).
+		TableExpr("t").
+		OrderExpr("t.num DESC").
+		Rows(ctx)
+	require.NoError(t, err)
+	defer rows.Close()
+
+	var nums []int
+	err = db.ScanRows(ctx, rows, &nums)
+	require.NoError(t, err)
+	require.Equal(t, []int{3, 2, 1}, nums)
+}
+
+func testRunInTx(t *testing.T, db *bun.DB) {
+	type Counter struct {
+		Count int64
+	}
+
+	err := db.ResetModel(ctx, (*Counter)(nil))
+	require.NoError(t, err)
+
+	_, err = db.NewInsert().Model(&Counter{Count: 0}).Exec(ctx)
+	require.NoError(t, err)
+
+	err = db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		_, err := tx.NewUpdate().Model((*Counter)(nil)).
+			Set("count = count + 1").
+			Where("TRUE").
+			Exec(ctx)
+		return err
+	})
+	require.NoError(t, err)
+
+	var count int
+	err = db.NewSelect().Model((*Counter)(nil)).Scan(ctx, &count)
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+
+	err = db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if _, err := tx.NewUpdate().Model((*Counter)(nil)).
+			Set("count = count + 1").
+			Where("TRUE").
+			Exec(ctx); err != nil {
+			return err
+		}
+		return errors.New("rollback")
+	})
+	require.Error(t, err)
+
+	err = db.NewSelect().Model((*Counter)(nil)).Scan(ctx, &count)
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+}
+
+func testJSONSpecialChars(t *testing.T, db *bun.DB) {
+	type Model struct {
+		ID    int
+		Attrs map[string]interface{} `bun:"type:json"`
+	}
+
+	ctx := context.Background()
+
+	err := db.ResetModel(ctx, (*Model)(nil))
+	require.NoError(t, err)
+
+	model := &Model{
+		Attrs: map[string]interface{}{
+			"hello": "\000world\nworld\u0000",
+		},
+	}
+	_, err = db.NewInsert().Model(model).Exec(ctx)
+	require.NoError(t, err)
+
+	model = new(Model)
+	err = db.NewSelect().Model(model).Scan(ctx)
+	require.NoError(t, err)
+	switch db.Dialect().Name() {
+	case dialect.MySQL5, dialect.MySQL8:
+		require.Equal(t, map[string]interface{}{
+			"hello": "\x00world\nworld\x00",
+		}, model.Attrs)
+	default:
+		require.Equal(t, map[string]interface{}{
+			"hello": "\\u0000world\nworld\\u0000",
+		}, model.Attrs)
+	}
+}
+
+func testJSONTag(t *testing.T, db *bun.DB) {
+	type Item struct {
+		Name string `json:"name"`
+		Qty  int    `json:"qty"`
+	}
+	type Model struct {
+		ID    int
+		Items []Item               `bun:",json"`
+		Meta  interface{}          `bun:",json"`
+		Null  map[string]Item      `bun:",json"`
+		Opts  *map[string]struct{} `bun:",json"`
+	}
+
+	err := db.ResetModel(ctx, (*Model)(nil))
+	require.NoError(t, err)
+
+	model := &Model{
+		ID:    1,
+		Items: []Item{{"apple", 2}, {"pear", 1}},
+		Meta:  map[string]interface{}{"source": "web"},
+	}
+	_, err = db.NewInsert().Model(model).Exec(ctx)
+	require.NoError(t, err)
+
+	got := new(Model)
+	err = db.NewSelect().Model(got).Where("id = ?", 1).Scan(ctx)
+	require.NoError(t, err)
+	require.Equal(t, model, got)
+}
+
+type Cents struct {
+	N int64
+}
+
+// newDialect returns a new instance of the dialect, which has its own schema.Tables.
+func newDialect(name dialect.Name) schema.Dialect {
+	switch name {
+	case dialect.PG:
+		return pgdialect.New()
+	case dialect.MySQL5, dialect.MySQL8:
+		return mysqldialect.New()
+	case dialect.SQLite:
+		return sqlitedialect.New()
+	default:
+		panic(fmt.Errorf("unsupported dialect: %s", name))
+	}
+}
+
+func testCustomType(t *testing.T, db *bun.DB) {
+	type Model struct {
+		ID    int
+		Price Cents
+		Old   *Cents
+		Code  string `bun:"type:varchar(10)"`
+	}
+
+	// Register the types on a new dialect to not affect the other tests.
+	db = bun.NewDB(db.DB, newDialect(db.Dialect().Name()))
+
+	tables := db.Dialect().Tables()
+	tables.RegisterType(reflect.TypeOf(Cents{}), schema.CustomType{
+		SQLType: "BIGINT",
+		Append: func(fmter schema.Formatter, b []byte, v reflect.Value) []byte {
+			return strconv.AppendInt(b, v.Interface().(Cents).N, 10)
+		},
+		Scan: func(dest reflect.Value, src interface{}) error {
+			n, ok := src.(int64)
+			if !ok && src != nil {
+				return fmt.Errorf("can't scan %T into Cents", src)
+			}
+			dest.Set(reflect.ValueOf(Cents{N: n}))
+			return nil
+		},
+	})
+	tables.RegisterSQLType("varchar(10)", func(fmter schema.Formatter, b []byte, v reflect.Value) []byte {
+		return fmter.Dialect().Append(fmter, b, strings.ToUpper(v.String()))
+	}, nil)
+
+	err := db.ResetModel(ctx, (*Model)(nil))
+	require.NoError(t, err)
+
+	model := &Model{ID: 1, Price: Cents{500}, Code: "abc"}
+	_, err = db.NewInsert().Model(model).Exec(ctx)
+	require.NoError(t, err)
+
+	got := new(Model)
+	err = db.NewSelect().Model(got).Where("price = ?", Cents{500}).Scan(ctx)
+	require.NoError(t, err)
+	require.Equal(t, &Model{ID: 1, Price: Cents{500}, Code: "ABC"}, got)
+
+	var price Cents
+	err = db.NewSelect().Model((*Model)(nil)).Column("price").Scan(ctx, &price)
+	require.NoError(t, err)
+	require.Equal(t, Cents{500}, price)
+
+	var prices []Cents
+	err = db.NewSelect().Model((*Model)(nil)).Column("price").Scan(ctx, &prices)
+	require.NoError(t, err)
+	require.Equal(t, []Cents{{500}}, prices)
+}
+
+type explainLogger struct {
+	logs []string
+}
+
+func (l *explainLogger) Printf(format string, v ...interface{}) {
+	l.logs = append(l.logs, fmt.Sprintf(format, v...))
+}
+
+func testExplain(t *testing.T, db *bun.DB) {
+	q := db.NewSelect().ColumnExpr("1")
+
+	plan, err := q.Explain(ctx)
+	require.NoError(t, err)
+	require.NotEmpty(t, plan)
+
+	switch db.Dialect().Name() {
+	case dialect.SQLite, dialect.MySQL5:
+		_, err = q.Explain(ctx, bun.ExplainAnalyze())
+		require.Error(t, err)
+	default:
+		plan, err = q.Explain(ctx, bun.ExplainAnalyze())
+		require.NoError(t, err)
+		require.NotEmpty(t, plan)
+	}
+
+	logger := new(explainLogger)
+	db = bun.NewDB(db.DB, db.Dialect())
+	db.AddQueryHook(&bun.ExplainAnalyzeHook{Logger: logger})
+
+	var num int
+	err = db.NewSelect().ColumnExpr("1").Scan(ctx, &num)
+	require.NoError(t, err)
+
+	_, err = db.ExecContext(ctx, "SELECT 1")
+	require.NoError(t, err)
+
+	err = db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		return tx.NewSelect().ColumnExpr("1").Scan(ctx, &num)
+	})
+	require.NoError(t, err)
+
+	require.Len(t, logger.logs, 1)
+	require.Contains(t, logger.logs[0], "SELECT 1 took")
+
+	_, err = q.Explain(ctx, bun.ExplainFormat("JSON; DROP TABLE x"))
+	require.Error(t, err)
+}
+
+func testStmtCache(t *testing.T, db *bun.DB) {
+	type Model struct {
+		ID  int
+		Str string
+	}
+
+	db = bun.NewDB(db.DB, db.Dialect(), bun.WithStmtCache(2))
+
+	err := db.ResetModel(ctx, (*Model)(nil))
+	require.NoError(t, err)
+
+	for i := 1; i <= 3; i++ {
+		_, err := db.NewInsert().Model(&Model{ID: i, Str: "hello"}).Exec(ctx)
+		require.NoError(t, err)
+
+		for j := 0; j < 2; j++ {
+			count, err := db.NewSelect().Model((*Model)(nil)).Count(ctx)
+			require.NoError(t, err)
+			require.Equal(t, i, count)
+
+			var models []Model
+			err = db.NewSelect().Model(&models).Order("id").Scan(ctx)
+			require.NoError(t, err)
+			require.Len(t, models, i)
+		}
+	}
+
+	// The queries that differ only by the args share the statement.
+	for i := 1; i <= 3; i++ {
+		model := new(Model)
+		err := db.NewSelect().Model(model).Where("id = ?", i).Scan(ctx)
+		require.NoError(t, err)
+		require.Equal(t, i, model.ID)
+	}
+
+	// The statements evicted by other goroutines stay usable until released.
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				var models []Model
+				err := db.NewSelect().Model(&models).Where("id <= ?", 3).Limit(i + 1).Scan(ctx)
+				assert.NoError(t, err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	err = db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		_, err := tx.NewDelete().Model((*Model)(nil)).Where("id = ?", 3).Exec(ctx)
+		require.NoError(t, err)
+
+		count, err := tx.NewSelect().Model((*Model)(nil)).Count(ctx)
+		require.NoError(t, err)
+		require.Equal(t, 2, count)
+
+		return errors.New("rollback")
+	})
+	require.EqualError(t, err, "rollback")
+
+	count, err := db.NewSelect().Model((*Model)(nil)).Count(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 3, count)
+
+	_, err = db.NewSelect().Model((*Model)(nil)).Where("missing = 1").Count(ctx)
+	require.Error(t, err)
+}
+
+func testInsertIface(t *testing.T, db *bun.DB) {
+	type Model struct {
+		ID    int
+		Value interface{} `bun:"type:json"`
+	}
+
+	ctx := context.Background()
+
+	err := db.ResetModel(ctx, (*Model)(nil))
+	require.NoError(t, err)
+
+	model := new(Model)
+	_, err = db.NewInsert().Model(model).Exec(ctx)
+	require.NoError(t, err)
+
+	model = &Model{
+		Value: "hello",
+	}
+	_, err = db.NewInsert().Model(model).Exec(ctx)
+	require.NoError(t, err)
+}
+
+func testInsertSliceIDs(t *testing.T, db *bun.DB) {
+	type Model struct {
+		ID  int64 `bun:",pk,autoincrement"`
+		Str string
+	}
+
+	err := db.ResetModel(ctx, (*Model)(nil))
+	require.NoError(t, err)
+
+	models := []*Model{{Str: "foo"}, {Str: "bar"}}
+	_, err = db.NewInsert().Model(&models).Exec(ctx)
+	require.NoError(t, err)
+	require.NotZero(t, models[0].ID)
+	require.Equal(t, models[0].ID+1, models[1].ID)
+
+	models = []*Model{{Str: "baz"}, {Str: "qux"}}
+	_, err = db.NewInsert().Model(&models).Exec(ctx)
+	require.NoError(t, err)
+
+	var strs []string
+	err = db.NewSelect().
+		Model((*Model)(nil)).
+		Column("str").
+		Where("id IN (?)", bun.In([]int64{models[0].ID, models[1].ID})).
+		OrderExpr("id ASC").
+		Scan(ctx, &strs)
+	require.NoError(t, err)
+	require.Equal(t, []string{"baz", "qux"}, strs)
+
+	if db.Dialect().Features().Has(feature.OnDuplicateKey) {
+		// The ids are not derived when the upsert may update rows.
+		dupID := models[0].ID
+		models = []*Model{{ID: dupID, Str: "baz2"}, {Str: "quux"}}
+		_, err = db.NewInsert().
+			Model(&models).
+			On("DUPLICATE KEY UPDATE str = VALUES(str)").
+			Exec(ctx)
 		require.NoError(t, err)
+		require.Equal(t, dupID, models[0].ID)
+		require.Zero(t, models[1].ID)
+	}
+}
 
-		nums = append(nums, num)
+func testSelectIface(t *testing.T, db *bun.DB) {
+	type Model struct {
+		ID    int
+		Value interface{} `bun:"type:json"`
 	}
 
-	require.NoError(t, rows.Err())
-	require.Equal(t, []int{3, 2, 1}, nums)
+	ctx := context.Background()
+
+	err := db.ResetModel(ctx, (*Model)(nil))
+	require.NoError(t, err)
+
+	models := []Model{
+		{ID: 1, Value: map[string]interface{}{"hello": "world"}},
+		{ID: 2, Value: []interface{}{"foo", "bar"}},
+		{ID: 3},
+	}
+	_, err = db.NewInsert().Model(&models).Exec(ctx)
+	require.NoError(t, err)
+
+	models = nil
+	err = db.NewSelect().Model(&models).Order("id ASC").Scan(ctx)
+	require.NoError(t, err)
+	require.Len(t, models, 3)
+	require.Equal(t, map[string]interface{}{"hello": "world"}, models[0].Value)
+	require.Equal(t, []interface{}{"foo", "bar"}, models[1].Value)
+	require.Nil(t, models[2].Value)
 }
 
-func testScanRows(t *testing.T, db *bun.DB) {
+func testSelectBool(t *testing.T, db *bun.DB) {
+	var flag bool
+	err := db.NewSelect().ColumnExpr("1").Scan(ctx, &flag)
+	require.NoError(t, err)
+	require.True(t, flag)
+
+	err = db.NewSelect().ColumnExpr("0").Scan(ctx, &flag)
+	require.NoError(t, err)
+	require.False(t, flag)
+}
+
+func testConnNamedArg(t *testing.T, db *bun.DB) {
 	if db.Dialect().Name() == dialect.MySQL5 {
 		t.Skip()
 	}
 
-	values := db.NewValues(&[]map[string]interface{}{
-		{"num": 1},
-		{"num": 2},
-		{"num": 3},
-	})
+	q := db.NewSelect().
+		With("t1", db.NewValues(&[]map[string]interface{}{{"num": 1}})).
+		With("t2", db.NewValues(&[]map[string]interface{}{{"num": 2}})).
+		TableExpr("?table").
+		ColumnExpr("num")
 
-	rows, err := db.NewSelect().
-		With("t", values).
-		TableExpr("t").
-		OrderExpr("t.num DESC").
-		Rows(ctx)
+	var num int
+
+	err := q.Conn(db.WithNamedArg("table", bun.Ident("t1"))).Scan(ctx, &num)
 	require.NoError(t, err)
-	defer rows.Close()
+	require.Equal(t, 1, num)
 
-	var nums []int
-	err = db.ScanRows(ctx, rows, &nums)
+	err = q.Conn(db.WithNamedArg("table", bun.Ident("t2"))).Scan(ctx, &num)
 	require.NoError(t, err)
-	require.Equal(t, []int{3, 2, 1}, nums)
+	require.Equal(t, 2, num)
 }
 
-func testRunInTx(t *testing.T, db *bun.DB) {
-	type Counter struct {
-		Count int64
+func testQueryNamedArg(t *testing.T, db *bun.DB) {
+	db = db.WithNamedArg("tenant_id", 1)
+
+	var num int
+	err := db.NewSelect().ColumnExpr("?tenant_id").Scan(ctx, &num)
+	require.NoError(t, err)
+	require.Equal(t, 1, num)
+
+	err = db.NewSelect().ColumnExpr("?tenant_id").NamedArg("tenant_id", 2).Scan(ctx, &num)
+	require.NoError(t, err)
+	require.Equal(t, 2, num)
+
+	err = db.NewRaw("SELECT ?tenant_id").NamedArg("tenant_id", 3).Scan(ctx, &num)
+	require.NoError(t, err)
+	require.Equal(t, 3, num)
+
+	err = db.WithNamedArg("tenant_id", 4).NewSelect().ColumnExpr("?tenant_id").Scan(ctx, &num)
+	require.NoError(t, err)
+	require.Equal(t, 4, num)
+
+	err = db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		return tx.NewSelect().ColumnExpr("?tenant_id").Scan(ctx, &num)
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, num)
+}
+
+func testWarnOnSelectStar(t *testing.T, db *bun.DB) {
+	type Model struct {
+		ID  int64
+		Str string
 	}
 
-	err := db.ResetModel(ctx, (*Counter)(nil))
+	var called int
+	warn := func(q *bun.SelectQuery) {
+		called++
+	}
+
+	_, err := db.NewSelect().Model(new(Model)).WarnOnSelectStar(warn).
+		AppendQuery(db.Formatter(), nil)
 	require.NoError(t, err)
+	require.Equal(t, 1, called)
 
-	_, err = db.NewInsert().Model(&Counter{Count: 0}).Exec(ctx)
+	_, err = db.NewSelect().Model(new(Model)).Column("id").WarnOnSelectStar(warn).
+		AppendQuery(db.Formatter(), nil)
+	require.NoError(t, err)
+	require.Equal(t, 1, called)
+}
+
+func testSelectColumnTypes(t *testing.T, db *bun.DB) {
+	columnTypes, err := db.NewSelect().
+		ColumnExpr("1 AS num, 'hello' AS str").
+		Limit(10).
+		ColumnTypes(ctx)
 	require.NoError(t, err)
+	require.Len(t, columnTypes, 2)
+	require.Equal(t, "num", columnTypes[0].Name())
+	require.Equal(t, "str", columnTypes[1].Name())
+}
+
+func testRunInTxWithRetry(t *testing.T, db *bun.DB) {
+	var transientErr error
+	switch db.Dialect().Name() {
+	case dialect.PG:
+		transientErr = sqlStateError("40001")
+	case dialect.MySQL5, dialect.MySQL8:
+		transientErr = errors.New("Error 1213: Deadlock found when trying to get lock")
+	default:
+		transientErr = errors.New("database is locked")
+	}
+
+	var attempts int
+	err := db.RunInTxWithRetry(ctx, nil, 3, func(ctx context.Context, tx bun.Tx) error {
+		attempts++
+		if attempts < 3 {
+			return fmt.Errorf("wrapped: %w", transientErr)
+		}
+
+		var num int
+		return tx.NewSelect().ColumnExpr("1").Scan(ctx, &num)
+	})
+	require.NoError(t, err)
+	require.Equal(t, 3, attempts)
+
+	attempts = 0
+	err = db.RunInTxWithRetry(ctx, nil, 1, func(ctx context.Context, tx bun.Tx) error {
+		attempts++
+		return transientErr
+	})
+	require.Equal(t, transientErr, err)
+	require.Equal(t, 2, attempts)
+
+	attempts = 0
+	permanentErr := errors.New("permanent")
+	err = db.RunInTxWithRetry(ctx, nil, 3, func(ctx context.Context, tx bun.Tx) error {
+		attempts++
+		return permanentErr
+	})
+	require.Equal(t, permanentErr, err)
+	require.Equal(t, 1, attempts)
+}
 
+func testRetryPolicy(t *testing.T, db *bun.DB) {
+	db = bun.NewDB(db.DB, db.Dialect(), bun.WithRetryPolicy(bun.RetryPolicy{
+		MaxRetries: 2,
+		Backoff: func(retry int) time.Duration {
+			return 0
+		},
+		IsRetryableError: func(err error) bool {
+			return true
+		},
+	}))
+
+	var attempts []int
+	hook := &queryHook{}
+	hook.beforeQuery = func(ctx context.Context, event *bun.QueryEvent) context.Context {
+		attempts = append(attempts, event.Attempt)
+		return ctx
+	}
+	db.AddQueryHook(hook)
+
+	_, err := db.NewDelete().Table("retry_missing").Where("TRUE").Exec(ctx)
+	require.Error(t, err)
+	require.Equal(t, []int{0, 1, 2}, attempts)
+
+	attempts = nil
+	var num int
+	err = db.NewSelect().Table("retry_missing").ColumnExpr("1").Scan(ctx, &num)
+	require.Error(t, err)
+	require.Equal(t, []int{0, 1, 2}, attempts)
+
+	attempts = nil
 	err = db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
-		_, err := tx.NewUpdate().Model((*Counter)(nil)).
-			Set("count = count + 1").
-			Where("TRUE").
-			Exec(ctx)
+		_, err := tx.NewDelete().Table("retry_missing").Where("TRUE").Exec(ctx)
 		return err
 	})
+	require.Error(t, err)
+	require.Equal(t, []int{0}, attempts)
+
+	attempts = nil
+	err = db.NewSelect().ColumnExpr("1").Scan(ctx, &num)
 	require.NoError(t, err)
+	require.Equal(t, []int{0}, attempts)
+}
 
-	var count int
-	err = db.NewSelect().Model((*Counter)(nil)).Scan(ctx, &count)
+func testIDB(t *testing.T, db *bun.DB) {
+	selectNum := func(ctx context.Context, idb bun.IDB) (int, error) {
+		var num int
+		err := idb.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+			return tx.NewSelect().ColumnExpr("42").Scan(ctx, &num)
+		})
+		return num, err
+	}
+
+	num, err := selectNum(ctx, db)
 	require.NoError(t, err)
-	require.Equal(t, 1, count)
+	require.Equal(t, 42, num)
+
+	conn, err := db.Conn(ctx)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	num, err = selectNum(ctx, conn)
+	require.NoError(t, err)
+	require.Equal(t, 42, num)
 
 	err = db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
-		if _, err := tx.NewUpdate().Model((*Counter)(nil)).
-			Set("count = count + 1").
-			Where("TRUE").
-			Exec(ctx); err != nil {
-			return err
-		}
-		return errors.New("rollback")
+		require.Equal(t, db.Dialect(), tx.Dialect())
+
+		num, err := selectNum(ctx, tx)
+		require.NoError(t, err)
+		require.Equal(t, 42, num)
+		return nil
 	})
-	require.Error(t, err)
+	require.NoError(t, err)
 
-	err = db.NewSelect().Model((*Counter)(nil)).Scan(ctx, &count)
+	bunTx, err := conn.BeginBunTx(ctx, nil)
 	require.NoError(t, err)
-	require.Equal(t, 1, count)
+	require.NoError(t, bunTx.Rollback())
 }
 
-func testJSONSpecialChars(t *testing.T, db *bun.DB) {
+func testNestedRunInTx(t *testing.T, db *bun.DB) {
 	type Model struct {
-		ID    int
-		Attrs map[string]interface{} `bun:"type:json"`
+		ID int64
 	}
 
-	ctx := context.Background()
-
 	err := db.ResetModel(ctx, (*Model)(nil))
 	require.NoError(t, err)
 
-	model := &Model{
-		Attrs: map[string]interface{}{
-			"hello": "\000world\nworld\u0000",
-		},
-	}
-	_, err = db.NewInsert().Model(model).Exec(ctx)
+	errInner := errors.New("inner")
+	err = db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if _, err := tx.NewInsert().Model(&Model{ID: 1}).Exec(ctx); err != nil {
+			return err
+		}
+
+		err := tx.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+			if _, err := tx.NewInsert().Model(&Model{ID: 2}).Exec(ctx); err != nil {
+				return err
+			}
+			return errInner
+		})
+		require.Equal(t, errInner, err)
+
+		err = tx.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+			_, err := tx.NewInsert().Model(&Model{ID: 3}).Exec(ctx)
+			return err
+		})
+		require.NoError(t, err)
+
+		err = tx.RunInTx(ctx, &sql.TxOptions{ReadOnly: true}, func(ctx context.Context, tx bun.Tx) error {
+			return nil
+		})
+		require.EqualError(t, err, "bun: nested RunInTx does not support TxOptions")
+
+		return nil
+	})
 	require.NoError(t, err)
 
-	model = new(Model)
-	err = db.NewSelect().Model(model).Scan(ctx)
+	var ids []int64
+	err = db.NewSelect().Model((*Model)(nil)).Column("id").Order("id").Scan(ctx, &ids)
 	require.NoError(t, err)
-	switch db.Dialect().Name() {
-	case dialect.MySQL5, dialect.MySQL8:
-		require.Equal(t, map[string]interface{}{
-			"hello": "\x00world\nworld\x00",
-		}, model.Attrs)
-	default:
-		require.Equal(t, map[string]interface{}{
-			"hello": "\\u0000world\nworld\\u0000",
-		}, model.Attrs)
+	require.Equal(t, []int64{1, 3}, ids)
+}
+
+func testQueryTimeout(t *testing.T, db *bun.DB) {
+	deadlines := make(chan time.Time, 1)
+	timeoutDB := bun.NewDB(db.DB, db.Dialect())
+	timeoutDB.AddQueryHook(&queryHook{
+		beforeQuery: func(ctx context.Context, event *bun.QueryEvent) context.Context {
+			deadline, ok := ctx.Deadline()
+			require.True(t, ok)
+			deadlines <- deadline
+
+			// The sqlite driver interrupts the connection from a goroutine when the context
+			// is canceled, and the goroutine can outlive the connection.
+			if db.Dialect().Name() == dialect.SQLite {
+				return noCancelContext{ctx}
+			}
+			return ctx
+		},
+	})
+
+	var num int
+	err := timeoutDB.NewSelect().ColumnExpr("1").Timeout(time.Minute).Scan(ctx, &num)
+	require.NoError(t, err)
+	require.Equal(t, 1, num)
+	require.WithinDuration(t, time.Now().Add(time.Minute), <-deadlines, 10*time.Second)
+
+	// Let the deadline pass before the query reaches the driver, because some drivers
+	// interrupt the connection asynchronously when the context is canceled mid-query.
+	slowDB := bun.NewDB(db.DB, db.Dialect())
+	hook := &queryHook{}
+	hook.beforeQuery = func(ctx context.Context, event *bun.QueryEvent) context.Context {
+		<-ctx.Done()
+		return ctx
 	}
+	slowDB.AddQueryHook(hook)
+
+	err = slowDB.NewSelect().ColumnExpr("1").Timeout(time.Nanosecond).Scan(ctx, &num)
+	require.Equal(t, context.DeadlineExceeded, err)
+
+	_, err = slowDB.NewSelect().ColumnExpr("1").Timeout(time.Nanosecond).Count(ctx)
+	require.Equal(t, context.DeadlineExceeded, err)
 }
 
-func testInsertIface(t *testing.T, db *bun.DB) {
+// noCancelContext keeps the values of the context, but is never canceled.
+type noCancelContext struct {
+	context.Context
+}
+
+func (noCancelContext) Deadline() (time.Time, bool) { return time.Time{}, false }
+func (noCancelContext) Done() <-chan struct{}       { return nil }
+func (noCancelContext) Err() error                  { return nil }
+
+func testScanResultSets(t *testing.T, db *bun.DB) {
 	type Model struct {
-		ID    int
-		Value interface{} `bun:"type:json"`
+		Num int
+		Str string
 	}
 
-	ctx := context.Background()
+	rows, err := db.QueryContext(ctx, "SELECT 1 AS num, 'one' AS str UNION ALL SELECT 2, 'two'")
+	require.NoError(t, err)
+	defer rows.Close()
 
-	err := db.ResetModel(ctx, (*Model)(nil))
+	var models []Model
+	err = db.ScanResultSets(ctx, rows, &models)
 	require.NoError(t, err)
+	require.Equal(t, []Model{{1, "one"}, {2, "two"}}, models)
 
-	model := new(Model)
-	_, err = db.NewInsert().Model(model).Exec(ctx)
+	rows, err = db.QueryContext(ctx, "SELECT 1")
 	require.NoError(t, err)
+	defer rows.Close()
 
-	model = &Model{
-		Value: "hello",
+	var num1, num2 int
+	err = db.ScanResultSets(ctx, rows, &num1, &num2)
+	require.EqualError(t, err, "bun: got 1 result sets, wanted 2")
+	require.Equal(t, 1, num1)
+
+	// Only the MySQL driver returns multiple result sets, for example, from a stored procedure.
+	switch db.Dialect().Name() {
+	case dialect.MySQL5, dialect.MySQL8:
+	default:
+		return
 	}
-	_, err = db.NewInsert().Model(model).Exec(ctx)
+
+	_, err = db.ExecContext(ctx, "DROP PROCEDURE IF EXISTS scan_result_sets")
 	require.NoError(t, err)
-}
 
-func testSelectBool(t *testing.T, db *bun.DB) {
-	var flag bool
-	err := db.NewSelect().ColumnExpr("1").Scan(ctx, &flag)
+	_, err = db.ExecContext(ctx, `CREATE PROCEDURE scan_result_sets()
+BEGIN
+	SELECT 1 AS num, 'one' AS str UNION ALL SELECT 2, 'two';
+	SELECT 3;
+END`)
 	require.NoError(t, err)
-	require.True(t, flag)
 
-	err = db.NewSelect().ColumnExpr("0").Scan(ctx, &flag)
+	rows, err = db.QueryContext(ctx, "CALL scan_result_sets()")
 	require.NoError(t, err)
-	require.False(t, flag)
+	defer rows.Close()
+
+	models = nil
+	var num int
+	err = db.ScanResultSets(ctx, rows, &models, &num)
+	require.NoError(t, err)
+	require.Equal(t, []Model{{1, "one"}, {2, "two"}}, models)
+	require.Equal(t, 3, num)
+}
+
+type sqlStateError string
+
+func (err sqlStateError) Error() string {
+	return "ERROR #" + string(err)
+}
+
+func (err sqlStateError) SQLState() string {
+	return string(err)
 }
@@ -0,0 +1,106 @@
+package dbtest_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/gen"
+)
+
+type GenAddress struct {
+	Street string
+}
+
+type GenUser struct {
+	ID       int64
+	Email    string
+	Billing  GenAddress `bun:"embed:billing_"`
+	Shipping GenAddress `bun:"embed:shipping_"`
+}
+
+func TestGen(t *testing.T) {
+	db := sqlite(t)
+
+	var buf bytes.Buffer
+	err := gen.Generate(&buf, db, "models", (*GenUser)(nil))
+	require.NoError(t, err)
+	require.Equal(t, `// Code generated by bun/gen. DO NOT EDIT.
+
+package models
+
+import "github.com/uptrace/bun/gen"
+
+// GenUserTable is the name of the GenUser model table.
+const GenUserTable = "gen_users"
+
+// GenUserAlias is the alias of the GenUser model table.
+const GenUserAlias = "gen_user"
+
+// GenUserCols contains the GenUser model columns.
+var GenUserCols = struct {
+	ID             gen.Int64Column
+	Email          gen.StringColumn
+	BillingStreet  gen.StringColumn
+	ShippingStreet gen.StringColumn
+}{
+	ID:             gen.Int64Column{Column: gen.Column{Table: "gen_user", Name: "id"}},
+	Email:          gen.StringColumn{Column: gen.Column{Table: "gen_user", Name: "email"}},
+	BillingStreet:  gen.StringColumn{Column: gen.Column{Table: "gen_user", Name: "billing_street"}},
+	ShippingStreet: gen.StringColumn{Column: gen.Column{Table: "gen_user", Name: "shipping_street"}},
+}
+`, buf.String())
+
+	err = gen.Generate(&buf, db, "models", 42)
+	require.EqualError(t, err, "bun: gen: got int, wanted a struct or a struct pointer")
+}
+
+func TestGenColumn(t *testing.T) {
+	testEachDB(t, testGenColumn)
+}
+
+func testGenColumn(t *testing.T, db *bun.DB) {
+	email := gen.StringColumn{Column: gen.Column{Table: "gen_user", Name: "email"}}
+	id := gen.Int64Column{Column: gen.Column{Table: "gen_user", Name: "id"}}
+
+	err := db.ResetModel(ctx, (*GenUser)(nil))
+	require.NoError(t, err)
+
+	users := []GenUser{{ID: 1, Email: "a@example.com"}, {ID: 2, Email: "b@example.com"}}
+	_, err = db.NewInsert().Model(&users).Exec(ctx)
+	require.NoError(t, err)
+
+	user := new(GenUser)
+	err = db.NewSelect().Model(user).Where(email.Eq("b@example.com")).Scan(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), user.ID)
+
+	count, err := db.NewSelect().
+		Model((*GenUser)(nil)).
+		Where(id.In(1, 2)).
+		Where(email.IsNotNull()).
+		Where(id.Ge(2)).
+		Count(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+
+	var emails []string
+	err = db.NewSelect().
+		Model((*GenUser)(nil)).
+		Column(email.Name).
+		OrderExpr("? DESC", id.Ident()).
+		Scan(ctx, &emails)
+	require.NoError(t, err)
+	require.Equal(t, []string{"b@example.com", "a@example.com"}, emails)
+
+	var userID int64
+	err = db.NewSelect().
+		TableExpr("? AS u", bun.Ident("gen_users")).
+		ColumnExpr("?", id.As("u").Ident()).
+		Where(email.As("u").Eq("a@example.com")).
+		Scan(ctx, &userID)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), userID)
+}
@@ -0,0 +1,75 @@
+package dbtest_test
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/migrate"
+)
+
+func TestMigrate(t *testing.T) {
+	testEachDB(t, testMigrate)
+}
+
+func testMigrate(t *testing.T, db *bun.DB) {
+	for _, table := range []string{"test_migrations", "test_migration_locks", "migrate_test"} {
+		_, err := db.NewDropTable().Table(table).IfExists().Exec(ctx)
+		require.NoError(t, err)
+	}
+
+	fsys := fstest.MapFS{
+		"20210101000000_create.up.sql": {
+			Data: []byte("CREATE TABLE migrate_test (id int)"),
+		},
+		"20210101000000_create.down.sql": {
+			Data: []byte("DROP TABLE migrate_test"),
+		},
+	}
+
+	newMigrator := func(fsys fstest.MapFS) *migrate.Migrator {
+		migrations := migrate.NewMigrations()
+		require.NoError(t, migrations.Discover(fsys))
+
+		migrator := migrate.NewMigrator(db, migrations,
+			migrate.WithTableName("test_migrations"),
+			migrate.WithLocksTableName("test_migration_locks"))
+		require.NoError(t, migrator.Init(ctx))
+		return migrator
+	}
+
+	migrator := newMigrator(fsys)
+
+	group, err := migrator.Migrate(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), group.ID)
+	require.Len(t, group.Migrations, 1)
+
+	_, err = db.NewSelect().Table("migrate_test").Count(ctx)
+	require.NoError(t, err)
+
+	group, err = migrator.Rollback(ctx)
+	require.NoError(t, err)
+	require.Len(t, group.Migrations, 1)
+
+	_, err = db.NewSelect().Table("migrate_test").Count(ctx)
+	require.Error(t, err)
+
+	// A failed transactional migration is rolled back.
+	fsys["20210102000000_fill.tx.up.sql"] = &fstest.MapFile{
+		Data: []byte("INSERT INTO migrate_test VALUES (1)\n--bun:split\nSELECT * FROM migrate_missing"),
+	}
+	migrator = newMigrator(fsys)
+
+	_, err = migrator.Migrate(ctx)
+	require.Error(t, err)
+
+	count, err := db.NewSelect().Table("migrate_test").Count(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 0, count)
+
+	_, err = db.NewDropTable().Table("migrate_test").Exec(ctx)
+	require.NoError(t, err)
+}
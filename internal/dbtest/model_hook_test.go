@@ -9,6 +9,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/schema"
 )
 
 var events Events
@@ -180,6 +181,46 @@ func (t *ModelHookTest) AfterDelete(ctx context.Context, query *bun.DeleteQuery)
 	return nil
 }
 
+func TestAfterScanTransform(t *testing.T) {
+	testEachDB(t, testAfterScanTransform)
+}
+
+func testAfterScanTransform(t *testing.T, db *bun.DB) {
+	err := db.ResetModel(ctx, (*EncryptedModel)(nil))
+	require.NoError(t, err)
+
+	_, err = db.NewInsert().Model(&[]EncryptedModel{
+		{ID: 1, Secret: "olleh"},
+		{ID: 2, Secret: "dlrow"},
+	}).Exec(ctx)
+	require.NoError(t, err)
+
+	var models []EncryptedModel
+	err = db.NewSelect().Model(&models).Order("id ASC").Scan(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []EncryptedModel{
+		{ID: 1, Secret: "hello"},
+		{ID: 2, Secret: "world"},
+	}, models)
+}
+
+type EncryptedModel struct {
+	ID     int64
+	Secret string
+}
+
+var _ bun.AfterScanHook = (*EncryptedModel)(nil)
+
+// AfterScan "decrypts" the secret of each scanned row.
+func (m *EncryptedModel) AfterScan(ctx context.Context) error {
+	b := []byte(m.Secret)
+	for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+		b[i], b[j] = b[j], b[i]
+	}
+	m.Secret = string(b)
+	return nil
+}
+
 func assertQueryModel(query interface{ GetModel() bun.Model }) {
 	switch value := query.GetModel().Value(); value.(type) {
 	case *ModelHookTest, *[]ModelHookTest:
@@ -188,3 +229,142 @@ func assertQueryModel(query interface{ GetModel() bun.Model }) {
 		panic(fmt.Errorf("unexpected: %T", value))
 	}
 }
+
+func TestBeforeAppendModelHook(t *testing.T) {
+	testEachDB(t, testBeforeAppendModelHook)
+}
+
+func testBeforeAppendModelHook(t *testing.T, db *bun.DB) {
+	err := db.ResetModel(ctx, (*AppendHookModel)(nil))
+	require.NoError(t, err)
+
+	models := []*AppendHookModel{{ID: 1}, {ID: 2}}
+	_, err = db.NewInsert().Model(&models).Exec(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "insert", models[0].Status)
+	require.Equal(t, "insert", models[1].Status)
+
+	model := &AppendHookModel{ID: 1}
+	_, err = db.NewUpdate().Model(model).WherePK().Exec(ctx)
+	require.NoError(t, err)
+
+	var statuses []string
+	err = db.NewSelect().Model((*AppendHookModel)(nil)).Column("status").Order("id").Scan(ctx, &statuses)
+	require.NoError(t, err)
+	require.Equal(t, []string{"update", "insert"}, statuses)
+
+	_, err = db.NewDelete().Model(&models).WherePK().Exec(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "delete", models[0].Status)
+	require.Equal(t, "delete", models[1].Status)
+
+	count, err := db.NewSelect().Model((*AppendHookModel)(nil)).Count(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 0, count)
+}
+
+type AppendHookModel struct {
+	ID     int64
+	Status string
+}
+
+var _ bun.BeforeAppendModelHook = (*AppendHookModel)(nil)
+
+func (m *AppendHookModel) BeforeAppendModel(ctx context.Context, query schema.QueryAppender) error {
+	switch query.(type) {
+	case *bun.InsertQuery:
+		m.Status = "insert"
+	case *bun.UpdateQuery:
+		m.Status = "update"
+	case *bun.DeleteQuery:
+		m.Status = "delete"
+	}
+	return nil
+}
+
+func TestBeforeAppendQueryHook(t *testing.T) {
+	testEachDB(t, testBeforeAppendQueryHook)
+}
+
+func testBeforeAppendQueryHook(t *testing.T, db *bun.DB) {
+	err := db.ResetModel(ctx, (*TenantModel)(nil))
+	require.NoError(t, err)
+
+	models := []*TenantModel{
+		{ID: 1, TenantID: 1},
+		{ID: 2, TenantID: 2},
+		{ID: 3, TenantID: 1},
+	}
+	_, err = db.NewInsert().Model(&models).Exec(ctx)
+	require.NoError(t, err)
+
+	var selected []TenantModel
+	count, err := db.NewSelect().Model(&selected).ScanAndCount(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 2, count)
+	require.Len(t, selected, 2)
+	require.Equal(t, int64(1), selected[0].ID)
+	require.Equal(t, int64(3), selected[1].ID)
+
+	stmt, err := db.NewSelect().Model((*TenantModel)(nil)).Prepare(ctx)
+	require.NoError(t, err)
+	defer stmt.Close()
+
+	var prepared []TenantModel
+	err = stmt.Scan(ctx, &prepared)
+	require.NoError(t, err)
+	require.Len(t, prepared, 2)
+
+	_, err = db.NewDelete().Model((*TenantModel)(nil)).Where("1 = 1").Exec(ctx)
+	require.NoError(t, err)
+
+	var ids []int64
+	err = db.NewSelect().Table("tenant_models").Column("id").Scan(ctx, &ids)
+	require.NoError(t, err)
+	require.Equal(t, []int64{2}, ids)
+}
+
+// TestScanAndCountQueryHook checks that both ScanAndCount queries include the conditions
+// added by BeforeAppendQueryHook. Run it with -race to detect the concurrent hook calls.
+func TestScanAndCountQueryHook(t *testing.T) {
+	testEachDB(t, testScanAndCountQueryHook)
+}
+
+func testScanAndCountQueryHook(t *testing.T, db *bun.DB) {
+	err := db.ResetModel(ctx, (*TenantModel)(nil))
+	require.NoError(t, err)
+
+	models := []*TenantModel{
+		{ID: 1, TenantID: 1},
+		{ID: 2, TenantID: 2},
+		{ID: 3, TenantID: 1},
+	}
+	_, err = db.NewInsert().Model(&models).Exec(ctx)
+	require.NoError(t, err)
+
+	for i := 0; i < 10; i++ {
+		var selected []TenantModel
+		count, err := db.NewSelect().Model(&selected).Limit(1).ScanAndCount(ctx)
+		require.NoError(t, err)
+		require.Equal(t, 2, count)
+		require.Len(t, selected, 1)
+		require.Equal(t, int64(1), selected[0].TenantID)
+	}
+}
+
+type TenantModel struct {
+	ID       int64
+	TenantID int64
+}
+
+var _ bun.BeforeAppendQueryHook = (*TenantModel)(nil)
+
+func (*TenantModel) BeforeAppendQuery(ctx context.Context, query schema.QueryAppender) error {
+	switch q := query.(type) {
+	case *bun.SelectQuery:
+		q.Where("tenant_id = ?", 1).Order("id")
+	case *bun.DeleteQuery:
+		q.Where("tenant_id = ?", 1)
+	}
+	return nil
+}
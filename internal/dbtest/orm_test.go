@@ -2,9 +2,12 @@ package dbtest_test
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"os"
 	"reflect"
+	"sort"
 	"strings"
 	"testing"
 	"time"
@@ -14,6 +17,7 @@ import (
 	"github.com/uptrace/bun"
 	"github.com/uptrace/bun/dbfixture"
 	"github.com/uptrace/bun/dialect"
+	"github.com/uptrace/bun/dialect/feature"
 )
 
 func TestORM(t *testing.T) {
@@ -27,7 +31,24 @@ func TestORM(t *testing.T) {
 		{"testAuthorRelations", testAuthorRelations},
 		{"testGenreRelations", testGenreRelations},
 		{"testTranslationRelations", testTranslationRelations},
+		{"testMissingRelation", testMissingRelation},
+		{"testGroupByAll", testGroupByAll},
 		{"testBulkUpdate", testBulkUpdate},
+		{"testBulkUpdateReturning", testBulkUpdateReturning},
+		{"testPrepare", testPrepare},
+		{"testConsistentScanAndCount", testConsistentScanAndCount},
+		{"testScanAndCountTx", testScanAndCountTx},
+		{"testNewRaw", testNewRaw},
+		{"testScanScalars", testScanScalars},
+		{"testScanMapColumns", testScanMapColumns},
+		{"testDistinctColumns", testDistinctColumns},
+		{"testScanStrict", testScanStrict},
+		{"testRelationAs", testRelationAs},
+		{"testScanOne", testScanOne},
+		{"testRandomSample", testRandomSample},
+		{"testM2MRelationApply", testM2MRelationApply},
+		{"testJoinModel", testJoinModel},
+		{"testExists", testExists},
 	}
 
 	testEachDB(t, func(t *testing.T, db *bun.DB) {
@@ -221,6 +242,80 @@ func testTranslationRelations(t *testing.T, db *bun.DB) {
 	}, translation)
 }
 
+func testMissingRelation(t *testing.T, db *bun.DB) {
+	model := &Translation{ID: 1003, BookID: 999, Lang: "xx"}
+	_, err := db.NewInsert().Model(model).Exec(ctx)
+	require.NoError(t, err)
+	defer func() {
+		_, err := db.NewDelete().Model(model).WherePK().Exec(ctx)
+		require.NoError(t, err)
+	}()
+
+	var translations []Translation
+
+	err = db.NewSelect().
+		Model(&translations).
+		Column("tr.*").
+		Relation("Book").
+		OrderExpr("tr.id DESC").
+		Scan(ctx)
+	require.NoError(t, err)
+	require.Len(t, translations, 4)
+	require.Nil(t, translations[0].Book)
+	require.NotNil(t, translations[3].Book)
+
+	// Scan again reusing the slice elements.
+	err = db.NewSelect().
+		Model(&translations).
+		Column("tr.*").
+		Relation("Book").
+		OrderExpr("tr.id ASC").
+		Scan(ctx)
+	require.NoError(t, err)
+	require.Len(t, translations, 4)
+	require.NotNil(t, translations[0].Book)
+	require.Equal(t, 100, translations[0].Book.ID)
+	require.Nil(t, translations[3].Book)
+
+	translation := &Translation{Book: new(Book)}
+	err = db.NewSelect().
+		Model(translation).
+		Column("tr.*").
+		Relation("Book").
+		Where("tr.id = ?", 1003).
+		Scan(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 999, translation.BookID)
+	require.Nil(t, translation.Book)
+}
+
+func testGroupByAll(t *testing.T, db *bun.DB) {
+	type BookStats struct {
+		AuthorID int
+		Prefix   string
+		Num      int
+	}
+
+	q := db.NewSelect().
+		Model((*Book)(nil)).
+		Column("author_id").
+		ColumnExpr("substr(book.title, 1, 4) AS prefix").
+		ColumnExpr("count(*) AS num").
+		GroupByAll()
+
+	var stats []BookStats
+	err := q.OrderExpr("author_id ASC").Scan(ctx, &stats)
+	require.NoError(t, err)
+	require.Equal(t, []BookStats{
+		{AuthorID: 10, Prefix: "book", Num: 2},
+		{AuthorID: 11, Prefix: "book", Num: 1},
+	}, stats)
+
+	count, err := q.Count(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 2, count)
+}
+
 func testBulkUpdate(t *testing.T, db *bun.DB) {
 	if db.Dialect().Name() == dialect.MySQL5 {
 		t.Skip()
@@ -257,6 +352,472 @@ func testBulkUpdate(t *testing.T, db *bun.DB) {
 	}
 }
 
+func testBulkUpdateReturning(t *testing.T, db *bun.DB) {
+	if !db.Dialect().Features().Has(feature.Returning) {
+		t.Skip()
+	}
+
+	var books []Book
+	err := db.NewSelect().Model(&books).Order("id ASC").Scan(ctx)
+	require.NoError(t, err)
+
+	for i := range books {
+		books[i].Title = fmt.Sprintf("updated %d", books[i].ID)
+	}
+
+	// SQLite does not allow table references in RETURNING and returns only the updated table.
+	returning := "book.*"
+	if db.Dialect().Name() == dialect.SQLite {
+		returning = "*"
+	}
+
+	var updated []Book
+	res, err := db.NewUpdate().
+		With("_data", db.NewValues(&books)).
+		Model(&updated).
+		Table("_data").
+		Set("title = _data.title || '!'").
+		Where("book.id = _data.id").
+		Returning(returning).
+		Exec(ctx)
+	require.NoError(t, err)
+
+	n, err := res.RowsAffected()
+	require.NoError(t, err)
+	require.Equal(t, len(books), int(n))
+
+	require.Len(t, updated, len(books))
+	sort.Slice(updated, func(i, j int) bool {
+		return updated[i].ID < updated[j].ID
+	})
+	for i := range books {
+		require.Equal(t, books[i].ID, updated[i].ID)
+		require.Equal(t, books[i].AuthorID, updated[i].AuthorID)
+		require.Equal(t, books[i].Title+"!", updated[i].Title)
+	}
+}
+
+func testPrepare(t *testing.T, db *bun.DB) {
+	db = bun.NewDB(db.DB, db.Dialect())
+
+	var events []*bun.QueryEvent
+	db.AddQueryHook(&queryHook{
+		beforeQuery: func(ctx context.Context, event *bun.QueryEvent) context.Context {
+			events = append(events, event)
+			return ctx
+		},
+	})
+
+	stmt, err := db.NewSelect().
+		Model((*Book)(nil)).
+		Where("author_id = ?", 10).
+		OrderExpr("id ASC").
+		Prepare(ctx)
+	require.NoError(t, err)
+	defer stmt.Close()
+
+	// Without args, the statement uses the args bound by the query.
+	var bound []Book
+	err = stmt.Scan(ctx, &bound)
+	require.NoError(t, err)
+	require.Len(t, bound, 2)
+	require.Len(t, events, 1)
+	require.Equal(t, "SELECT", events[0].Operation())
+	require.Equal(t, []interface{}{10}, events[0].QueryArgs)
+
+	for _, test := range []struct {
+		authorID int
+		ids      []int
+	}{
+		{10, []int{100, 101}},
+		{11, []int{102}},
+		{12, nil},
+	} {
+		var books []Book
+		err := stmt.Scan(ctx, &books, test.authorID)
+		require.NoError(t, err)
+
+		var ids []int
+		for _, book := range books {
+			ids = append(ids, book.ID)
+		}
+		require.Equal(t, test.ids, ids)
+	}
+
+	book := new(Book)
+	err = stmt.Scan(ctx, book, 12)
+	require.Equal(t, sql.ErrNoRows, err)
+
+	updateStmt, err := db.NewUpdate().
+		Model((*Book)(nil)).
+		Set("title = ?", "").
+		Where("id = ?", 0).
+		Prepare(ctx)
+	require.NoError(t, err)
+	defer updateStmt.Close()
+
+	res, err := updateStmt.ExecContext(ctx, "prepared", 100)
+	require.NoError(t, err)
+
+	n, err := res.RowsAffected()
+	require.NoError(t, err)
+	require.Equal(t, int64(1), n)
+
+	err = db.NewSelect().Model(book).Where("id = ?", 100).Scan(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "prepared", book.Title)
+}
+
+func testConsistentScanAndCount(t *testing.T, db *bun.DB) {
+	var books []Book
+	count, err := db.NewSelect().
+		Model(&books).
+		OrderExpr("id ASC").
+		Limit(1).
+		ConsistentScanAndCount(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 3, count)
+	require.Len(t, books, 1)
+	require.Equal(t, 100, books[0].ID)
+
+	err = db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		_, err := tx.NewDelete().Model((*Book)(nil)).Where("id = ?", 102).Exec(ctx)
+		require.NoError(t, err)
+
+		books = nil
+		count, err := tx.NewSelect().
+			Model(&books).
+			OrderExpr("id ASC").
+			ConsistentScanAndCount(ctx)
+		require.NoError(t, err)
+		require.Equal(t, 2, count)
+		require.Len(t, books, 2)
+
+		return errors.New("rollback")
+	})
+	require.EqualError(t, err, "rollback")
+}
+
+func testScanAndCountTx(t *testing.T, db *bun.DB) {
+	err := db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		var books []Book
+		count, err := tx.NewSelect().
+			Model(&books).
+			OrderExpr("id ASC").
+			Limit(1).
+			ScanAndCount(ctx)
+		require.NoError(t, err)
+		require.Equal(t, 3, count)
+		require.Len(t, books, 1)
+		return nil
+	})
+	require.NoError(t, err)
+
+	// The count query is not run after the scan fails in a transaction.
+	tx, err := db.BeginTx(ctx, nil)
+	require.NoError(t, err)
+	defer tx.Rollback()
+
+	var txBooks []Book
+	_, err = tx.NewSelect().Model(&txBooks).Where("missing_column = 1").ScanAndCount(ctx)
+	require.Error(t, err)
+	require.Len(t, strings.Split(err.Error(), "\n"), 1)
+
+	var books []Book
+	_, err = db.NewSelect().Model(&books).Where("missing_column = 1").ScanAndCount(ctx)
+	require.Error(t, err)
+	require.Len(t, strings.Split(err.Error(), "\n"), 2)
+}
+
+func testNewRaw(t *testing.T, db *bun.DB) {
+	var books []Book
+	err := db.NewRaw("SELECT * FROM ? WHERE author_id = ? ORDER BY id", bun.Ident("books"), 10).
+		Scan(ctx, &books)
+	require.NoError(t, err)
+	require.Len(t, books, 2)
+	require.Equal(t, 100, books[0].ID)
+	require.Equal(t, 10, books[0].AuthorID)
+
+	book := new(Book)
+	err = db.NewRaw("SELECT * FROM books WHERE id = ?", 999).Scan(ctx, book)
+	require.Equal(t, sql.ErrNoRows, err)
+
+	var id int
+	var title string
+	err = db.NewRaw("SELECT id, title FROM books WHERE id = ?", 101).Scan(ctx, &id, &title)
+	require.NoError(t, err)
+	require.Equal(t, 101, id)
+
+	err = db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		_, err := tx.NewRaw("DELETE FROM books WHERE id = ?", 102).Exec(ctx)
+		require.NoError(t, err)
+
+		var count int
+		err = tx.NewRaw("SELECT count(*) FROM books").Scan(ctx, &count)
+		require.NoError(t, err)
+		require.Equal(t, 2, count)
+
+		return errors.New("rollback")
+	})
+	require.EqualError(t, err, "rollback")
+}
+
+func testScanScalars(t *testing.T, db *bun.DB) {
+	var count, maxID int
+	err := db.NewSelect().
+		Model((*Book)(nil)).
+		ColumnExpr("count(*)").
+		ColumnExpr("max(id)").
+		Scan(ctx, &count, &maxID)
+	require.NoError(t, err)
+	require.Equal(t, 3, count)
+	require.Equal(t, 102, maxID)
+
+	var ids, authorIDs []int64
+	err = db.NewSelect().
+		Model((*Book)(nil)).
+		Column("id", "author_id").
+		OrderExpr("id ASC").
+		Scan(ctx, &ids, &authorIDs)
+	require.NoError(t, err)
+	require.Equal(t, []int64{100, 101, 102}, ids)
+	require.Equal(t, []int64{10, 10, 11}, authorIDs)
+
+	var title string
+	err = db.NewSelect().
+		Model((*Book)(nil)).
+		Column("title").
+		Where("id = ?", 100).
+		Scan(ctx, &title)
+	require.NoError(t, err)
+
+	var titleBytes []byte
+	var id int
+	err = db.NewSelect().
+		Model((*Book)(nil)).
+		Column("title", "id").
+		Where("id = ?", 100).
+		Scan(ctx, &titleBytes, &id)
+	require.NoError(t, err)
+	require.Equal(t, title, string(titleBytes))
+	require.Equal(t, 100, id)
+
+	err = db.NewSelect().Model((*Book)(nil)).Column("id", "author_id").Scan(ctx, &ids)
+	require.Error(t, err)
+}
+
+func testScanMapColumns(t *testing.T, db *bun.DB) {
+	var m map[string][]interface{}
+	err := db.NewSelect().
+		Model((*Book)(nil)).
+		Column("id", "author_id").
+		OrderExpr("id ASC").
+		Scan(ctx, &m)
+	require.NoError(t, err)
+	require.Equal(t, map[string][]interface{}{
+		"id":        {int64(100), int64(101), int64(102)},
+		"author_id": {int64(10), int64(10), int64(11)},
+	}, m)
+
+	m = nil
+	err = db.NewSelect().
+		Model((*Book)(nil)).
+		Column("id").
+		Where("id < 0").
+		Scan(ctx, &m)
+	require.NoError(t, err)
+	require.Equal(t, map[string][]interface{}{"id": {}}, m)
+}
+
+func testDistinctColumns(t *testing.T, db *bun.DB) {
+	var authorIDs []int
+	q := db.NewSelect().
+		Model((*Book)(nil)).
+		DistinctColumns("author_id").
+		OrderExpr("author_id ASC")
+
+	err := q.Scan(ctx, &authorIDs)
+	require.NoError(t, err)
+	require.Equal(t, []int{10, 11}, authorIDs)
+
+	count, err := q.Count(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 2, count)
+}
+
+func testRelationAs(t *testing.T, db *bun.DB) {
+	var books []Book
+	err := db.NewSelect().
+		Model(&books).
+		Column("book.id").
+		RelationAs("Author", "a").
+		RelationAs("Editor", "e").
+		Relation("Editor.Avatar").
+		Where("a.id = ?", 10).
+		Where("e.name = ?", "author 3").
+		Scan(ctx)
+	require.NoError(t, err)
+	require.Len(t, books, 1)
+
+	book := books[0]
+	require.Equal(t, 101, book.ID)
+	require.Equal(t, 10, book.Author.ID)
+	require.Equal(t, "author 1", book.Author.Name)
+	require.NotNil(t, book.Editor)
+	require.Equal(t, 12, book.Editor.ID)
+	require.Equal(t, "author 3", book.Editor.Name)
+	require.Equal(t, 3, book.Editor.Avatar.ID)
+
+	var ids []int
+	err = db.NewSelect().
+		Model((*Book)(nil)).
+		Column("book.id").
+		RelationAs("Author._", "a").
+		RelationAs("Editor._", "e").
+		Where("a.id = e.id").
+		Scan(ctx, &ids)
+	require.NoError(t, err)
+	require.Equal(t, []int{102}, ids)
+
+	err = db.NewSelect().
+		Model(&books).
+		RelationAs("Translations", "t").
+		Scan(ctx)
+	require.EqualError(t, err, `bun: RelationAs("Translations") requires has-one or belongs-to relation`)
+
+	err = db.NewSelect().
+		Model(&books).
+		RelationWithOpts("Author", bun.RelationOpts{JoinType: "RIGHT JOIN"}).
+		Scan(ctx)
+	require.EqualError(t, err, `bun: unsupported JoinType="RIGHT JOIN"`)
+}
+
+func testScanOne(t *testing.T, db *bun.DB) {
+	book := new(Book)
+	err := db.NewSelect().Model(book).Where("id = ?", 100).ScanOne(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 100, book.ID)
+
+	book = new(Book)
+	err = db.NewSelect().Model(book).Where("author_id = ?", 10).ScanOne(ctx)
+	require.Equal(t, bun.ErrMultipleRows, err)
+
+	err = db.NewSelect().Model(book).Where("id = ?", 0).ScanOne(ctx)
+	require.Equal(t, sql.ErrNoRows, err)
+
+	var ids []int
+	err = db.NewSelect().Model((*Book)(nil)).Column("id").Where("id > ?", 100).ScanOne(ctx, &ids)
+	require.Equal(t, bun.ErrMultipleRows, err)
+
+	err = db.NewSelect().Model((*Book)(nil)).Column("id").Where("id > ?", 1000).ScanOne(ctx, &ids)
+	require.Equal(t, sql.ErrNoRows, err)
+}
+
+func testRandomSample(t *testing.T, db *bun.DB) {
+	var books []Book
+	err := db.NewSelect().Model(&books).RandomSample(2).Scan(ctx)
+	require.NoError(t, err)
+	require.Len(t, books, 2)
+	require.NotEqual(t, books[0].ID, books[1].ID)
+}
+
+func testM2MRelationApply(t *testing.T, db *bun.DB) {
+	book := new(Book)
+	err := db.NewSelect().
+		Model(book).
+		Column("book.id").
+		Relation("Genres", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.Where("genre.id > ?", 0).OrderExpr("genre.id DESC")
+		}).
+		Where("book.id = ?", 100).
+		Scan(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []Genre{
+		{ID: 2, Name: "genre 2", Rating: 9999},
+		{ID: 1, Name: "genre 1", Rating: 999},
+	}, book.Genres)
+
+	book = new(Book)
+	err = db.NewSelect().
+		Model(book).
+		Column("book.id").
+		Relation("Genres", func(q *bun.SelectQuery) *bun.SelectQuery {
+			return q.Where("genre.name = ?", "genre 2")
+		}).
+		Where("book.id = ?", 100).
+		Scan(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []Genre{{ID: 2, Name: "genre 2", Rating: 9999}}, book.Genres)
+}
+
+func testJoinModel(t *testing.T, db *bun.DB) {
+	type BookWithWriter struct {
+		Book   `bun:",inherit"`
+		Writer *Author `bun:"-"`
+	}
+
+	var books []BookWithWriter
+	err := db.NewSelect().
+		Model(&books).
+		Column("book.id").
+		JoinModel("Writer", "writer.id = book.author_id").
+		JoinOn("writer.name = ?", "author 1").
+		OrderExpr("book.id ASC").
+		Scan(ctx)
+	require.NoError(t, err)
+	require.Len(t, books, 3)
+
+	require.Equal(t, 100, books[0].ID)
+	require.NotNil(t, books[0].Writer)
+	require.Equal(t, "author 1", books[0].Writer.Name)
+	require.Equal(t, 101, books[1].ID)
+	require.NotNil(t, books[1].Writer)
+	require.Equal(t, 10, books[1].Writer.ID)
+	require.Equal(t, 102, books[2].ID)
+	require.Nil(t, books[2].Writer)
+
+	err = db.NewSelect().Model(&books).JoinModel("Title", "TRUE").Scan(ctx)
+	require.Error(t, err)
+}
+
+func testExists(t *testing.T, db *bun.DB) {
+	exists, err := db.NewSelect().Model((*Book)(nil)).Where("id = ?", 100).Exists(ctx)
+	require.NoError(t, err)
+	require.True(t, exists)
+
+	exists, err = db.NewSelect().Model((*Book)(nil)).Where("id = ?", 999).Exists(ctx)
+	require.NoError(t, err)
+	require.False(t, exists)
+
+	sub := db.NewSelect().Model((*Book)(nil)).ColumnExpr("1").Where("book.author_id = author.id")
+	var authors []Author
+	err = db.NewSelect().Model(&authors).WhereNotExists(sub).Scan(ctx)
+	require.NoError(t, err)
+	require.Len(t, authors, 1)
+	require.Equal(t, 12, authors[0].ID)
+}
+
+func testScanStrict(t *testing.T, db *bun.DB) {
+	book := new(Book)
+	err := db.NewSelect().
+		Model(book).
+		Where("id = ?", 100).
+		ScanStrict(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 100, book.ID)
+
+	var books []Book
+	err = db.NewSelect().
+		Model(&books).
+		Column("id").
+		ColumnExpr("title AS titel").
+		ColumnExpr("author_id AS writer").
+		ColumnExpr("1 AS _ignored").
+		ScanStrict(ctx)
+	require.EqualError(t, err, `bun: Book does not have columns "titel", "writer"`)
+	require.Len(t, books, 3)
+}
+
 type Genre struct {
 	ID     int
 	Name   string
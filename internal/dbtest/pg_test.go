@@ -1,10 +1,13 @@
 package dbtest_test
 
 import (
+	"bytes"
 	"database/sql"
 	"net"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
@@ -45,6 +48,41 @@ func TestPGArray(t *testing.T) {
 	require.Equal(t, []string{"one", "two", "three"}, strs)
 }
 
+func TestPGHStore(t *testing.T) {
+	type Model struct {
+		ID    int
+		Attrs map[string]string `bun:",hstore"`
+	}
+
+	db := pg(t)
+
+	_, err := db.Exec("CREATE EXTENSION IF NOT EXISTS hstore")
+	require.NoError(t, err)
+
+	_, err = db.NewDropTable().Model((*Model)(nil)).IfExists().Exec(ctx)
+	require.NoError(t, err)
+
+	_, err = db.NewCreateTable().Model((*Model)(nil)).Exec(ctx)
+	require.NoError(t, err)
+
+	model1 := &Model{
+		ID:    123,
+		Attrs: map[string]string{"one": "1", "two": "2"},
+	}
+	_, err = db.NewInsert().Model(model1).Exec(ctx)
+	require.NoError(t, err)
+
+	model2 := new(Model)
+	err = db.NewSelect().Model(model2).Where("attrs @> ?", pgdialect.HStore(map[string]string{"one": "1"})).Scan(ctx)
+	require.NoError(t, err)
+	require.Equal(t, model1, model2)
+
+	var attrs map[string]string
+	err = db.NewSelect().Model((*Model)(nil)).Column("attrs").Scan(ctx, pgdialect.HStore(&attrs))
+	require.NoError(t, err)
+	require.Equal(t, model1.Attrs, attrs)
+}
+
 type Recipe struct {
 	bun.BaseModel `bun:"?tenant.recipes"`
 
@@ -273,3 +311,119 @@ func TestIPNet(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, *ipv4Net, model.Network)
 }
+
+func TestPGCopyFrom(t *testing.T) {
+	type Model struct {
+		ID  int64
+		Str string
+	}
+
+	db := pg(t)
+
+	err := db.ResetModel(ctx, (*Model)(nil))
+	require.NoError(t, err)
+
+	res, err := db.NewCopyFrom().
+		Model((*Model)(nil)).
+		Option("FORMAT csv").
+		Exec(ctx, strings.NewReader("1,foo\n2,bar\n3,baz\n"))
+	require.NoError(t, err)
+
+	n, err := res.RowsAffected()
+	require.NoError(t, err)
+	require.Equal(t, int64(3), n)
+
+	var models []Model
+	err = db.NewSelect().Model(&models).Order("id ASC").Scan(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []Model{{1, "foo"}, {2, "bar"}, {3, "baz"}}, models)
+
+	_, err = db.NewCopyFrom().
+		Model((*Model)(nil)).
+		Option("FORMAT csv").
+		Exec(ctx, strings.NewReader("invalid\n"))
+	require.Error(t, err)
+
+	_, err = db.Exec("SELECT 1")
+	require.NoError(t, err)
+}
+
+func TestPGCopyTo(t *testing.T) {
+	type Model struct {
+		ID  int64
+		Str string
+	}
+
+	db := pg(t)
+
+	err := db.ResetModel(ctx, (*Model)(nil))
+	require.NoError(t, err)
+
+	res, err := db.NewCopyFrom().
+		Model((*Model)(nil)).
+		Exec(ctx, strings.NewReader("1\tfoo\n2\tbar\n"))
+	require.NoError(t, err)
+
+	n, err := res.RowsAffected()
+	require.NoError(t, err)
+	require.Equal(t, int64(2), n)
+
+	var buf bytes.Buffer
+	res, err = db.CopyTo(ctx, &buf, "COPY (SELECT * FROM ? ORDER BY id) TO STDOUT WITH (FORMAT csv)",
+		bun.Ident("models"))
+	require.NoError(t, err)
+	require.Equal(t, "1,foo\n2,bar\n", buf.String())
+
+	n, err = res.RowsAffected()
+	require.NoError(t, err)
+	require.Equal(t, int64(2), n)
+}
+
+func TestPGInsertCopy(t *testing.T) {
+	type Model struct {
+		ID    int64 `bun:",pk,autoincrement"`
+		Str   string
+		Ptr   *string
+		Items []string `bun:",array"`
+		Bytes []byte
+		Time  time.Time
+	}
+
+	db := pg(t)
+
+	err := db.ResetModel(ctx, (*Model)(nil))
+	require.NoError(t, err)
+
+	str := "it's a \\ \t test\n"
+	tm := time.Date(2021, 7, 1, 12, 30, 0, 0, time.UTC)
+	models := []Model{
+		{Str: str, Items: []string{"a", `b"c`}, Bytes: []byte{0, '\\', 0xff}, Time: tm},
+		{Str: "bar", Ptr: &str},
+	}
+	res, err := db.NewInsert().Model(&models).Copy().Exec(ctx)
+	require.NoError(t, err)
+
+	n, err := res.RowsAffected()
+	require.NoError(t, err)
+	require.Equal(t, int64(2), n)
+
+	var got []Model
+	err = db.NewSelect().Model(&got).Order("id ASC").Scan(ctx)
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	require.Equal(t, str, got[0].Str)
+	require.Nil(t, got[0].Ptr)
+	require.Equal(t, []string{"a", `b"c`}, got[0].Items)
+	require.Equal(t, []byte{0, '\\', 0xff}, got[0].Bytes)
+	require.True(t, tm.Equal(got[0].Time))
+	require.Equal(t, str, *got[1].Ptr)
+
+	type JSONModel struct {
+		ID    int64
+		Attrs map[string]interface{}
+	}
+
+	_, err = db.NewInsert().Model(&[]JSONModel{{ID: 1}}).Copy().Exec(ctx)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "bun: COPY does not support")
+}
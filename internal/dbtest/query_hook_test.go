@@ -2,6 +2,7 @@ package dbtest_test
 
 import (
 	"context"
+	"strings"
 	"testing"
 	"time"
 
@@ -30,6 +31,8 @@ func testQueryHook(t *testing.T, db *bun.DB) {
 			b, err := event.QueryAppender.AppendQuery(schema.NewNopFormatter(), nil)
 			require.NoError(t, err)
 			require.Equal(t, "SELECT * FROM (SELECT 1) AS t WHERE (? = ?)", string(b))
+			require.Equal(t, []interface{}{"foo", "bar"}, event.Args())
+			require.Equal(t, "SELECT", event.Operation())
 
 			return ctx
 		}
@@ -39,6 +42,33 @@ func testQueryHook(t *testing.T, db *bun.DB) {
 		hook.require(t)
 	}
 
+	{
+		type Model struct {
+			ID  int64 `bun:",pk"`
+			Str string
+		}
+
+		hook.reset()
+		hook.beforeQuery = func(
+			ctx context.Context, event *bun.QueryEvent,
+		) context.Context {
+			b, err := event.QueryAppender.AppendQuery(schema.NewNopFormatter(), nil)
+			require.NoError(t, err)
+			require.Equal(t, 2, strings.Count(string(b), "?"))
+			require.Equal(t, []interface{}{"hello", int64(1)}, event.Args())
+
+			return ctx
+		}
+
+		_, err := db.NewUpdate().
+			Model(&Model{ID: 1, Str: "hello"}).
+			Column("str").
+			WherePK().
+			Exec(ctx)
+		require.Error(t, err) // table does not exist
+		hook.require(t)
+	}
+
 	{
 		hook.reset()
 		hook.beforeQuery = func(
@@ -70,6 +100,83 @@ func testQueryHook(t *testing.T, db *bun.DB) {
 	}
 }
 
+func TestQueryComment(t *testing.T) {
+	testEachDB(t, testQueryComment)
+}
+
+func testQueryComment(t *testing.T, db *bun.DB) {
+	type traceKey struct{}
+
+	db = bun.NewDB(db.DB, db.Dialect(), bun.WithQueryComment(
+		func(ctx context.Context) map[string]string {
+			if traceparent, ok := ctx.Value(traceKey{}).(string); ok {
+				return map[string]string{"traceparent": traceparent}
+			}
+			return nil
+		},
+	))
+
+	hook := &queryHook{}
+	db.AddQueryHook(hook)
+
+	{
+		hook.reset()
+		hook.beforeQuery = func(
+			ctx context.Context, event *bun.QueryEvent,
+		) context.Context {
+			require.Equal(t, "SELECT 1 /*action='test',route='%2Fbooks%2A%2F%27'*/", event.Query)
+			return ctx
+		}
+
+		var num int
+		err := db.NewSelect().
+			ColumnExpr("1").
+			Comment("route", "/books*/'").
+			Comment("action", "test").
+			Scan(ctx, &num)
+		require.NoError(t, err)
+		require.Equal(t, 1, num)
+		hook.require(t)
+	}
+
+	{
+		hook.reset()
+		hook.beforeQuery = func(
+			ctx context.Context, event *bun.QueryEvent,
+		) context.Context {
+			require.Equal(t, "SELECT 1 /*action='test',traceparent='00-01-02-01'*/", event.Query)
+			return ctx
+		}
+
+		ctx := context.WithValue(ctx, traceKey{}, "00-01-02-01")
+
+		var num int
+		err := db.NewSelect().ColumnExpr("1").Comment("action", "test").Scan(ctx, &num)
+		require.NoError(t, err)
+		require.Equal(t, 1, num)
+		hook.require(t)
+	}
+
+	{
+		hook.reset()
+		hook.beforeQuery = func(
+			ctx context.Context, event *bun.QueryEvent,
+		) context.Context {
+			require.Equal(t, "SELECT 1 /*action='test',widget='dashboard'*/", event.Query)
+			return ctx
+		}
+
+		ctx := bun.ContextWithComment(ctx, "widget", "dashboard")
+		ctx = bun.ContextWithComment(ctx, "action", "list")
+
+		var num int
+		err := db.NewSelect().ColumnExpr("1").Comment("action", "test").Scan(ctx, &num)
+		require.NoError(t, err)
+		require.Equal(t, 1, num)
+		hook.require(t)
+	}
+}
+
 type queryHook struct {
 	startTime time.Time
 	endTime   time.Time
@@ -1,6 +1,8 @@
 package dbtest_test
 
 import (
+	"database/sql"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"path/filepath"
@@ -9,8 +11,12 @@ import (
 	"time"
 
 	"github.com/bradleyjkemp/cupaloy"
+	"github.com/stretchr/testify/require"
 
 	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/feature"
+	"github.com/uptrace/bun/dialect/mysqldialect"
+	"github.com/uptrace/bun/dialect/pgdialect"
 	"github.com/uptrace/bun/schema"
 )
 
@@ -42,6 +48,14 @@ func TestQuery(t *testing.T) {
 		DeletedAt time.Time `bun:",soft_delete"`
 	}
 
+	type Payment struct {
+		ID        int64
+		AccountID int64
+		Currency  string
+		Amount    int64
+		CreatedAt time.Time
+	}
+
 	queries := []func(db *bun.DB) schema.QueryAppender{
 		func(db *bun.DB) schema.QueryAppender {
 			return db.NewValues(&Model{42, "hello"})
@@ -466,6 +480,482 @@ func TestQuery(t *testing.T) {
 			}
 			return db.NewSelect().Where("?a + ?b AS ?alias", params)
 		},
+		func(db *bun.DB) schema.QueryAppender {
+			return db.NewSelect().Model(new(Story)).Relation("User._").Where("user.name = 'hello'")
+		},
+		func(db *bun.DB) schema.QueryAppender {
+			return db.NewSelect().Model(new(Story)).JoinRelation("User").Order("user.name")
+		},
+		func(db *bun.DB) schema.QueryAppender {
+			return db.NewSelect().
+				ColumnExpr("lower(?) AS ?", bun.Ident("user.name"), bun.Ident("lname")).
+				Table("users").
+				Where("? = ?", bun.Ident("name"), "hello")
+		},
+		func(db *bun.DB) schema.QueryAppender {
+			src := db.NewValues(&[]Model{{42, "hello"}})
+			return db.NewMerge().
+				With("src", src).
+				Model(new(Model)).
+				Using("src").
+				On("model.id = src.id").
+				WhenMatched("UPDATE SET str = src.str").
+				WhenNotMatched("INSERT (id, str) VALUES (src.id, src.str)")
+		},
+		func(db *bun.DB) schema.QueryAppender {
+			return db.NewSelect().
+				Model(new(Model)).
+				Column("str").
+				ColumnExpr("lower(?) AS lstr", bun.Ident("str")).
+				ColumnExpr("COUNT(*) AS num").
+				GroupByAll()
+		},
+		func(db *bun.DB) schema.QueryAppender {
+			return db.NewSelect().Model(new(Story)).Relation("User").GroupByAll()
+		},
+		func(db *bun.DB) schema.QueryAppender {
+			return db.NewSelect().
+				Model(new(Model)).
+				Column("str").
+				ColumnExpr("count(*) AS num").
+				ColumnExpr("max(id) AS ?", bun.Ident("max_id")).
+				Group("str").
+				Having("num > ? AND max_id < ?", 1, 100).
+				Having("str != 'num' AND model.num > 0").
+				Order("num DESC").
+				OrderExpr("max_id + 1").
+				ExpandAliases()
+		},
+		func(db *bun.DB) schema.QueryAppender {
+			return db.NewSelect().
+				Model(new(Model)).
+				Join("JOIN models AS m2 ON m2.id > model.id").
+				WhereColumn("model.str", "=", "m2.str").
+				WhereColumn("model.id", "IS DISTINCT FROM", "m2.id")
+		},
+		func(db *bun.DB) schema.QueryAppender {
+			return db.NewDelete().Model(new(Model)).WhereColumn("id", "= 1; DROP TABLE models; --", "str")
+		},
+		func(db *bun.DB) schema.QueryAppender {
+			q1 := db.NewSelect().
+				Model(new(Model)).
+				Where("id > ?", 1).
+				OrderExpr("str = ? DESC", "a").
+				Limit(1)
+			q2 := db.NewSelect().
+				Model(new(Model)).
+				Where("id > ?", 2).
+				OrderExpr("coalesce(str, ?)", "b").
+				Limit(1)
+			return q1.UnionAll(q2)
+		},
+		func(db *bun.DB) schema.QueryAppender {
+			return db.NewCopyFrom().Model(new(Model)).Option("FORMAT csv").Option("HEADER")
+		},
+		func(db *bun.DB) schema.QueryAppender {
+			return db.NewSelect().
+				Model(new(Model)).
+				Column("id").
+				ColumnCase(func(c *bun.CaseBuilder) {
+					c.When("id > ?", 10).Then("big").
+						When("id > ?", 0).ThenExpr("upper(?)", bun.Ident("str")).
+						Else(nil).
+						As("label")
+				})
+		},
+		func(db *bun.DB) schema.QueryAppender {
+			return db.NewSelect().Model(new(Model)).DistinctColumns("id", "str")
+		},
+		func(db *bun.DB) schema.QueryAppender {
+			return db.NewSelect().
+				Model(new(Model)).
+				ModelTableExpr("model_view AS model").
+				ViewColumns(map[string]string{"str": "title"}).
+				Where("title IS NOT NULL")
+		},
+		func(db *bun.DB) schema.QueryAppender {
+			return db.NewSelect().Model(new(Model)).Where("id = ?", 1).ForUpdate().Wait(5)
+		},
+		func(db *bun.DB) schema.QueryAppender {
+			return db.NewSelect().Model(new(Model)).TableSchema("reporting").Where("?TableName IS NOT NULL")
+		},
+		func(db *bun.DB) schema.QueryAppender {
+			return db.NewUpdate().Model(&Model{ID: 1, Str: "hello"}).TableSchema("reporting").WherePK()
+		},
+		func(db *bun.DB) schema.QueryAppender {
+			type Order struct {
+				bun.BaseModel `bun:"reporting.orders"`
+
+				ID int64
+			}
+			return db.NewSelect().Model(new(Order)).TableSchema("archive")
+		},
+		func(db *bun.DB) schema.QueryAppender {
+			return db.NewSelect().
+				Model(new(Payment)).
+				Column("id", "amount").
+				RunningTotal("amount", "created_at", "total")
+		},
+		func(db *bun.DB) schema.QueryAppender {
+			return db.NewSelect().
+				Model(new(Payment)).
+				ColumnExpr("payment.*").
+				RunningTotal("payment.amount", "created_at DESC", "total", "account_id", "currency")
+		},
+		func(db *bun.DB) schema.QueryAppender {
+			return db.NewTruncateTable().Model(new(Model)).RestartIdentity().Cascade()
+		},
+		func(db *bun.DB) schema.QueryAppender {
+			return db.NewTruncateTable().Model(new(Model)).Table("stories", "users").ContinueIdentity()
+		},
+		func(db *bun.DB) schema.QueryAppender {
+			return db.NewDelete().
+				Model(new(Model)).
+				Where("str <> ?", "keep").
+				WhereIn("id", db.NewSelect().Table("staging").Column("id").Where("created_at < ?", "2021-01-01"))
+		},
+		func(db *bun.DB) schema.QueryAppender {
+			return db.NewSelect().Model(new(Model)).WhereIn("model.id", []int{1, 2, 3})
+		},
+		func(db *bun.DB) schema.QueryAppender {
+			zero := 0
+			return db.NewSelect().
+				Model(new(Model)).
+				WhereIfNotZero("model.str", "").
+				WhereIfNotZero("model.id", 0).
+				WhereIfNotZero("model.created_at", time.Time{}).
+				WhereIfNotZero("model.str", "hello").
+				WhereIfNotZero("model.id", &zero)
+		},
+		func(db *bun.DB) schema.QueryAppender {
+			type Model struct {
+				ID int64 `bun:",pk,autoincrement"`
+			}
+			return db.NewInsert().Model(new(Model))
+		},
+		func(db *bun.DB) schema.QueryAppender {
+			type Model struct {
+				ID int64 `bun:",pk,autoincrement"`
+			}
+			return db.NewInsert().Model(&[]Model{{}, {}})
+		},
+		func(db *bun.DB) schema.QueryAppender {
+			return db.NewSelect().Model(new(Model)).Where("str IS NOT NULL").RandomSample(10)
+		},
+		func(db *bun.DB) schema.QueryAppender {
+			allowed := map[string]bool{"id": true, "str": true}
+			return db.NewSelect().Model(new(Model)).OrderSafe(allowed, "str:desc", "id")
+		},
+		func(db *bun.DB) schema.QueryAppender {
+			allowed := map[string]bool{"id": true}
+			return db.NewSelect().Model(new(Model)).OrderSafe(allowed, "id:asc", "str; DROP TABLE models:asc")
+		},
+		func(db *bun.DB) schema.QueryAppender {
+			return db.NewSelect().Model(new(Model)).WithTotalCount("total").Order("id").Limit(10).Offset(20)
+		},
+		func(db *bun.DB) schema.QueryAppender {
+			token := base64.RawURLEncoding.EncodeToString([]byte(`["foo",5]`))
+			return db.NewSelect().Model(new(Model)).Cursor("str", "desc").CursorAfter(token).Limit(10)
+		},
+		func(db *bun.DB) schema.QueryAppender {
+			return db.NewSelect().Model(new(Model)).Cursor("str", "asc").CursorAfter("not a cursor")
+		},
+		func(db *bun.DB) schema.QueryAppender {
+			q := db.NewInsert().Model(&Model{ID: 42, Str: "hello"})
+			if db.Dialect().Features().Has(feature.OnDuplicateKey) {
+				return q.On("DUPLICATE KEY UPDATE").SetExcludedColumns()
+			}
+			return q.On("CONFLICT (id) DO UPDATE").SetExcludedColumns().Set("updated = TRUE")
+		},
+		func(db *bun.DB) schema.QueryAppender {
+			src := db.NewValues(&[]Model{{42, "hello"}, {43, "world"}})
+			return db.NewMerge().
+				Model(new(Model)).
+				UsingExpr("(?) AS src (?Columns)", src).
+				On("model.id = src.id").
+				WhenMatched("UPDATE SET str = src.str").
+				WhenNotMatched("INSERT (id, str) VALUES (src.id, src.str)")
+		},
+		func(db *bun.DB) schema.QueryAppender {
+			type Comment struct {
+				ID      int64
+				StoryID int64
+				Story   *Story `bun:"rel:belongs-to,on_delete:cascade"`
+				UserID  int64
+				User    *User `bun:"rel:belongs-to"`
+			}
+			return db.NewCreateTable().Model(new(Comment)).WithForeignKeys()
+		},
+		func(db *bun.DB) schema.QueryAppender {
+			return db.NewCreateTable().
+				Model(new(Model)).
+				PartitionBy("RANGE (id)").
+				TableSpace("fast_ssd")
+		},
+		func(db *bun.DB) schema.QueryAppender {
+			return db.NewRenameColumn().Model(new(Model)).Rename("str", "title")
+		},
+		func(db *bun.DB) schema.QueryAppender {
+			type Model struct {
+				ID  int64
+				Str string `bun:"type:varchar(100),notnull"`
+			}
+			return db.NewModifyColumn().Model(new(Model)).Column("str")
+		},
+		func(db *bun.DB) schema.QueryAppender {
+			type Model struct {
+				ID    int64
+				Title string
+				Num   int
+			}
+			models := []Model{{42, "hello", 1}, {43, "world", 2}}
+			return db.NewUpdate().Model(&models).Column("num").Bulk()
+		},
+		func(db *bun.DB) schema.QueryAppender {
+			return db.NewSelect().
+				Model(new(Story)).
+				Relation("User", func(q *bun.SelectQuery) *bun.SelectQuery {
+					return q.JoinOn("user.name = ?", "hello")
+				})
+		},
+		func(db *bun.DB) schema.QueryAppender {
+			return db.NewSelect().
+				Model(new(Story)).
+				RelationWithOpts("User", bun.RelationOpts{
+					JoinType: bun.InnerJoin,
+					Apply: func(q *bun.SelectQuery) *bun.SelectQuery {
+						return q.Column("name")
+					},
+				})
+		},
+		func(db *bun.DB) schema.QueryAppender {
+			type Image struct {
+				ID            int64
+				TrackableID   int64
+				TrackableType string
+			}
+			type Post struct {
+				ID    int64
+				Image *Image `bun:"rel:has-one,join:\"id=trackable_id,type=trackable_type\",polymorphic:article"`
+			}
+			return db.NewSelect().Model(new(Post)).Relation("Image")
+		},
+		func(db *bun.DB) schema.QueryAppender {
+			return db.NewSelect().
+				Model(new(Payment)).
+				Column("id").
+				ColumnWindow("row_number()", func(w *bun.Window) {
+					w.PartitionBy("account_id").OrderBy("created_at DESC").As("num")
+				}).
+				ColumnWindow("sum(?)", func(w *bun.Window) {
+					w.OrderBy("id").Frame("ROWS BETWEEN 1 PRECEDING AND CURRENT ROW").As("total")
+				}, bun.Ident("amount"))
+		},
+		func(db *bun.DB) schema.QueryAppender {
+			return db.NewSelect().
+				Model(new(Payment)).
+				Column("id").
+				Window("w", func(w *bun.Window) {
+					w.PartitionBy("account_id")
+				}).
+				ColumnWindow("rank()", func(w *bun.Window) {
+					w.Base("w").OrderBy("amount DESC").As("rank")
+				}).
+				ColumnWindow("count(*)", func(w *bun.Window) {
+					w.Base("w").As("count")
+				})
+		},
+		func(db *bun.DB) schema.QueryAppender {
+			sub := db.NewSelect().Model(new(Story)).Column("user_id").Where("name = ?", "hello")
+			return db.NewSelect().
+				Model(new(User)).
+				Where("id IN (?)", sub).
+				TableExpr("(?) AS sub", db.NewSelect().Model(new(Story)).ColumnExpr("max(id) AS max_id"))
+		},
+		func(db *bun.DB) schema.QueryAppender {
+			sub := db.NewSelect().Model(new(Story)).Wait(5)
+			return db.NewSelect().Model(new(User)).Where("id IN (?)", sub)
+		},
+		func(db *bun.DB) schema.QueryAppender {
+			sub := db.NewSelect().Model(new(Story)).ColumnExpr("1").Where("story.user_id = user.id")
+			return db.NewSelect().Model(new(User)).WhereExists(sub)
+		},
+		func(db *bun.DB) schema.QueryAppender {
+			sub := db.NewSelect().Model(new(Story)).ColumnExpr("1").Where("story.user_id = user.id")
+			return db.NewDelete().Model(new(User)).WhereNotExists(sub)
+		},
+		func(db *bun.DB) schema.QueryAppender {
+			type Model struct {
+				ID     int64
+				Tags   []string  `bun:"type:text[]"`
+				Matrix [][]int64 `bun:",array"`
+			}
+			return db.NewCreateTable().Model(new(Model))
+		},
+		func(db *bun.DB) schema.QueryAppender {
+			type Model struct {
+				ID     int64
+				Tags   []string  `bun:"type:text[]"`
+				Matrix [][]int64 `bun:",array"`
+			}
+			return db.NewInsert().Model(&Model{
+				ID:     1,
+				Tags:   []string{"foo", "bar"},
+				Matrix: [][]int64{{1, 2}, {3, 4}},
+			})
+		},
+		func(db *bun.DB) schema.QueryAppender {
+			return db.NewSelect().
+				Model(new(Model)).
+				Where("tags && ?", pgdialect.Array([]string{"foo", "bar"})).
+				Where("? = ANY(tags)", "foo")
+		},
+		func(db *bun.DB) schema.QueryAppender {
+			type Model struct {
+				ID    int64
+				Attrs map[string]string `bun:",hstore"`
+			}
+			return db.NewInsert().Model(&Model{
+				ID:    1,
+				Attrs: map[string]string{"color": "red", "size": "M"},
+			})
+		},
+		func(db *bun.DB) schema.QueryAppender {
+			return db.NewSelect().
+				Model(new(Model)).
+				Where("attrs @> ?", pgdialect.HStore(map[string]string{"color": "red"}))
+		},
+		func(db *bun.DB) schema.QueryAppender {
+			type Model struct {
+				ID    int64
+				Items []string          `bun:",json"`
+				Attrs map[string]string `bun:",msgpack"`
+			}
+			return db.NewCreateTable().Model(new(Model))
+		},
+		func(db *bun.DB) schema.QueryAppender {
+			return db.NewRaw("SELECT * FROM ? WHERE id = ? AND name IN (?)",
+				bun.Ident("users"), 1, bun.In([]string{"a", "b"}))
+		},
+		func(db *bun.DB) schema.QueryAppender {
+			return db.NewCreateTable().
+				Model(new(Model)).
+				Engine("MergeTree()").
+				PartitionBy("str").
+				OrderBy("(id)")
+		},
+		func(db *bun.DB) schema.QueryAppender {
+			type Employee struct {
+				ID        int64
+				ManagerID int64
+				Manager   *Employee `bun:"rel:belongs-to,join:manager_id=id,alias:m"`
+			}
+			return db.NewSelect().
+				Model(new(Employee)).
+				ModelTableAlias("e").
+				Column("id").
+				Relation("Manager").
+				Where("?TableAlias.manager_id <> m.manager_id").
+				WherePK()
+		},
+		func(db *bun.DB) schema.QueryAppender {
+			return db.NewUpdate().
+				Model(new(Model)).
+				Set("str = ?TableAlias.str").
+				Where("(?TablePKs) IN (SELECT ?PKs FROM ?TableName)").
+				Returning("?Columns")
+		},
+		func(db *bun.DB) schema.QueryAppender {
+			return db.NewDelete().
+				Model(new(Model)).
+				Where("?PKs > 0")
+		},
+		func(db *bun.DB) schema.QueryAppender {
+			return db.NewDelete().
+				Model(new(Model)).
+				Where("str = ?", "hello").
+				Order("id ASC").
+				Limit(10)
+		},
+		func(db *bun.DB) schema.QueryAppender {
+			return db.NewDelete().
+				Model(new(Model)).
+				Join("JOIN authors AS a").
+				JoinOn("a.id = model.id").
+				Where("a.name = ?", "hello")
+		},
+		func(db *bun.DB) schema.QueryAppender {
+			return db.NewUpdate().
+				Model(new(Model)).
+				Set("str = a.name").
+				Join("JOIN authors AS a").
+				JoinOn("a.id = model.id").
+				Where("model.id = ?", 1)
+		},
+		func(db *bun.DB) schema.QueryAppender {
+			return db.NewUpdate().
+				Model(new(Model)).
+				Set("str = a.name").
+				From("books").
+				Join("JOIN authors AS a").
+				JoinOn("a.id = books.author_id").
+				Where("books.id = model.id")
+		},
+		func(db *bun.DB) schema.QueryAppender {
+			return db.NewSelect().
+				Table("books").
+				Column("author_id").
+				ColumnExpr("count(*)").
+				GroupByAll()
+		},
+		func(db *bun.DB) schema.QueryAppender {
+			return db.NewModifyColumn().Model(new(Model)).Column("id")
+		},
+		func(db *bun.DB) schema.QueryAppender {
+			return db.NewSelect().
+				Model(new(Model)).
+				Join("JOIN models AS m2 ON m2.id > model.id").
+				WhereColumn("model.str", "is not distinct from", "m2.str")
+		},
+		func(db *bun.DB) schema.QueryAppender {
+			return db.NewCreateTable().Model(new(Story)).Engine("InnoDB")
+		},
+		func(db *bun.DB) schema.QueryAppender {
+			return db.NewMerge().
+				Model(new(Model)).
+				Using("src").
+				On("model.id = src.id").
+				WhenMatched("DELETE").
+				Returning("model.id")
+		},
+		func(db *bun.DB) schema.QueryAppender {
+			return db.NewSelect().
+				Model(new(Model)).
+				Join("JOIN models AS m2 ON m2.id > model.id").
+				WhereColumn("model.str", "ILIKE", "m2.str")
+		},
+		func(db *bun.DB) schema.QueryAppender {
+			return db.NewDelete().
+				Model(new(Model)).
+				Join("LEFT JOIN authors AS a ON a.id = model.id").
+				Where("a.id IS NULL")
+		},
+		func(db *bun.DB) schema.QueryAppender {
+			return db.NewDelete().
+				Model(new(Model)).
+				Using("books").
+				Join("JOIN authors AS a ON a.id = books.author_id").
+				Where("books.id = model.id")
+		},
+		func(db *bun.DB) schema.QueryAppender {
+			type Model struct {
+				ID    int64 `bun:",pk,autoincrement"`
+				Title string
+				Num   int
+			}
+			models := []Model{{42, "hello", 1}, {43, "world", 2}}
+			return db.NewUpdate().Model(&models).Bulk().Column("num")
+		},
 	}
 
 	timeRE := regexp.MustCompile(`'\d{4}-\d{2}-\d{2} \d{2}:\d{2}:\d{2}\.\d+(\+\d{2}:\d{2})?'`)
@@ -486,3 +976,163 @@ func TestQuery(t *testing.T) {
 		}
 	})
 }
+
+func TestQueryLockWait(t *testing.T) {
+	db := bun.NewDB(nil, mariaDialect{mysqldialect.New()})
+
+	q := db.NewSelect().TableExpr("t").ForUpdate().Wait(5)
+	b, err := q.AppendQuery(db.Formatter(), nil)
+	require.NoError(t, err)
+	require.Equal(t, "SELECT * FROM t FOR UPDATE WAIT 5", string(b))
+
+	q = db.NewSelect().TableExpr("t").Wait(5)
+	_, err = q.AppendQuery(db.Formatter(), nil)
+	require.EqualError(t, err, "bun: Wait requires For")
+}
+
+// mariaDialect emulates the MySQL dialect connected to MariaDB.
+type mariaDialect struct {
+	*mysqldialect.Dialect
+}
+
+func (d mariaDialect) Init(*sql.DB) {}
+
+func (d mariaDialect) Features() feature.Feature {
+	return d.Dialect.Features() | feature.LockWait
+}
+
+func TestQueryOffsetFetch(t *testing.T) {
+	queryString := func(t *testing.T, q *bun.SelectQuery) string {
+		b, err := q.AppendQuery(q.DB().Formatter(), nil)
+		require.NoError(t, err)
+		return string(b)
+	}
+
+	db := bun.NewDB(nil, pgdialect.New())
+
+	q := db.NewSelect().ColumnExpr("1").OrderExpr("1").Limit(10)
+	require.Equal(t, "SELECT 1 ORDER BY 1 FETCH NEXT 10 ROWS ONLY", queryString(t, q))
+
+	q = db.NewSelect().ColumnExpr("1").OrderExpr("1").Limit(10).Offset(20)
+	require.Equal(t, "SELECT 1 ORDER BY 1 OFFSET 20 ROWS FETCH NEXT 10 ROWS ONLY", queryString(t, q))
+
+	mssql := bun.NewDB(nil, mssqlDialect{pgdialect.New()})
+
+	q = mssql.NewSelect().ColumnExpr("1").Limit(10)
+	require.Equal(t, "SELECT TOP 10 1", queryString(t, q))
+
+	q = mssql.NewSelect().ColumnExpr("1").OrderExpr("1").Limit(10).Offset(20)
+	require.Equal(t, "SELECT 1 ORDER BY 1 OFFSET 20 ROWS FETCH NEXT 10 ROWS ONLY", queryString(t, q))
+
+	q = mssql.NewSelect().ColumnExpr("1").OrderExpr("1").Offset(20)
+	require.Equal(t, "SELECT 1 ORDER BY 1 OFFSET 20 ROWS", queryString(t, q))
+}
+
+// mssqlDialect emulates the MSSQL pagination with TOP.
+type mssqlDialect struct {
+	*pgdialect.Dialect
+}
+
+func (d mssqlDialect) Init(*sql.DB) {}
+
+func (d mssqlDialect) Features() feature.Feature {
+	return d.Dialect.Features() | feature.SelectTop
+}
+
+func TestQueryString(t *testing.T) {
+	type Model struct {
+		ID  int64
+		Str string
+	}
+
+	db := bun.NewDB(nil, pgdialect.New())
+
+	queries := []struct {
+		query     bun.Query
+		operation string
+		template  string
+	}{
+		{
+			db.NewSelect().Model((*Model)(nil)).Where("id = ?", 1),
+			"SELECT",
+			`SELECT "model"."id", "model"."str" FROM "models" AS "model" WHERE (id = ?)`,
+		},
+		{
+			db.NewInsert().Model(&Model{ID: 1, Str: "hello"}),
+			"INSERT",
+			`INSERT INTO "models" ("id", "str") VALUES (?, ?)`,
+		},
+		{
+			db.NewUpdate().Model((*Model)(nil)).Set("str = ?", "hello").Where("id = ?", 1),
+			"UPDATE",
+			`UPDATE "models" AS "model" SET str = ? WHERE (id = ?)`,
+		},
+		{
+			db.NewDelete().Model((*Model)(nil)).Where("id = ?", 1),
+			"DELETE",
+			`DELETE FROM "models" AS "model" WHERE (id = ?)`,
+		},
+		{
+			db.NewMerge().Model((*Model)(nil)).Using("src").On("model.id = src.id").WhenMatched("DELETE"),
+			"MERGE",
+			`MERGE INTO "models" AS "model" USING "src" ON (model.id = src.id) WHEN MATCHED THEN DELETE`,
+		},
+		{
+			db.NewValues(&[]Model{{ID: 1, Str: "hello"}}),
+			"VALUES",
+			`VALUES (?, ?)`,
+		},
+		{
+			db.NewRaw("select * from models where id = ?", 1),
+			"SELECT",
+			`select * from models where id = ?`,
+		},
+		{
+			db.NewCreateTable().Model((*Model)(nil)),
+			"CREATE TABLE",
+			`CREATE TABLE "models" ("id" BIGSERIAL NOT NULL, "str" VARCHAR, PRIMARY KEY ("id"))`,
+		},
+		{
+			db.NewDropTable().Model((*Model)(nil)),
+			"DROP TABLE",
+			`DROP TABLE "models"`,
+		},
+		{
+			db.NewTruncateTable().Model((*Model)(nil)),
+			"TRUNCATE TABLE",
+			`TRUNCATE TABLE "models" RESTART IDENTITY`,
+		},
+		{
+			db.NewCreateIndex().Model((*Model)(nil)).Index("str_idx").Column("str"),
+			"CREATE INDEX",
+			`CREATE INDEX "str_idx" ON "models" ("str")`,
+		},
+		{
+			db.NewDropIndex().Index("str_idx"),
+			"DROP INDEX",
+			`DROP INDEX str_idx`,
+		},
+		{
+			db.NewAddColumn().Model((*Model)(nil)).ColumnExpr("num INT DEFAULT ?", 0),
+			"ADD COLUMN",
+			`ALTER TABLE "models" ADD num INT DEFAULT ?`,
+		},
+		{
+			db.NewDropColumn().Model((*Model)(nil)).Column("str"),
+			"DROP COLUMN",
+			`ALTER TABLE "models" DROP COLUMN "str"`,
+		},
+	}
+
+	for _, test := range queries {
+		require.Equal(t, test.operation, test.query.Operation())
+
+		b, err := test.query.AppendQuery(db.Formatter(), nil)
+		require.NoError(t, err)
+		require.Equal(t, string(b), test.query.String())
+
+		b, err = test.query.AppendQuery(schema.NewNopFormatter(), nil)
+		require.NoError(t, err)
+		require.Equal(t, test.template, string(b))
+	}
+}
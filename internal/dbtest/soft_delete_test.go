@@ -63,6 +63,7 @@ func testSoftDelete(t *testing.T, db *bun.DB) {
 	// Soft delete.
 	_, err = db.NewDelete().Model(video1).Where("id = ?", video1.ID).Exec(ctx)
 	require.NoError(t, err)
+	require.False(t, video1.DeletedAt.IsZero())
 
 	// Count visible videos.
 	count, err = db.NewSelect().Model((*Video)(nil)).Count(ctx)
@@ -0,0 +1,61 @@
+package internal
+
+import (
+	"errors"
+	"strings"
+)
+
+// JoinErrors returns an error that wraps the non-nil errors like errors.Join.
+// It returns nil if there are no errors and the error itself if there is only one,
+// so the error can still be compared with ==, for example, to sql.ErrNoRows.
+func JoinErrors(errs ...error) error {
+	var nonNil []error
+	for _, err := range errs {
+		if err != nil {
+			nonNil = append(nonNil, err)
+		}
+	}
+
+	switch len(nonNil) {
+	case 0:
+		return nil
+	case 1:
+		return nonNil[0]
+	default:
+		return joinError(nonNil)
+	}
+}
+
+type joinError []error
+
+func (e joinError) Error() string {
+	ss := make([]string, len(e))
+	for i, err := range e {
+		ss[i] = err.Error()
+	}
+	return strings.Join(ss, "\n")
+}
+
+// Is and As let errors.Is and errors.As match any of the joined errors,
+// because Go versions before 1.20 don't support Unwrap() []error.
+func (e joinError) Is(target error) bool {
+	for _, err := range e {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+func (e joinError) As(target interface{}) bool {
+	for _, err := range e {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+func (e joinError) Unwrap() []error {
+	return e
+}
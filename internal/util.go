@@ -1,7 +1,10 @@
 package internal
 
 import (
+	"context"
+	"math/rand"
 	"reflect"
+	"time"
 )
 
 func MakeSliceNextElemFunc(v reflect.Value) func() reflect.Value {
@@ -55,3 +58,35 @@ func Unwrap(err error) error {
 	}
 	return u.Unwrap()
 }
+
+// RetryBackoff returns an exponential backoff with jitter for the retry attempt.
+func RetryBackoff(retry int) time.Duration {
+	const (
+		minBackoff = 10 * time.Millisecond
+		maxBackoff = time.Second
+	)
+
+	if retry > 10 {
+		retry = 10
+	}
+
+	d := minBackoff << uint(retry)
+	if d > maxBackoff {
+		d = maxBackoff
+	}
+
+	// Add jitter so concurrent transactions don't retry at the same time.
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+func Sleep(ctx context.Context, dur time.Duration) error {
+	t := time.NewTimer(dur)
+	defer t.Stop()
+
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -16,6 +16,15 @@ type join struct {
 
 	ApplyQueryFunc func(*SelectQuery) *SelectQuery
 	columns        []schema.QueryWithArgs
+
+	// joinOnly is set when the relation is joined without selecting its columns.
+	joinOnly bool
+	// alias overrides the generated table alias, for example, "a" instead of "author".
+	alias string
+	// joinType overrides LEFT JOIN used to join has-one relations, for example, INNER JOIN.
+	joinType JoinType
+	// on contains additional has-one join conditions added with JoinOn.
+	on []schema.QueryWithSep
 }
 
 func (j *join) applyQuery(q *SelectQuery) {
@@ -26,14 +35,23 @@ func (j *join) applyQuery(q *SelectQuery) {
 	var table *schema.Table
 	var columns []schema.QueryWithArgs
 
+	var relJoin *join
+
 	// Save state.
 	table, q.table = q.table, j.JoinModel.Table()
 	columns, q.columns = q.columns, nil
+	switch j.Relation.Type {
+	case schema.HasOneRelation, schema.BelongsToRelation:
+		relJoin, q.relJoin = q.relJoin, j
+	default:
+		relJoin = q.relJoin
+	}
 
 	q = j.ApplyQueryFunc(q)
 
 	// Restore state.
 	q.table = table
+	q.relJoin = relJoin
 	j.columns, q.columns = q.columns, columns
 }
 
@@ -199,14 +217,14 @@ func (j *join) appendAliasColumn(fmter schema.Formatter, b []byte, column string
 	quote := fmter.IdentQuote()
 
 	b = append(b, quote)
-	b = appendAlias(b, j)
+	b = appendRelationPath(b, j)
 	b = append(b, "__"...)
 	b = append(b, column...)
 	b = append(b, quote)
 	return b
 }
 
-func (j *join) appendBaseAlias(fmter schema.Formatter, b []byte) []byte {
+func (j *join) appendBaseAlias(fmter schema.Formatter, b []byte, q *SelectQuery) []byte {
 	quote := fmter.IdentQuote()
 
 	if j.hasParent() {
@@ -215,21 +233,27 @@ func (j *join) appendBaseAlias(fmter schema.Formatter, b []byte) []byte {
 		b = append(b, quote)
 		return b
 	}
+	if q.table == j.BaseModel.Table() {
+		return append(b, q.tableAlias()...)
+	}
 	return append(b, j.BaseModel.Table().SQLAlias...)
 }
 
-func (j *join) appendSoftDelete(b []byte, flags internal.Flag) []byte {
+func (j *join) appendSoftDelete(fmter schema.Formatter, b []byte, flags internal.Flag) []byte {
 	b = append(b, '.')
 	b = append(b, j.JoinModel.Table().SoftDeleteField.SQLName...)
 	if flags.Has(deletedFlag) {
-		b = append(b, " IS NOT NULL"...)
+		b = fmter.AppendKeywords(b, " IS NOT NULL")
 	} else {
-		b = append(b, " IS NULL"...)
+		b = fmter.AppendKeywords(b, " IS NULL")
 	}
 	return b
 }
 
 func appendAlias(b []byte, j *join) []byte {
+	if j.alias != "" {
+		return append(b, j.alias...)
+	}
 	if j.hasParent() {
 		b = appendAlias(b, j.Parent)
 		b = append(b, "__"...)
@@ -238,37 +262,84 @@ func appendAlias(b []byte, j *join) []byte {
 	return b
 }
 
+// appendRelationPath appends the relation path, for example, "author__avatar",
+// that is used to alias the relation columns so they can be scanned into the model.
+func appendRelationPath(b []byte, j *join) []byte {
+	if j.hasParent() {
+		b = appendRelationPath(b, j.Parent)
+		b = append(b, "__"...)
+	}
+	b = append(b, j.Relation.Field.Name...)
+	return b
+}
+
 func (j *join) appendHasOneJoin(
 	fmter schema.Formatter, b []byte, q *SelectQuery,
 ) (_ []byte, err error) {
 	isSoftDelete := j.JoinModel.Table().SoftDeleteField != nil && !q.flags.Has(allWithDeletedFlag)
 
-	b = append(b, "LEFT JOIN "...)
+	joinType := LeftJoin
+	if j.joinType != "" {
+		joinType = j.joinType
+	}
+	b = fmter.AppendKeywords(b, string(joinType))
+	b = append(b, ' ')
 	b = fmter.AppendQuery(b, string(j.JoinModel.Table().SQLNameForSelects))
-	b = append(b, " AS "...)
+	b = fmter.AppendKeywords(b, " AS ")
 	b = j.appendAlias(fmter, b)
 
-	b = append(b, " ON "...)
+	b = fmter.AppendKeywords(b, " ON ")
 
-	b = append(b, '(')
-	for i, baseField := range j.Relation.BaseFields {
-		if i > 0 {
-			b = append(b, " AND "...)
+	on := j.on
+	if len(j.Relation.BaseFields) > 0 {
+		b = append(b, '(')
+		for i, baseField := range j.Relation.BaseFields {
+			if i > 0 {
+				b = fmter.AppendKeywords(b, " AND ")
+			}
+			b = j.appendAlias(fmter, b)
+			b = append(b, '.')
+			b = append(b, j.Relation.JoinFields[i].SQLName...)
+			b = append(b, " = "...)
+			b = j.appendBaseAlias(fmter, b, q)
+			b = append(b, '.')
+			b = append(b, baseField.SQLName...)
+		}
+		b = append(b, ')')
+	} else if len(on) > 0 {
+		// Ad hoc relations are joined only using the JoinOn conditions.
+		b = append(b, '(')
+		b, err = on[0].AppendQuery(fmter, b)
+		if err != nil {
+			return nil, err
 		}
+		b = append(b, ')')
+		on = on[1:]
+	}
+
+	if j.Relation.PolymorphicField != nil {
+		b = fmter.AppendKeywords(b, " AND ")
 		b = j.appendAlias(fmter, b)
 		b = append(b, '.')
-		b = append(b, j.Relation.JoinFields[i].SQLName...)
+		b = append(b, j.Relation.PolymorphicField.SQLName...)
 		b = append(b, " = "...)
-		b = j.appendBaseAlias(fmter, b)
-		b = append(b, '.')
-		b = append(b, baseField.SQLName...)
+		b = fmter.AppendQuery(b, "?", j.Relation.PolymorphicValue)
 	}
-	b = append(b, ')')
 
 	if isSoftDelete {
-		b = append(b, " AND "...)
+		b = fmter.AppendKeywords(b, " AND ")
 		b = j.appendAlias(fmter, b)
-		b = j.appendSoftDelete(b, q.flags)
+		b = j.appendSoftDelete(fmter, b, q.flags)
+	}
+
+	for _, on := range on {
+		b = append(b, on.Sep...)
+		b = append(b, '(')
+		b, err = on.AppendQuery(fmter, b)
+		if err != nil {
+			return nil, err
+		}
+		b = append(b, ')')
 	}
 
 	return b, nil
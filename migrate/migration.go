@@ -43,6 +43,7 @@ func NewSQLMigrationFunc(fsys fs.FS, name string) MigrationFunc {
 		if err != nil {
 			return err
 		}
+		defer f.Close()
 
 		scanner := bufio.NewScanner(f)
 		var queries []string
@@ -73,36 +74,33 @@ func NewSQLMigrationFunc(fsys fs.FS, name string) MigrationFunc {
 			return err
 		}
 
-		var idb bun.IConn
-
 		if isTx {
 			tx, err := db.BeginTx(ctx, nil)
 			if err != nil {
 				return err
 			}
-			idb = tx
-		} else {
-			conn, err := db.Conn(ctx)
-			if err != nil {
-				return err
+
+			for _, q := range queries {
+				if _, err := tx.ExecContext(ctx, q); err != nil {
+					_ = tx.Rollback()
+					return err
+				}
 			}
-			idb = conn
+			return tx.Commit()
+		}
+
+		conn, err := db.Conn(ctx)
+		if err != nil {
+			return err
 		}
+		defer conn.Close()
 
 		for _, q := range queries {
-			_, err = idb.ExecContext(ctx, q)
-			if err != nil {
+			if _, err := conn.ExecContext(ctx, q); err != nil {
 				return err
 			}
 		}
-
-		if tx, ok := idb.(bun.Tx); ok {
-			return tx.Commit()
-		} else if conn, ok := idb.(bun.Conn); ok {
-			return conn.Close()
-		}
-
-		panic("not reached")
+		return nil
 	}
 }
 
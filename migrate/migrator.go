@@ -70,6 +70,9 @@ func (m *Migrator) MigrationsWithStatus(ctx context.Context) (MigrationSlice, er
 	for i := range sorted {
 		name := sorted[i].Name
 		if m2, ok := appliedMap[name]; ok {
+			// Keep the funcs since applied migrations are selected from the table.
+			m2.Up = sorted[i].Up
+			m2.Down = sorted[i].Down
 			sorted[i] = *m2
 		}
 	}
@@ -39,6 +39,7 @@ type tableModel interface {
 	Relation() *schema.Relation
 
 	Join(string, func(*SelectQuery) *SelectQuery) *join
+	joinAdHoc(string) *join
 	GetJoin(string) *join
 	GetJoins() []join
 	AddJoin(join) *join
@@ -64,7 +65,7 @@ func newModel(db *DB, dest []interface{}) (model, error) {
 		}
 
 		v = v.Elem()
-		if v.Kind() != reflect.Slice {
+		if v.Kind() != reflect.Slice || db.isScalarType(v.Type()) {
 			return newScanModel(db, dest), nil
 		}
 
@@ -112,19 +113,26 @@ func _newModel(db *DB, dest interface{}, scan bool) (model, error) {
 	switch v.Kind() {
 	case reflect.Map:
 		typ := v.Type()
+		if typ == mapColumnsType {
+			mapPtr := v.Addr().Interface().(*map[string][]interface{})
+			return newMapColumnsModel(db, mapPtr), nil
+		}
 		if err := validMap(typ); err != nil {
 			return nil, err
 		}
 		mapPtr := v.Addr().Interface().(*map[string]interface{})
 		return newMapModel(db, mapPtr), nil
 	case reflect.Struct:
-		if v.Type() != timeType {
+		if v.Type() != timeType && !db.isCustomType(v.Type()) {
 			return newStructTableModelValue(db, dest, v), nil
 		}
 	case reflect.Slice:
+		if db.isScalarType(v.Type()) {
+			break
+		}
 		switch elemType := sliceElemType(v); elemType.Kind() {
 		case reflect.Struct:
-			if elemType != timeType {
+			if elemType != timeType && !db.isCustomType(elemType) {
 				return newSliceTableModel(db, dest, v, elemType), nil
 			}
 		case reflect.Map:
@@ -129,7 +129,7 @@ func (m *mapModel) appendColumnsValues(fmter schema.Formatter, b []byte) []byte
 		b = fmter.AppendIdent(b, k)
 	}
 
-	b = append(b, ") VALUES ("...)
+	b = fmter.AppendKeywords(b, ") VALUES (")
 
 	isTemplate := fmter.IsNop()
 	for i, k := range keys {
@@ -137,7 +137,7 @@ func (m *mapModel) appendColumnsValues(fmter schema.Formatter, b []byte) []byte
 			b = append(b, ", "...)
 		}
 		if isTemplate {
-			b = append(b, '?')
+			b = fmter.AppendTemplateArg(b, m.m[k])
 		} else {
 			b = fmter.Dialect().Append(fmter, b, m.m[k])
 		}
@@ -165,7 +165,7 @@ func (m *mapModel) appendSet(fmter schema.Formatter, b []byte) []byte {
 		b = fmter.AppendIdent(b, k)
 		b = append(b, " = "...)
 		if isTemplate {
-			b = append(b, '?')
+			b = fmter.AppendTemplateArg(b, m.m[k])
 		} else {
 			b = fmter.Dialect().Append(fmter, b, m.m[k])
 		}
@@ -0,0 +1,68 @@
+package bun
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+)
+
+var mapColumnsType = reflect.TypeOf((*map[string][]interface{})(nil)).Elem()
+
+// mapColumnsModel scans rows into a map that holds a slice of values per column.
+type mapColumnsModel struct {
+	mapModel
+	dest *map[string][]interface{}
+}
+
+var _ model = (*mapColumnsModel)(nil)
+
+func newMapColumnsModel(db *DB, dest *map[string][]interface{}) *mapColumnsModel {
+	return &mapColumnsModel{
+		mapModel: mapModel{
+			db: db,
+		},
+		dest: dest,
+	}
+}
+
+func (m *mapColumnsModel) Value() interface{} {
+	return m.dest
+}
+
+func (m *mapColumnsModel) ScanRows(ctx context.Context, rows *sql.Rows) (int, error) {
+	columns, err := rows.Columns()
+	if err != nil {
+		return 0, err
+	}
+
+	m.rows = rows
+	m.columns = columns
+	dest := makeDest(m, len(columns))
+
+	values := make(map[string][]interface{}, len(columns))
+	for _, col := range columns {
+		values[col] = make([]interface{}, 0)
+	}
+
+	var n int
+
+	for rows.Next() {
+		m.m = make(map[string]interface{}, len(columns))
+
+		m.scanIndex = 0
+		if err := rows.Scan(dest...); err != nil {
+			return 0, err
+		}
+
+		for _, col := range columns {
+			values[col] = append(values[col], m.m[col])
+		}
+		n++
+	}
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	*m.dest = values
+	return n, nil
+}
@@ -98,19 +98,23 @@ func (m *mapSliceModel) appendValues(fmter schema.Formatter, b []byte) (_ []byte
 	}
 	slice := *m.dest
 
-	b = append(b, "VALUES "...)
+	b = fmter.AppendKeywords(b, "VALUES ")
 	if m.db.features.Has(feature.ValuesRow) {
-		b = append(b, "ROW("...)
+		b = fmter.AppendKeywords(b, "ROW(")
 	} else {
 		b = append(b, '(')
 	}
 
 	if fmter.IsNop() {
-		for i := range m.keys {
+		var first map[string]interface{}
+		if len(slice) > 0 {
+			first = slice[0]
+		}
+		for i, k := range m.keys {
 			if i > 0 {
 				b = append(b, ", "...)
 			}
-			b = append(b, '?')
+			b = fmter.AppendTemplateArg(b, first[k])
 		}
 		return b, nil
 	}
@@ -119,7 +123,7 @@ func (m *mapSliceModel) appendValues(fmter schema.Formatter, b []byte) (_ []byte
 		if i > 0 {
 			b = append(b, "), "...)
 			if m.db.features.Has(feature.ValuesRow) {
-				b = append(b, "ROW("...)
+				b = fmter.AppendKeywords(b, "ROW(")
 			} else {
 				b = append(b, '(')
 			}
@@ -49,6 +49,9 @@ func (m *scanModel) Scan(src interface{}) error {
 	dest := reflect.ValueOf(m.dest[m.scanIndex])
 	m.scanIndex++
 
-	scanner := m.db.dialect.Scanner(dest.Type())
+	scanner := m.db.dialect.Tables().TypeScanner(dest.Type())
+	if scanner == nil {
+		scanner = m.db.dialect.Scanner(dest.Type())
+	}
 	return scanner(dest, src)
 }
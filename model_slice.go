@@ -3,6 +3,7 @@ package bun
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"reflect"
 
 	"github.com/uptrace/bun/internal"
@@ -15,6 +16,7 @@ type sliceInfo struct {
 }
 
 type sliceModel struct {
+	db        *DB
 	dest      []interface{}
 	values    []reflect.Value
 	scanIndex int
@@ -25,6 +27,7 @@ var _ model = (*sliceModel)(nil)
 
 func newSliceModel(db *DB, dest []interface{}, values []reflect.Value) *sliceModel {
 	return &sliceModel{
+		db:     db,
 		dest:   dest,
 		values: values,
 	}
@@ -46,15 +49,23 @@ func (m *sliceModel) ScanRows(ctx context.Context, rows *sql.Rows) (int, error)
 			v.Set(v.Slice(0, 0))
 		}
 
+		scan := m.db.dialect.Tables().TypeScanner(v.Type().Elem())
+		if scan == nil {
+			scan = schema.Scanner(v.Type().Elem())
+		}
+
 		m.info[i] = sliceInfo{
 			nextElem: internal.MakeSliceNextElemFunc(v),
-			scan:     schema.Scanner(v.Type().Elem()),
+			scan:     scan,
 		}
 	}
 
 	if len(columns) == 0 {
 		return 0, nil
 	}
+	if len(columns) != len(m.values) {
+		return 0, fmt.Errorf("bun: got %d columns, but %d slice destinations", len(columns), len(m.values))
+	}
 	dest := makeDest(m, len(columns))
 
 	var n int
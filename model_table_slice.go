@@ -49,6 +49,10 @@ func (m *sliceTableModel) Join(name string, apply func(*SelectQuery) *SelectQuer
 	return m.join(m.slice, name, apply)
 }
 
+func (m *sliceTableModel) joinAdHoc(name string) *join {
+	return m._joinAdHoc(m.slice, name)
+}
+
 func (m *sliceTableModel) Bind(bind reflect.Value) {
 	m.slice = bind.Field(m.index[len(m.index)-1])
 }
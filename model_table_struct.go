@@ -5,7 +5,9 @@ import (
 	"database/sql"
 	"fmt"
 	"reflect"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/uptrace/bun/schema"
 )
@@ -27,6 +29,14 @@ type structTableModel struct {
 
 	columns   []string
 	scanIndex int
+
+	// strict makes the model collect unknown columns instead of returning an error.
+	strict         bool
+	unknownColumns []string
+
+	// totalColumn is scanned into totalDest instead of the struct, see WithTotalCount.
+	totalColumn string
+	totalDest   *int
 }
 
 var _ tableModel = (*structTableModel)(nil)
@@ -116,6 +126,36 @@ func (m *structTableModel) mountJoins() {
 	}
 }
 
+// resetMissingJoins sets has-one relation pointers to nil when all the relation columns
+// are NULL, i.e. LEFT JOIN did not find a matching row.
+func (m *structTableModel) resetMissingJoins() {
+	for i := range m.joins {
+		j := &m.joins[i]
+		switch j.Relation.Type {
+		case schema.HasOneRelation, schema.BelongsToRelation:
+		default:
+			continue
+		}
+		if j.joinOnly {
+			continue
+		}
+
+		joinModel, ok := j.JoinModel.(*structTableModel)
+		if !ok {
+			continue
+		}
+
+		if joinModel.structInited {
+			joinModel.resetMissingJoins()
+			continue
+		}
+
+		if strct := joinModel.strct; strct.Kind() == reflect.Ptr && !strct.IsNil() && strct.CanSet() {
+			strct.Set(reflect.Zero(strct.Type()))
+		}
+	}
+}
+
 var _ schema.BeforeScanHook = (*structTableModel)(nil)
 
 func (m *structTableModel) BeforeScan(ctx context.Context) error {
@@ -173,6 +213,34 @@ func (m *structTableModel) Join(name string, apply func(*SelectQuery) *SelectQue
 	return m.join(m.strct, name, apply)
 }
 
+func (m *structTableModel) joinAdHoc(name string) *join {
+	return m._joinAdHoc(m.strct, name)
+}
+
+// _joinAdHoc joins the struct field with the name that is not a relation
+// using schema.Table.AdHocRelation.
+func (m *structTableModel) _joinAdHoc(bind reflect.Value, name string) *join {
+	if j := m.GetJoin(name); j != nil {
+		return j
+	}
+
+	rel := m.table.AdHocRelation(name)
+	if rel == nil {
+		return nil
+	}
+
+	model, err := newTableModelIndex(m.db, m.table, bind, rel.Field.Index, rel)
+	if err != nil {
+		return nil
+	}
+
+	return m.AddJoin(join{
+		BaseModel: m,
+		JoinModel: model,
+		Relation:  rel,
+	})
+}
+
 func (m *structTableModel) join(
 	bind reflect.Value, name string, apply func(*SelectQuery) *SelectQuery,
 ) *join {
@@ -208,6 +276,7 @@ func (m *structTableModel) join(
 			currJoin.Parent = lastJoin
 			currJoin.BaseModel = currJoin.JoinModel
 			currJoin.JoinModel = model
+			currJoin.alias = relation.Field.Tag.Options["alias"]
 
 			lastJoin = currJoin.BaseModel.AddJoin(currJoin)
 		}
@@ -263,6 +332,10 @@ func (m *structTableModel) scanRow(ctx context.Context, rows *sql.Rows, dest []i
 		return err
 	}
 
+	if m.structInited {
+		m.resetMissingJoins()
+	}
+
 	if err := m.AfterScan(ctx); err != nil {
 		return err
 	}
@@ -278,15 +351,86 @@ func (m *structTableModel) Scan(src interface{}) error {
 }
 
 func (m *structTableModel) ScanColumn(column string, src interface{}) error {
+	if m.totalColumn != "" && column == m.totalColumn {
+		return scanTotalCount(m.totalDest, src)
+	}
 	if ok, err := m.scanColumn(column, src); ok {
 		return err
 	}
-	if column == "" || column[0] == '_' || m.db.flags.Has(discardUnknownColumns) {
+	if column == "" || column[0] == '_' {
+		return nil
+	}
+	if m.strict {
+		m.addUnknownColumn(column)
+		return nil
+	}
+	if m.db.flags.Has(discardUnknownColumns) {
 		return nil
 	}
 	return fmt.Errorf("bun: %s does not have column %q", m.table.TypeName, column)
 }
 
+func (m *structTableModel) setStrict(on bool) {
+	m.strict = on
+	m.unknownColumns = nil
+}
+
+func (m *structTableModel) setTotalCount(column string, dest *int) {
+	m.totalColumn = column
+	m.totalDest = dest
+}
+
+func scanTotalCount(dest *int, src interface{}) error {
+	v := reflect.ValueOf(dest).Elem()
+	return schema.Scanner(v.Type())(v, src)
+}
+
+func (m *structTableModel) addUnknownColumn(column string) {
+	for _, c := range m.unknownColumns {
+		if c == column {
+			return
+		}
+	}
+	m.unknownColumns = append(m.unknownColumns, column)
+}
+
+// unknownColumnsErr returns an error listing the columns that did not match any field.
+func (m *structTableModel) unknownColumnsErr() error {
+	if len(m.unknownColumns) == 0 {
+		return nil
+	}
+
+	quoted := make([]string, len(m.unknownColumns))
+	for i, column := range m.unknownColumns {
+		quoted[i] = strconv.Quote(column)
+	}
+	return fmt.Errorf("bun: %s does not have columns %s",
+		m.table.TypeName, strings.Join(quoted, ", "))
+}
+
+// convertLocation converts the scanned time value to the location
+// configured with the field tag or the DB option.
+func (m *structTableModel) convertLocation(field *schema.Field) {
+	loc := field.ScanLocation
+	if loc == nil {
+		loc = m.db.scanLocation
+	}
+	if loc == nil || field.IndirectType != timeType {
+		return
+	}
+
+	fv := field.Value(m.strct)
+	if fv.Kind() == reflect.Ptr {
+		if fv.IsNil() {
+			return
+		}
+		fv = fv.Elem()
+	}
+
+	tm := fv.Addr().Interface().(*time.Time)
+	*tm = tm.In(loc)
+}
+
 func (m *structTableModel) scanColumn(column string, src interface{}) (bool, error) {
 	if src != nil {
 		if err := m.initStruct(); err != nil {
@@ -294,16 +438,31 @@ func (m *structTableModel) scanColumn(column string, src interface{}) (bool, err
 		}
 	}
 
+	joinName, joinColumn := splitColumn(column)
+	if joinName != "" && src != nil {
+		// Scan relation columns using the join model so it can track
+		// whether the relation has any non-NULL values.
+		if join := m.GetJoin(joinName); join != nil {
+			return true, join.JoinModel.ScanColumn(joinColumn, src)
+		}
+	}
+
 	if field, ok := m.table.FieldMap[column]; ok {
-		return true, field.ScanValue(m.strct, src)
+		if err := field.ScanValue(m.strct, src); err != nil {
+			return true, err
+		}
+		if src != nil {
+			m.convertLocation(field)
+		}
+		return true, nil
 	}
 
-	if joinName, column := splitColumn(column); joinName != "" {
+	if joinName != "" {
 		if join := m.GetJoin(joinName); join != nil {
-			return true, join.JoinModel.ScanColumn(column, src)
+			return true, join.JoinModel.ScanColumn(joinColumn, src)
 		}
 		if m.table.ModelName == joinName {
-			return true, m.ScanColumn(column, src)
+			return true, m.ScanColumn(joinColumn, src)
 		}
 	}
 
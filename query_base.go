@@ -5,6 +5,11 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/uptrace/bun/dialect/feature"
 	"github.com/uptrace/bun/internal"
@@ -16,8 +21,19 @@ const (
 	forceDeleteFlag
 	deletedFlag
 	allWithDeletedFlag
+	appendQueryHookFlag
+	scanOneFlag
 )
 
+// Query is implemented by all query builders.
+type Query interface {
+	schema.QueryAppender
+	// Operation returns the query operation, for example, "SELECT" or "CREATE TABLE".
+	Operation() string
+	// String returns the query formatted with the DB formatter.
+	String() string
+}
+
 type withQuery struct {
 	name  string
 	query schema.QueryAppender
@@ -39,15 +55,26 @@ var (
 	_ IConn = (*Tx)(nil)
 )
 
-// IDB is a common interface for *bun.DB, bun.Conn, and bun.Tx.
+// IDB is a common interface for *bun.DB, bun.Conn, and bun.Tx so repositories
+// can accept either of them:
+//
+//	func (r *Repo) CreateUser(ctx context.Context, db bun.IDB, user *User) error {
+//		_, err := db.NewInsert().Model(user).Exec(ctx)
+//		return err
+//	}
 type IDB interface {
 	IConn
+	Dialect() schema.Dialect
+
+	RunInTx(ctx context.Context, opts *sql.TxOptions, fn func(ctx context.Context, tx Tx) error) error
 
 	NewValues(model interface{}) *ValuesQuery
+	NewRaw(query string, args ...interface{}) *RawQuery
 	NewSelect() *SelectQuery
 	NewInsert() *InsertQuery
 	NewUpdate() *UpdateQuery
 	NewDelete() *DeleteQuery
+	NewMerge() *MergeQuery
 	NewCreateTable() *CreateTableQuery
 	NewDropTable() *DropTableQuery
 	NewCreateIndex() *CreateIndexQuery
@@ -55,12 +82,16 @@ type IDB interface {
 	NewTruncateTable() *TruncateTableQuery
 	NewAddColumn() *AddColumnQuery
 	NewDropColumn() *DropColumnQuery
+	NewRenameColumn() *RenameColumnQuery
+	NewModifyColumn() *ModifyColumnQuery
 }
 
 var (
-	_ IConn = (*DB)(nil)
-	_ IConn = (*Conn)(nil)
-	_ IConn = (*Tx)(nil)
+	_ IDB = (*DB)(nil)
+	_ IDB = (*Conn)(nil)
+	_ IDB = (*Tx)(nil)
+	_ IDB = Conn{}
+	_ IDB = Tx{}
 )
 
 type baseQuery struct {
@@ -73,10 +104,23 @@ type baseQuery struct {
 	tableModel tableModel
 	table      *schema.Table
 
-	with       []withQuery
-	modelTable schema.QueryWithArgs
-	tables     []schema.QueryWithArgs
-	columns    []schema.QueryWithArgs
+	with        []withQuery
+	modelTable  schema.QueryWithArgs
+	tableSchema string
+	tables      []schema.QueryWithArgs
+	columns     []schema.QueryWithArgs
+	comments    map[string]string
+	timeout     time.Duration
+
+	// modelTableAlias overrides the model table alias for a single query.
+	modelTableAlias schema.Safe
+	// namedArgs are resolved in addition to the DB named args, for example, ?tenant_id.
+	namedArgs []namedArg
+
+	// inTx and txIsolation describe the transaction used by the query.
+	inTx        bool
+	txIsolation sql.IsolationLevel
+	isolation   sql.IsolationLevel
 
 	flags internal.Flag
 }
@@ -91,16 +135,48 @@ func (q *baseQuery) GetModel() Model {
 
 func (q *baseQuery) setConn(db IConn) {
 	// Unwrap Bun wrappers to not call query hooks twice.
+	// The wrapped DB is used to format the query so its named args are respected,
+	// for example, to run the same query against different tenant tables.
+	q.inTx = false
+	q.txIsolation = sql.LevelDefault
+
 	switch db := db.(type) {
 	case *DB:
+		q.db = db
 		q.conn = db.DB
 	case Conn:
+		q.db = db.db
 		q.conn = db.Conn
 	case Tx:
+		q.db = db.db
 		q.conn = db.Tx
+		q.inTx = true
+		q.txIsolation = db.isolation
 	default:
 		q.conn = db
+		_, q.inTx = db.(*sql.Tx)
+	}
+}
+
+// checkIsolation checks that the query runs in a transaction
+// with the isolation level required by RequireIsolation.
+// The default and unknown transaction isolation levels can't be verified,
+// because they depend on the database settings, so they are allowed.
+func (q *baseQuery) checkIsolation() error {
+	if q.isolation == sql.LevelDefault {
+		return nil
 	}
+	if !q.inTx {
+		return fmt.Errorf("bun: query requires a transaction with %s isolation level", q.isolation)
+	}
+	if q.txIsolation == sql.LevelDefault || q.txIsolation > sql.LevelLinearizable {
+		return nil
+	}
+	if q.txIsolation < q.isolation {
+		return fmt.Errorf("bun: query requires %s isolation level, but the transaction uses %s",
+			q.isolation, q.txIsolation)
+	}
+	return nil
 }
 
 // TODO: rename to setModel
@@ -136,6 +212,96 @@ func (q *baseQuery) getModel(dest []interface{}) (model, error) {
 
 //------------------------------------------------------------------------------
 
+// beforeAppendQueryHook calls BeforeAppendQueryHook once per query, so the conditions
+// added by the hook are not duplicated. The hooks of the has-one and belongs-to
+// relations joined in the query are called too, once per table.
+func (q *baseQuery) beforeAppendQueryHook(ctx context.Context, query schema.QueryAppender) error {
+	if q.table == nil || q.flags.Has(appendQueryHookFlag) {
+		return nil
+	}
+	q.flags = q.flags.Set(appendQueryHookFlag)
+
+	seen := map[*schema.Table]struct{}{q.table: {}}
+	if err := callBeforeAppendQueryHook(ctx, q.table, query); err != nil {
+		return err
+	}
+	if q.tableModel == nil {
+		return nil
+	}
+	return joinsBeforeAppendQueryHook(ctx, q.tableModel.GetJoins(), query, seen)
+}
+
+func joinsBeforeAppendQueryHook(
+	ctx context.Context, joins []join, query schema.QueryAppender, seen map[*schema.Table]struct{},
+) error {
+	for i := range joins {
+		j := &joins[i]
+		switch j.Relation.Type {
+		case schema.HasOneRelation, schema.BelongsToRelation:
+		default:
+			// The other relations are selected with separate queries, which call the hook.
+			continue
+		}
+
+		table := j.JoinModel.Table()
+		if _, ok := seen[table]; !ok {
+			seen[table] = struct{}{}
+			if err := callBeforeAppendQueryHook(ctx, table, query); err != nil {
+				return err
+			}
+		}
+		if err := joinsBeforeAppendQueryHook(ctx, j.JoinModel.GetJoins(), query, seen); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func callBeforeAppendQueryHook(
+	ctx context.Context, table *schema.Table, query schema.QueryAppender,
+) error {
+	if hook, ok := table.ZeroIface.(BeforeAppendQueryHook); ok {
+		return hook.BeforeAppendQuery(ctx, query)
+	}
+	return nil
+}
+
+// beforeAppendModelHook calls BeforeAppendModelHook for each struct of the model.
+func (q *baseQuery) beforeAppendModelHook(ctx context.Context, query schema.QueryAppender) error {
+	if q.tableModel == nil {
+		return nil
+	}
+	if _, ok := q.table.ZeroIface.(BeforeAppendModelHook); !ok {
+		return nil
+	}
+
+	v := reflect.Indirect(reflect.ValueOf(q.tableModel.Value()))
+	switch v.Kind() {
+	case reflect.Struct:
+		return callBeforeAppendModelHook(ctx, v, query)
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			elem := v.Index(i)
+			if elem.Kind() == reflect.Ptr {
+				if elem.IsNil() {
+					continue
+				}
+				elem = elem.Elem()
+			}
+			if err := callBeforeAppendModelHook(ctx, elem, query); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func callBeforeAppendModelHook(
+	ctx context.Context, strct reflect.Value, query schema.QueryAppender,
+) error {
+	return strct.Addr().Interface().(BeforeAppendModelHook).BeforeAppendModel(ctx, query)
+}
+
 func (q *baseQuery) checkSoftDelete() error {
 	if q.table == nil {
 		return errors.New("bun: can't use soft deletes without a table")
@@ -190,7 +356,7 @@ func (q *baseQuery) appendWith(fmter schema.Formatter, b []byte) (_ []byte, err
 		return b, nil
 	}
 
-	b = append(b, "WITH "...)
+	b = fmter.AppendKeywords(b, "WITH ")
 	for i, with := range q.with {
 		if i > 0 {
 			b = append(b, ", "...)
@@ -206,7 +372,7 @@ func (q *baseQuery) appendWith(fmter schema.Formatter, b []byte) (_ []byte, err
 			b = append(b, ")"...)
 		}
 
-		b = append(b, " AS ("...)
+		b = fmter.AppendKeywords(b, " AS (")
 
 		b, err = with.query.AppendQuery(fmter, b)
 		if err != nil {
@@ -226,6 +392,10 @@ func (q *baseQuery) addTable(table schema.QueryWithArgs) {
 }
 
 func (q *baseQuery) addColumn(column schema.QueryWithArgs) {
+	if strings.TrimSpace(column.Query) == "" {
+		q.setErr(errors.New("bun: column name or expression is empty"))
+		return
+	}
 	q.columns = append(q.columns, column)
 }
 
@@ -293,10 +463,14 @@ func (q *baseQuery) _appendTables(
 				return nil, err
 			}
 		} else {
-			b = fmter.AppendQuery(b, string(q.table.SQLNameForSelects))
-			if withAlias && q.table.SQLAlias != q.table.SQLNameForSelects {
-				b = append(b, " AS "...)
-				b = append(b, q.table.SQLAlias...)
+			if q.tableSchema != "" && q.table.SQLNameForSelects == q.table.SQLName {
+				b = q.appendTableName(fmter, b)
+			} else {
+				b = fmter.AppendQuery(b, string(q.table.SQLNameForSelects))
+			}
+			if withAlias && (q.tableSchema != "" || q.tableAlias() != q.table.SQLNameForSelects) {
+				b = fmter.AppendKeywords(b, " AS ")
+				b = append(b, q.tableAlias()...)
 			}
 		}
 	}
@@ -332,10 +506,10 @@ func (q *baseQuery) _appendFirstTable(
 	}
 
 	if q.table != nil {
-		b = fmter.AppendQuery(b, string(q.table.SQLName))
+		b = q.appendTableName(fmter, b)
 		if withAlias {
-			b = append(b, " AS "...)
-			b = append(b, q.table.SQLAlias...)
+			b = fmter.AppendKeywords(b, " AS ")
+			b = append(b, q.tableAlias()...)
 		}
 		return b, nil
 	}
@@ -347,6 +521,40 @@ func (q *baseQuery) _appendFirstTable(
 	return nil, errors.New("bun: query does not have a table")
 }
 
+// appendTableName appends the model table name qualified with the schema set by TableSchema.
+func (q *baseQuery) appendTableName(fmter schema.Formatter, b []byte) []byte {
+	if q.tableSchema == "" {
+		return fmter.AppendQuery(b, string(q.table.SQLName))
+	}
+
+	name := q.table.Name
+	if i := strings.LastIndexByte(name, '.'); i >= 0 {
+		name = name[i+1:]
+	}
+
+	b = fmter.AppendIdent(b, q.tableSchema)
+	b = append(b, '.')
+	return fmter.AppendIdent(b, name)
+}
+
+// queryString formats the query with the DB formatter. It returns the error text
+// if the query can't be formatted.
+func (q *baseQuery) queryString(query schema.QueryAppender) string {
+	b, err := query.AppendQuery(q.db.fmter, nil)
+	if err != nil {
+		return err.Error()
+	}
+	return string(b)
+}
+
+// tableAlias returns the quoted alias of the model table.
+func (q *baseQuery) tableAlias() schema.Safe {
+	if q.modelTableAlias != "" {
+		return q.modelTableAlias
+	}
+	return q.table.SQLAlias
+}
+
 func (q *baseQuery) hasMultiTables() bool {
 	if q.modelHasTableName() {
 		return len(q.tables) >= 1
@@ -436,13 +644,33 @@ func (q *baseQuery) scan(
 	model model,
 	hasDest bool,
 ) (res result, _ error) {
-	ctx, event := q.db.beforeQuery(ctx, queryApp, query, nil)
-
-	rows, err := q.conn.QueryContext(ctx, query)
-	if err != nil {
-		q.db.afterQuery(ctx, event, nil, err)
+	if err := q.checkIsolation(); err != nil {
 		return res, err
 	}
+
+	query = q.appendComment(ctx, query)
+
+	ctx, cancel := q.withTimeout(ctx)
+	defer cancel()
+
+	var rows *sql.Rows
+	var event *QueryEvent
+	for attempt := 0; ; attempt++ {
+		var attemptCtx context.Context
+		attemptCtx, event = q.db.beforeQueryAttempt(ctx, queryApp, query, nil, attempt)
+
+		var err error
+		rows, err = q.queryContext(attemptCtx, queryApp, query)
+		if err == nil {
+			ctx = attemptCtx
+			break
+		}
+
+		q.db.afterQuery(attemptCtx, event, nil, err)
+		if !q.db.retryQuery(ctx, q.inTx, attempt, err) {
+			return res, err
+		}
+	}
 	defer rows.Close()
 
 	n, err := model.ScanRows(ctx, rows)
@@ -452,8 +680,11 @@ func (q *baseQuery) scan(
 	}
 
 	res.n = n
-	if n == 0 && hasDest && isSingleRowModel(model) {
+	switch {
+	case n == 0 && (q.flags.Has(scanOneFlag) || hasDest && isSingleRowModel(model)):
 		err = sql.ErrNoRows
+	case q.flags.Has(scanOneFlag) && (n > 1 || rows.Next()):
+		err = ErrMultipleRows
 	}
 
 	q.db.afterQuery(ctx, event, nil, err)
@@ -466,22 +697,168 @@ func (q *baseQuery) exec(
 	queryApp schema.QueryAppender,
 	query string,
 ) (res result, _ error) {
-	ctx, event := q.db.beforeQuery(ctx, queryApp, query, nil)
+	if err := q.checkIsolation(); err != nil {
+		return res, err
+	}
+
+	query = q.appendComment(ctx, query)
+
+	ctx, cancel := q.withTimeout(ctx)
+	defer cancel()
+
+	for attempt := 0; ; attempt++ {
+		ctx, event := q.db.beforeQueryAttempt(ctx, queryApp, query, nil, attempt)
+
+		r, err := q.execContext(ctx, queryApp, query)
+		if err == nil {
+			res.r = r
+			q.db.afterQuery(ctx, event, nil, nil)
+			return res, nil
+		}
 
-	r, err := q.conn.ExecContext(ctx, query)
-	if err != nil {
 		q.db.afterQuery(ctx, event, nil, err)
-		return res, err
+		if !q.db.retryQuery(ctx, q.inTx, attempt, err) {
+			return res, err
+		}
 	}
+}
 
-	res.r = r
+// prepare formats the query using driver placeholders for the args
+// and creates a prepared statement for later execution. The args are kept
+// as the default args of the statement.
+func (q *baseQuery) prepare(ctx context.Context, queryApp schema.QueryAppender) (Stmt, error) {
+	if err := q.beforeAppendQueryHook(ctx, queryApp); err != nil {
+		return Stmt{}, err
+	}
 
-	q.db.afterQuery(ctx, event, nil, err)
-	return res, nil
+	conn, ok := q.conn.(interface {
+		PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+	})
+	if !ok {
+		return Stmt{}, fmt.Errorf("bun: %T does not support prepared statements", q.conn)
+	}
+
+	var params []interface{}
+	queryBytes, err := queryApp.AppendQuery(q.db.fmter.WithParams(&params), q.db.makeQueryBytes())
+	if err != nil {
+		return Stmt{}, err
+	}
+
+	query := q.appendComment(ctx, internal.String(queryBytes))
+
+	stmt, err := conn.PrepareContext(ctx, query)
+	if err != nil {
+		return Stmt{}, err
+	}
+	return Stmt{
+		db:       q.db,
+		Stmt:     stmt,
+		queryApp: queryApp,
+		query:    query,
+		args:     params,
+	}, nil
+}
+
+// withTimeout returns the ctx with the deadline set by the query Timeout.
+func (q *baseQuery) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if q.timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, q.timeout)
+}
+
+type commentsKey struct{}
+
+// ContextWithComment returns a copy of the ctx with the key-value pair added to
+// the sqlcommenter comment of the queries executed with the ctx,
+// for example, to trace SQL back to the HTTP handler that issued it.
+// Keys set with the query Comment method take precedence.
+func ContextWithComment(ctx context.Context, key, value string) context.Context {
+	parent, _ := ctx.Value(commentsKey{}).(map[string]string)
+	comments := make(map[string]string, len(parent)+1)
+	for k, v := range parent {
+		comments[k] = v
+	}
+	comments[key] = value
+	return context.WithValue(ctx, commentsKey{}, comments)
+}
+
+type namedArg struct {
+	name  string
+	value interface{}
+}
+
+func (q *baseQuery) addNamedArg(name string, value interface{}) {
+	q.namedArgs = append(q.namedArgs, namedArg{name: name, value: value})
+}
+
+// withNamedArgs returns the formatter that also resolves the query named args.
+func (q *baseQuery) withNamedArgs(fmter schema.Formatter) schema.Formatter {
+	for _, arg := range q.namedArgs {
+		fmter = fmter.WithArg(arg.name, arg.value)
+	}
+	return fmter
+}
+
+func (q *baseQuery) addComment(key, value string) {
+	if q.comments == nil {
+		q.comments = make(map[string]string)
+	}
+	q.comments[key] = value
+}
+
+// appendComment appends the query comments to the query using sqlcommenter format,
+// for example, `SELECT 1 /*key='value'*/`.
+func (q *baseQuery) appendComment(ctx context.Context, query string) string {
+	var dbComments map[string]string
+	if q.db.queryComment != nil {
+		dbComments = q.db.queryComment(ctx)
+	}
+	ctxComments, _ := ctx.Value(commentsKey{}).(map[string]string)
+
+	comments := q.comments
+	if len(dbComments) > 0 || len(ctxComments) > 0 {
+		comments = make(map[string]string, len(dbComments)+len(ctxComments)+len(q.comments))
+		for _, m := range []map[string]string{dbComments, ctxComments, q.comments} {
+			for k, v := range m {
+				comments[k] = v
+			}
+		}
+	}
+
+	if len(comments) == 0 {
+		return query
+	}
+
+	keys := make([]string, 0, len(comments))
+	for k := range comments {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	b := make([]byte, 0, len(query)+64)
+	b = append(b, query...)
+	b = append(b, " /*"...)
+	for i, k := range keys {
+		if i > 0 {
+			b = append(b, ',')
+		}
+		// URL encoding also escapes quotes and asterisks
+		// so the value can't terminate the comment.
+		b = append(b, url.PathEscape(k)...)
+		b = append(b, "='"...)
+		b = append(b, url.PathEscape(comments[k])...)
+		b = append(b, '\'')
+	}
+	b = append(b, "*/"...)
+
+	return internal.String(b)
 }
 
 //------------------------------------------------------------------------------
 
+// AppendNamedArg appends the model placeholders that can be used in raw query fragments:
+// ?TableName, ?TableAlias, ?PKs, ?TablePKs, ?Columns, and ?TableColumns.
 func (q *baseQuery) AppendNamedArg(fmter schema.Formatter, b []byte, name string) ([]byte, bool) {
 	if q.table == nil {
 		return b, false
@@ -489,22 +866,22 @@ func (q *baseQuery) AppendNamedArg(fmter schema.Formatter, b []byte, name string
 
 	switch name {
 	case "TableName":
-		b = fmter.AppendQuery(b, string(q.table.SQLName))
+		b = q.appendTableName(fmter, b)
 		return b, true
 	case "TableAlias":
-		b = fmter.AppendQuery(b, string(q.table.SQLAlias))
+		b = fmter.AppendQuery(b, string(q.tableAlias()))
 		return b, true
 	case "PKs":
 		b = appendColumns(b, "", q.table.PKs)
 		return b, true
 	case "TablePKs":
-		b = appendColumns(b, q.table.SQLAlias, q.table.PKs)
+		b = appendColumns(b, q.tableAlias(), q.table.PKs)
 		return b, true
 	case "Columns":
 		b = appendColumns(b, "", q.table.Fields)
 		return b, true
 	case "TableColumns":
-		b = appendColumns(b, q.table.SQLAlias, q.table.Fields)
+		b = appendColumns(b, q.tableAlias(), q.table.Fields)
 		return b, true
 	}
 
@@ -545,6 +922,110 @@ func (q *whereBaseQuery) addWhere(where schema.QueryWithSep) {
 	q.where = append(q.where, where)
 }
 
+func (q *whereBaseQuery) addWhereColumn(left, op, right string) {
+	switch strings.ToUpper(op) {
+	case "=", "<>", "!=", "<", "<=", ">", ">=",
+		"LIKE", "NOT LIKE", "ILIKE", "NOT ILIKE",
+		"IS DISTINCT FROM", "IS NOT DISTINCT FROM":
+	default:
+		q.setErr(fmt.Errorf("bun: WhereColumn(unsupported operator %q)", op))
+		return
+	}
+
+	q.addWhere(schema.SafeQueryWithSep("?", []interface{}{
+		whereColumn{left: Ident(left), op: op, right: Ident(right)},
+	}, " AND "))
+}
+
+// whereColumn compares two columns. Dialects with the null-safe equal operator `<=>`
+// use it instead of IS DISTINCT FROM, and dialects without ILIKE compare
+// the lowercased columns with LIKE.
+type whereColumn struct {
+	left  Ident
+	op    string
+	right Ident
+}
+
+var _ schema.QueryAppender = whereColumn{}
+
+func (w whereColumn) AppendQuery(fmter schema.Formatter, b []byte) (_ []byte, err error) {
+	op := w.op
+	not := false
+	lower := false
+	switch strings.ToUpper(op) {
+	case "IS DISTINCT FROM":
+		if fmter.HasFeature(feature.NullSafeEqual) {
+			op, not = "<=>", true
+		}
+	case "IS NOT DISTINCT FROM":
+		if fmter.HasFeature(feature.NullSafeEqual) {
+			op = "<=>"
+		}
+	case "ILIKE":
+		if !fmter.HasFeature(feature.ILike) {
+			op, lower = "LIKE", true
+		}
+	case "NOT ILIKE":
+		if !fmter.HasFeature(feature.ILike) {
+			op, lower = "NOT LIKE", true
+		}
+	}
+
+	if not {
+		b = fmter.AppendKeywords(b, "NOT (")
+	}
+	b, err = w.appendColumn(fmter, b, w.left, lower)
+	if err != nil {
+		return nil, err
+	}
+	b = append(b, ' ')
+	b = append(b, op...)
+	b = append(b, ' ')
+	b, err = w.appendColumn(fmter, b, w.right, lower)
+	if err != nil {
+		return nil, err
+	}
+	if not {
+		b = append(b, ')')
+	}
+	return b, nil
+}
+
+func (w whereColumn) appendColumn(
+	fmter schema.Formatter, b []byte, column Ident, lower bool,
+) (_ []byte, err error) {
+	if lower {
+		b = fmter.AppendKeywords(b, "LOWER(")
+	}
+	b, err = column.AppendQuery(fmter, b)
+	if err != nil {
+		return nil, err
+	}
+	if lower {
+		b = append(b, ')')
+	}
+	return b, nil
+}
+
+func (q *whereBaseQuery) addWhereIn(column string, values interface{}) {
+	arg, ok := values.(schema.QueryAppender)
+	if !ok {
+		arg = In(values)
+	}
+	q.addWhere(schema.SafeQueryWithSep("? IN (?)", []interface{}{Ident(column), arg}, " AND "))
+}
+
+func (q *whereBaseQuery) addWhereExists(op string, subq *SelectQuery) {
+	q.addWhere(schema.SafeQueryWithSep(op+" (?)", []interface{}{subq}, " AND "))
+}
+
+func (q *whereBaseQuery) addWhereIfNotZero(column string, value interface{}) {
+	if schema.IsZero(value) {
+		return
+	}
+	q.addWhere(schema.SafeQueryWithSep("? = ?", []interface{}{Ident(column), value}, " AND "))
+}
+
 func (q *whereBaseQuery) addWhereGroup(sep string, where []schema.QueryWithSep) {
 	if len(where) == 0 {
 		return
@@ -574,7 +1055,7 @@ func (q *whereBaseQuery) appendWhere(
 		return b, nil
 	}
 
-	b = append(b, " WHERE "...)
+	b = fmter.AppendKeywords(b, " WHERE ")
 	startLen := len(b)
 
 	if len(q.where) > 0 {
@@ -586,23 +1067,23 @@ func (q *whereBaseQuery) appendWhere(
 
 	if q.isSoftDelete() {
 		if len(b) > startLen {
-			b = append(b, " AND "...)
+			b = fmter.AppendKeywords(b, " AND ")
 		}
 		if withAlias {
-			b = append(b, q.tableModel.Table().SQLAlias...)
+			b = append(b, q.tableAlias()...)
 			b = append(b, '.')
 		}
 		b = append(b, q.tableModel.Table().SoftDeleteField.SQLName...)
 		if q.flags.Has(deletedFlag) {
-			b = append(b, " IS NOT NULL"...)
+			b = fmter.AppendKeywords(b, " IS NOT NULL")
 		} else {
-			b = append(b, " IS NULL"...)
+			b = fmter.AppendKeywords(b, " IS NULL")
 		}
 	}
 
 	if q.flags.Has(wherePKFlag) {
 		if len(b) > startLen {
-			b = append(b, " AND "...)
+			b = fmter.AppendKeywords(b, " AND ")
 		}
 		b, err = q.appendWherePK(fmter, b, withAlias)
 		if err != nil {
@@ -667,16 +1148,16 @@ func (q *whereBaseQuery) appendWherePKStruct(
 	b = append(b, '(')
 	for i, f := range q.table.PKs {
 		if i > 0 {
-			b = append(b, " AND "...)
+			b = fmter.AppendKeywords(b, " AND ")
 		}
 		if withAlias {
-			b = append(b, q.table.SQLAlias...)
+			b = append(b, q.tableAlias()...)
 			b = append(b, '.')
 		}
 		b = append(b, f.SQLName...)
 		b = append(b, " = "...)
 		if isTemplate {
-			b = append(b, '?')
+			b = f.AppendTemplateValue(fmter, b, model.strct)
 		} else {
 			b = f.AppendValue(fmter, b, model.strct)
 		}
@@ -692,7 +1173,7 @@ func (q *whereBaseQuery) appendWherePKSlice(
 		b = append(b, '(')
 	}
 	if withAlias {
-		b = appendColumns(b, q.table.SQLAlias, q.table.PKs)
+		b = appendColumns(b, q.tableAlias(), q.table.PKs)
 	} else {
 		b = appendColumns(b, "", q.table.PKs)
 	}
@@ -700,7 +1181,7 @@ func (q *whereBaseQuery) appendWherePKSlice(
 		b = append(b, ')')
 	}
 
-	b = append(b, " IN ("...)
+	b = fmter.AppendKeywords(b, " IN (")
 
 	isTemplate := fmter.IsNop()
 	slice := model.slice
@@ -723,7 +1204,7 @@ func (q *whereBaseQuery) appendWherePKSlice(
 				b = append(b, ", "...)
 			}
 			if isTemplate {
-				b = append(b, '?')
+				b = f.AppendTemplateValue(fmter, b, el)
 			} else {
 				b = f.AppendValue(fmter, b, el)
 			}
@@ -761,24 +1242,56 @@ func (q *returningQuery) addReturningField(field *schema.Field) {
 	q.returningFields = append(q.returningFields, field)
 }
 
+// returningRequested reports whether the RETURNING clause was added with Returning.
+func (q *returningQuery) returningRequested() bool {
+	if q.returningDisabled() {
+		return false
+	}
+	return len(q.returning) > 0
+}
+
 func (q *returningQuery) hasReturning() bool {
-	if len(q.returning) == 1 {
-		switch q.returning[0].Query {
-		case "null", "NULL":
-			return false
-		}
+	if q.returningDisabled() {
+		return false
 	}
 	return len(q.returning) > 0 || len(q.returningFields) > 0
 }
 
+// returningDisabled reports whether the query was created with Returning("NULL")
+// or Returning("NOTHING") to skip scanning the returned columns.
+func (q *returningQuery) returningDisabled() bool {
+	if len(q.returning) != 1 {
+		return false
+	}
+	switch q.returning[0].Query {
+	case "null", "NULL":
+		return true
+	}
+	return q.returningNothing()
+}
+
+func (q *returningQuery) returningNothing() bool {
+	if len(q.returning) != 1 {
+		return false
+	}
+	switch q.returning[0].Query {
+	case "nothing", "NOTHING":
+		return true
+	}
+	return false
+}
+
 func (q *returningQuery) appendReturning(
 	fmter schema.Formatter, b []byte,
 ) (_ []byte, err error) {
+	if q.returningNothing() && fmter.HasFeature(feature.ReturningNothing) {
+		return fmter.AppendKeywords(b, " RETURNING NOTHING"), nil
+	}
 	if !q.hasReturning() {
 		return b, nil
 	}
 
-	b = append(b, " RETURNING "...)
+	b = fmter.AppendKeywords(b, " RETURNING ")
 
 	for i, f := range q.returning {
 		if i > 0 {
@@ -860,9 +1373,9 @@ func (q cascadeQuery) appendCascade(fmter schema.Formatter, b []byte) []byte {
 		return b
 	}
 	if q.restrict {
-		b = append(b, " RESTRICT"...)
+		b = fmter.AppendKeywords(b, " RESTRICT")
 	} else {
-		b = append(b, " CASCADE"...)
+		b = fmter.AppendKeywords(b, " CASCADE")
 	}
 	return b
 }
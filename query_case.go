@@ -0,0 +1,112 @@
+package bun
+
+import (
+	"errors"
+
+	"github.com/uptrace/bun/schema"
+)
+
+type caseWhen struct {
+	cond schema.QueryWithArgs
+	then schema.QueryWithArgs
+}
+
+// CaseBuilder builds a `CASE WHEN ... THEN ... ELSE ... END` expression.
+type CaseBuilder struct {
+	whens []caseWhen
+	els   schema.QueryWithArgs
+	alias string
+	err   error
+}
+
+var _ schema.QueryAppender = (*CaseBuilder)(nil)
+
+// When adds a `WHEN cond` branch. It must be followed by Then or ThenExpr.
+func (c *CaseBuilder) When(query string, args ...interface{}) *CaseBuilder {
+	c.whens = append(c.whens, caseWhen{cond: schema.SafeQuery(query, args)})
+	return c
+}
+
+// Then sets the value of the last When branch.
+func (c *CaseBuilder) Then(value interface{}) *CaseBuilder {
+	return c.ThenExpr("?", value)
+}
+
+// ThenExpr sets the expression of the last When branch.
+func (c *CaseBuilder) ThenExpr(query string, args ...interface{}) *CaseBuilder {
+	if len(c.whens) == 0 || !c.whens[len(c.whens)-1].then.IsZero() {
+		c.setErr(errors.New("bun: CASE Then must follow When"))
+		return c
+	}
+	c.whens[len(c.whens)-1].then = schema.SafeQuery(query, args)
+	return c
+}
+
+// Else sets the value used when no When branch matches.
+func (c *CaseBuilder) Else(value interface{}) *CaseBuilder {
+	return c.ElseExpr("?", value)
+}
+
+// ElseExpr sets the expression used when no When branch matches.
+func (c *CaseBuilder) ElseExpr(query string, args ...interface{}) *CaseBuilder {
+	c.els = schema.SafeQuery(query, args)
+	return c
+}
+
+// As sets the column alias.
+func (c *CaseBuilder) As(alias string) *CaseBuilder {
+	c.alias = alias
+	return c
+}
+
+func (c *CaseBuilder) setErr(err error) {
+	if c.err == nil {
+		c.err = err
+	}
+}
+
+func (c *CaseBuilder) AppendQuery(fmter schema.Formatter, b []byte) (_ []byte, err error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	if len(c.whens) == 0 {
+		return nil, errors.New("bun: CASE requires at least one When")
+	}
+
+	b = fmter.AppendKeywords(b, "CASE")
+
+	for _, when := range c.whens {
+		if when.then.IsZero() {
+			return nil, errors.New("bun: CASE When requires Then")
+		}
+
+		b = fmter.AppendKeywords(b, " WHEN ")
+		b, err = when.cond.AppendQuery(fmter, b)
+		if err != nil {
+			return nil, err
+		}
+
+		b = fmter.AppendKeywords(b, " THEN ")
+		b, err = when.then.AppendQuery(fmter, b)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if !c.els.IsZero() {
+		b = fmter.AppendKeywords(b, " ELSE ")
+		b, err = c.els.AppendQuery(fmter, b)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	b = fmter.AppendKeywords(b, " END")
+
+	if c.alias != "" {
+		b = fmter.AppendKeywords(b, " AS ")
+		b = fmter.AppendIdent(b, c.alias)
+	}
+
+	return b, nil
+}
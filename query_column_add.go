@@ -13,6 +13,8 @@ type AddColumnQuery struct {
 	baseQuery
 }
 
+var _ Query = (*AddColumnQuery)(nil)
+
 func NewAddColumnQuery(db *DB) *AddColumnQuery {
 	q := &AddColumnQuery{
 		baseQuery: baseQuery{
@@ -52,6 +54,12 @@ func (q *AddColumnQuery) ModelTableExpr(query string, args ...interface{}) *AddC
 	return q
 }
 
+// TableSchema qualifies the model table name with the schema.
+func (q *AddColumnQuery) TableSchema(schema string) *AddColumnQuery {
+	q.tableSchema = schema
+	return q
+}
+
 //------------------------------------------------------------------------------
 
 func (q *AddColumnQuery) ColumnExpr(query string, args ...interface{}) *AddColumnQuery {
@@ -59,6 +67,14 @@ func (q *AddColumnQuery) ColumnExpr(query string, args ...interface{}) *AddColum
 	return q
 }
 
+func (q *AddColumnQuery) Operation() string {
+	return "ADD COLUMN"
+}
+
+func (q *AddColumnQuery) String() string {
+	return q.queryString(q)
+}
+
 //------------------------------------------------------------------------------
 
 func (q *AddColumnQuery) AppendQuery(fmter schema.Formatter, b []byte) (_ []byte, err error) {
@@ -69,14 +85,14 @@ func (q *AddColumnQuery) AppendQuery(fmter schema.Formatter, b []byte) (_ []byte
 		return nil, fmt.Errorf("bun: AddColumnQuery requires exactly one column")
 	}
 
-	b = append(b, "ALTER TABLE "...)
+	b = fmter.AppendKeywords(b, "ALTER TABLE ")
 
 	b, err = q.appendFirstTable(fmter, b)
 	if err != nil {
 		return nil, err
 	}
 
-	b = append(b, " ADD "...)
+	b = fmter.AppendKeywords(b, " ADD ")
 
 	b, err = q.columns[0].AppendQuery(fmter, b)
 	if err != nil {
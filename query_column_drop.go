@@ -13,6 +13,8 @@ type DropColumnQuery struct {
 	baseQuery
 }
 
+var _ Query = (*DropColumnQuery)(nil)
+
 func NewDropColumnQuery(db *DB) *DropColumnQuery {
 	q := &DropColumnQuery{
 		baseQuery: baseQuery{
@@ -52,6 +54,12 @@ func (q *DropColumnQuery) ModelTableExpr(query string, args ...interface{}) *Dro
 	return q
 }
 
+// TableSchema qualifies the model table name with the schema.
+func (q *DropColumnQuery) TableSchema(schema string) *DropColumnQuery {
+	q.tableSchema = schema
+	return q
+}
+
 //------------------------------------------------------------------------------
 
 func (q *DropColumnQuery) Column(columns ...string) *DropColumnQuery {
@@ -66,6 +74,14 @@ func (q *DropColumnQuery) ColumnExpr(query string, args ...interface{}) *DropCol
 	return q
 }
 
+func (q *DropColumnQuery) Operation() string {
+	return "DROP COLUMN"
+}
+
+func (q *DropColumnQuery) String() string {
+	return q.queryString(q)
+}
+
 //------------------------------------------------------------------------------
 
 func (q *DropColumnQuery) AppendQuery(fmter schema.Formatter, b []byte) (_ []byte, err error) {
@@ -76,14 +92,14 @@ func (q *DropColumnQuery) AppendQuery(fmter schema.Formatter, b []byte) (_ []byt
 		return nil, fmt.Errorf("bun: DropColumnQuery requires exactly one column")
 	}
 
-	b = append(b, "ALTER TABLE "...)
+	b = fmter.AppendKeywords(b, "ALTER TABLE ")
 
 	b, err = q.appendFirstTable(fmter, b)
 	if err != nil {
 		return nil, err
 	}
 
-	b = append(b, " DROP COLUMN "...)
+	b = fmter.AppendKeywords(b, " DROP COLUMN ")
 
 	b, err = q.columns[0].AppendQuery(fmter, b)
 	if err != nil {
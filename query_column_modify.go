@@ -0,0 +1,166 @@
+package bun
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/uptrace/bun/dialect/feature"
+	"github.com/uptrace/bun/internal"
+	"github.com/uptrace/bun/schema"
+)
+
+// ModifyColumnQuery changes the column type to the type of the model field,
+// for example, `ALTER COLUMN "name" TYPE varchar(100)` or `MODIFY COLUMN` on MySQL.
+type ModifyColumnQuery struct {
+	baseQuery
+}
+
+var _ Query = (*ModifyColumnQuery)(nil)
+
+func NewModifyColumnQuery(db *DB) *ModifyColumnQuery {
+	q := &ModifyColumnQuery{
+		baseQuery: baseQuery{
+			db:   db,
+			conn: db.DB,
+		},
+	}
+	return q
+}
+
+func (q *ModifyColumnQuery) Conn(db IConn) *ModifyColumnQuery {
+	q.setConn(db)
+	return q
+}
+
+func (q *ModifyColumnQuery) Model(model interface{}) *ModifyColumnQuery {
+	q.setTableModel(model)
+	return q
+}
+
+//------------------------------------------------------------------------------
+
+func (q *ModifyColumnQuery) Table(tables ...string) *ModifyColumnQuery {
+	for _, table := range tables {
+		q.addTable(schema.UnsafeIdent(table))
+	}
+	return q
+}
+
+func (q *ModifyColumnQuery) TableExpr(query string, args ...interface{}) *ModifyColumnQuery {
+	q.addTable(schema.SafeQuery(query, args))
+	return q
+}
+
+func (q *ModifyColumnQuery) ModelTableExpr(query string, args ...interface{}) *ModifyColumnQuery {
+	q.modelTable = schema.SafeQuery(query, args)
+	return q
+}
+
+// TableSchema qualifies the model table name with the schema.
+func (q *ModifyColumnQuery) TableSchema(schema string) *ModifyColumnQuery {
+	q.tableSchema = schema
+	return q
+}
+
+//------------------------------------------------------------------------------
+
+func (q *ModifyColumnQuery) Column(columns ...string) *ModifyColumnQuery {
+	for _, column := range columns {
+		q.addColumn(schema.UnsafeIdent(column))
+	}
+	return q
+}
+
+func (q *ModifyColumnQuery) Operation() string {
+	return "MODIFY COLUMN"
+}
+
+func (q *ModifyColumnQuery) String() string {
+	return q.queryString(q)
+}
+
+//------------------------------------------------------------------------------
+
+func (q *ModifyColumnQuery) AppendQuery(fmter schema.Formatter, b []byte) (_ []byte, err error) {
+	if q.err != nil {
+		return nil, q.err
+	}
+	if q.table == nil {
+		return nil, errNilModel
+	}
+	if len(q.columns) != 1 {
+		return nil, fmt.Errorf("bun: ModifyColumnQuery requires exactly one column")
+	}
+
+	field, err := q.table.Field(q.columns[0].Query)
+	if err != nil {
+		return nil, err
+	}
+
+	b = fmter.AppendKeywords(b, "ALTER TABLE ")
+
+	b, err = q.appendFirstTable(fmter, b)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case fmter.HasFeature(feature.ModifyColumn):
+		// MySQL redefines the whole column.
+		b = fmter.AppendKeywords(b, " MODIFY COLUMN ")
+		b = append(b, field.SQLName...)
+		b = appendColumnDefinition(fmter, b, field)
+	case fmter.HasFeature(feature.AlterColumnType):
+		b = fmter.AppendKeywords(b, " ALTER COLUMN ")
+		b = append(b, field.SQLName...)
+		b = fmter.AppendKeywords(b, " TYPE ")
+		// Serial types are pseudo-types that are only valid in CREATE TABLE.
+		if strings.HasSuffix(strings.ToUpper(field.CreateTableSQLType), "SERIAL") {
+			b = append(b, field.DiscoveredSQLType...)
+		} else {
+			b = append(b, field.CreateTableSQLType...)
+		}
+	default:
+		return nil, fmt.Errorf("bun: %s does not support changing column types", q.db.dialect.Name())
+	}
+
+	return b, nil
+}
+
+// appendColumnDefinition appends the column type and attributes that MySQL
+// requires to redefine the column with MODIFY COLUMN and CHANGE.
+func appendColumnDefinition(fmter schema.Formatter, b []byte, field *schema.Field) []byte {
+	b = append(b, ' ')
+	b = append(b, field.CreateTableSQLType...)
+	if field.NotNull {
+		b = fmter.AppendKeywords(b, " NOT NULL")
+	}
+	if fmter.HasFeature(feature.AutoIncrement) && field.AutoIncrement {
+		b = fmter.AppendKeywords(b, " AUTO_INCREMENT")
+	}
+	if field.SQLDefault != "" {
+		b = fmter.AppendKeywords(b, " DEFAULT ")
+		b = append(b, field.SQLDefault...)
+	}
+	return b
+}
+
+//------------------------------------------------------------------------------
+
+func (q *ModifyColumnQuery) Exec(ctx context.Context, dest ...interface{}) (sql.Result, error) {
+	queryBytes, err := q.AppendQuery(q.db.fmter, q.db.makeQueryBytes())
+	if err != nil {
+		return nil, err
+	}
+
+	query := internal.String(queryBytes)
+
+	res, err := q.exec(ctx, q, query)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
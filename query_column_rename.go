@@ -0,0 +1,163 @@
+package bun
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/uptrace/bun/dialect/feature"
+	"github.com/uptrace/bun/internal"
+	"github.com/uptrace/bun/schema"
+)
+
+type RenameColumnQuery struct {
+	baseQuery
+
+	newName string
+}
+
+var _ Query = (*RenameColumnQuery)(nil)
+
+func NewRenameColumnQuery(db *DB) *RenameColumnQuery {
+	q := &RenameColumnQuery{
+		baseQuery: baseQuery{
+			db:   db,
+			conn: db.DB,
+		},
+	}
+	return q
+}
+
+func (q *RenameColumnQuery) Conn(db IConn) *RenameColumnQuery {
+	q.setConn(db)
+	return q
+}
+
+func (q *RenameColumnQuery) Model(model interface{}) *RenameColumnQuery {
+	q.setTableModel(model)
+	return q
+}
+
+//------------------------------------------------------------------------------
+
+func (q *RenameColumnQuery) Table(tables ...string) *RenameColumnQuery {
+	for _, table := range tables {
+		q.addTable(schema.UnsafeIdent(table))
+	}
+	return q
+}
+
+func (q *RenameColumnQuery) TableExpr(query string, args ...interface{}) *RenameColumnQuery {
+	q.addTable(schema.SafeQuery(query, args))
+	return q
+}
+
+func (q *RenameColumnQuery) ModelTableExpr(query string, args ...interface{}) *RenameColumnQuery {
+	q.modelTable = schema.SafeQuery(query, args)
+	return q
+}
+
+// TableSchema qualifies the model table name with the schema.
+func (q *RenameColumnQuery) TableSchema(schema string) *RenameColumnQuery {
+	q.tableSchema = schema
+	return q
+}
+
+//------------------------------------------------------------------------------
+
+// Rename renames the column, for example, `Rename("name", "full_name")`.
+func (q *RenameColumnQuery) Rename(column, newName string) *RenameColumnQuery {
+	q.columns = []schema.QueryWithArgs{schema.UnsafeIdent(column)}
+	q.newName = newName
+	return q
+}
+
+func (q *RenameColumnQuery) Operation() string {
+	return "RENAME COLUMN"
+}
+
+func (q *RenameColumnQuery) String() string {
+	return q.queryString(q)
+}
+
+//------------------------------------------------------------------------------
+
+func (q *RenameColumnQuery) AppendQuery(fmter schema.Formatter, b []byte) (_ []byte, err error) {
+	if q.err != nil {
+		return nil, q.err
+	}
+	if len(q.columns) != 1 || q.newName == "" {
+		return nil, fmt.Errorf("bun: RenameColumnQuery requires Rename(column, newName)")
+	}
+
+	b = fmter.AppendKeywords(b, "ALTER TABLE ")
+
+	b, err = q.appendFirstTable(fmter, b)
+	if err != nil {
+		return nil, err
+	}
+
+	if !fmter.HasFeature(feature.RenameColumn) {
+		return q.appendChange(fmter, b)
+	}
+
+	b = fmter.AppendKeywords(b, " RENAME COLUMN ")
+
+	b, err = q.columns[0].AppendQuery(fmter, b)
+	if err != nil {
+		return nil, err
+	}
+
+	b = fmter.AppendKeywords(b, " TO ")
+	b = fmter.AppendIdent(b, q.newName)
+
+	return b, nil
+}
+
+// appendChange appends `CHANGE old new definition` for dialects that do not support
+// RENAME COLUMN, for example, MySQL 5.7. The column definition is taken from the model field with the old
+// or the new name.
+func (q *RenameColumnQuery) appendChange(fmter schema.Formatter, b []byte) (_ []byte, err error) {
+	if q.table == nil {
+		return nil, fmt.Errorf("bun: RENAME COLUMN on %s requires a model", fmter.Dialect().Name())
+	}
+
+	column := q.columns[0].Query
+	field, ok := q.table.FieldMap[column]
+	if !ok {
+		field, ok = q.table.FieldMap[q.newName]
+	}
+	if !ok {
+		return nil, fmt.Errorf("bun: %s does not have column=%q or column=%q",
+			q.table.TypeName, column, q.newName)
+	}
+
+	b = fmter.AppendKeywords(b, " CHANGE ")
+	b, err = q.columns[0].AppendQuery(fmter, b)
+	if err != nil {
+		return nil, err
+	}
+	b = append(b, ' ')
+	b = fmter.AppendIdent(b, q.newName)
+	b = appendColumnDefinition(fmter, b, field)
+
+	return b, nil
+}
+
+//------------------------------------------------------------------------------
+
+func (q *RenameColumnQuery) Exec(ctx context.Context, dest ...interface{}) (sql.Result, error) {
+	queryBytes, err := q.AppendQuery(q.db.fmter, q.db.makeQueryBytes())
+	if err != nil {
+		return nil, err
+	}
+
+	query := internal.String(queryBytes)
+
+	res, err := q.exec(ctx, q, query)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
@@ -0,0 +1,618 @@
+package bun
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/uptrace/bun/dialect/feature"
+	"github.com/uptrace/bun/internal"
+	"github.com/uptrace/bun/schema"
+)
+
+var (
+	driverValuerType = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+	bytesType        = reflect.TypeOf((*[]byte)(nil)).Elem()
+)
+
+// copyFromConn is implemented by driver connections that support the COPY protocol,
+// for example, pgdriver.Conn.
+type copyFromConn interface {
+	CopyFrom(ctx context.Context, r io.Reader, query string) (int64, error)
+}
+
+type CopyFromQuery struct {
+	baseQuery
+
+	options []schema.QueryWithArgs
+}
+
+var _ Query = (*CopyFromQuery)(nil)
+
+func NewCopyFromQuery(db *DB) *CopyFromQuery {
+	q := &CopyFromQuery{
+		baseQuery: baseQuery{
+			db:   db,
+			conn: db.DB,
+		},
+	}
+	return q
+}
+
+func (q *CopyFromQuery) Conn(db IConn) *CopyFromQuery {
+	q.setConn(db)
+	return q
+}
+
+func (q *CopyFromQuery) Model(model interface{}) *CopyFromQuery {
+	q.setTableModel(model)
+	return q
+}
+
+// Apply calls the fn passing the CopyFromQuery as an argument.
+func (q *CopyFromQuery) Apply(fn func(*CopyFromQuery) *CopyFromQuery) *CopyFromQuery {
+	return fn(q)
+}
+
+// Comment adds a key-value pair to the sqlcommenter comment appended to the query,
+// for example, `/*action='list',route='%2Fbooks'*/`.
+func (q *CopyFromQuery) Comment(key, value string) *CopyFromQuery {
+	q.addComment(key, value)
+	return q
+}
+
+//------------------------------------------------------------------------------
+
+func (q *CopyFromQuery) Table(tables ...string) *CopyFromQuery {
+	for _, table := range tables {
+		q.addTable(schema.UnsafeIdent(table))
+	}
+	return q
+}
+
+func (q *CopyFromQuery) TableExpr(query string, args ...interface{}) *CopyFromQuery {
+	q.addTable(schema.SafeQuery(query, args))
+	return q
+}
+
+func (q *CopyFromQuery) ModelTableExpr(query string, args ...interface{}) *CopyFromQuery {
+	q.modelTable = schema.SafeQuery(query, args)
+	return q
+}
+
+// TableSchema qualifies the model table name with the schema.
+func (q *CopyFromQuery) TableSchema(schema string) *CopyFromQuery {
+	q.tableSchema = schema
+	return q
+}
+
+//------------------------------------------------------------------------------
+
+// Column overrides the column list derived from the model.
+func (q *CopyFromQuery) Column(columns ...string) *CopyFromQuery {
+	for _, column := range columns {
+		q.addColumn(schema.UnsafeIdent(column))
+	}
+	return q
+}
+
+// Option adds a COPY option, for example, `Option("FORMAT csv")`.
+func (q *CopyFromQuery) Option(query string, args ...interface{}) *CopyFromQuery {
+	q.options = append(q.options, schema.SafeQuery(query, args))
+	return q
+}
+
+func (q *CopyFromQuery) Operation() string {
+	return "COPY"
+}
+
+func (q *CopyFromQuery) String() string {
+	return q.queryString(q)
+}
+
+//------------------------------------------------------------------------------
+
+func (q *CopyFromQuery) AppendQuery(fmter schema.Formatter, b []byte) (_ []byte, err error) {
+	if q.err != nil {
+		return nil, q.err
+	}
+
+	if !fmter.IsNop() && !fmter.HasFeature(feature.CopyFrom) {
+		return nil, fmt.Errorf("bun: COPY FROM is not supported by %s", q.db.Dialect().Name())
+	}
+
+	b = fmter.AppendKeywords(b, "COPY ")
+
+	b, err = q.appendFirstTable(fmter, b)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case q.tableModel != nil:
+		fields, err := q.getFields()
+		if err != nil {
+			return nil, err
+		}
+
+		b = append(b, " ("...)
+		b = appendColumns(b, "", fields)
+		b = append(b, ")"...)
+	case len(q.columns) > 0:
+		b = append(b, " ("...)
+		b, err = q.appendColumns(fmter, b)
+		if err != nil {
+			return nil, err
+		}
+		b = append(b, ")"...)
+	}
+
+	b = fmter.AppendKeywords(b, " FROM STDIN")
+
+	if len(q.options) > 0 {
+		b = fmter.AppendKeywords(b, " WITH (")
+		for i, opt := range q.options {
+			if i > 0 {
+				b = append(b, ", "...)
+			}
+			b, err = opt.AppendQuery(fmter, b)
+			if err != nil {
+				return nil, err
+			}
+		}
+		b = append(b, ")"...)
+	}
+
+	return b, nil
+}
+
+//------------------------------------------------------------------------------
+
+// Exec streams the data from the reader into the table using the COPY protocol.
+// The number of copied rows is available via sql.Result.RowsAffected.
+func (q *CopyFromQuery) Exec(ctx context.Context, r io.Reader) (sql.Result, error) {
+	queryBytes, err := q.AppendQuery(q.db.fmter, q.db.makeQueryBytes())
+	if err != nil {
+		return nil, err
+	}
+
+	query := internal.String(queryBytes)
+	query = q.appendComment(ctx, query)
+
+	ctx, event := q.db.beforeQuery(ctx, q, query, nil)
+
+	n, err := q.copyFrom(ctx, r, query)
+	if err != nil {
+		q.db.afterQuery(ctx, event, nil, err)
+		return nil, err
+	}
+
+	res := driver.RowsAffected(n)
+	q.db.afterQuery(ctx, event, res, nil)
+	return res, nil
+}
+
+func (q *CopyFromQuery) copyFrom(ctx context.Context, r io.Reader, query string) (int64, error) {
+	var n int64
+	err := withDriverConn(ctx, q.conn, func(driverConn interface{}) error {
+		cn, ok := driverConn.(copyFromConn)
+		if !ok {
+			return fmt.Errorf("bun: COPY FROM is not supported by %T", driverConn)
+		}
+
+		var err error
+		n, err = cn.CopyFrom(ctx, r, query)
+		return err
+	})
+	return n, err
+}
+
+//------------------------------------------------------------------------------
+
+// checkCopy returns the reason why the InsertQuery can't use COPY or LOAD DATA instead of INSERT.
+func (q *InsertQuery) checkCopy(dest []interface{}) error {
+	if q.err != nil {
+		return q.err
+	}
+	if len(dest) > 0 {
+		return errors.New("bun: COPY can't scan into dest")
+	}
+	if _, ok := q.tableModel.(*sliceTableModel); !ok {
+		return errors.New("bun: COPY requires a slice model")
+	}
+	if q.returningRequested() || !q.onConflict.IsZero() || q.ignore || q.replace ||
+		len(q.modelValues) > 0 || len(q.extraValues) > 0 {
+		return errors.New("bun: COPY does not support RETURNING, ON CONFLICT, and custom values")
+	}
+
+	switch {
+	case q.db.features.Has(feature.LoadData):
+		if _, ok := q.db.dialect.(readerDialect); !ok {
+			return fmt.Errorf("bun: %T does not support LOAD DATA", q.db.dialect)
+		}
+	case q.db.features.Has(feature.CopyFrom):
+		switch q.conn.(type) {
+		case *sql.DB, *sql.Conn:
+		default:
+			return fmt.Errorf("bun: COPY requires a DB or Conn, got %T", q.conn)
+		}
+	default:
+		return fmt.Errorf("bun: COPY is not supported by %s", q.db.dialect.Name())
+	}
+
+	fields, err := q.getFields()
+	if err != nil {
+		return err
+	}
+	loadData := q.db.features.Has(feature.LoadData)
+	for _, f := range fields {
+		if !q.isCopyable(f, loadData) {
+			return fmt.Errorf("bun: COPY does not support %s column of type %s", f.Name, f.IndirectType)
+		}
+	}
+
+	return nil
+}
+
+func (q *InsertQuery) copyFrom(ctx context.Context) (sql.Result, error) {
+	model := q.tableModel.(*sliceTableModel)
+	if model.sliceLen == 0 {
+		return nil, fmt.Errorf("bun: Insert(empty %T)", model.slice.Type())
+	}
+
+	fields, err := q.getFields()
+	if err != nil {
+		return nil, err
+	}
+	fields = copyFields(fields, model.slice)
+
+	rd := &copyRowsReader{
+		fields:   fields,
+		slice:    model.slice,
+		loadData: q.db.features.Has(feature.LoadData),
+	}
+
+	if rd.loadData {
+		return q.loadData(ctx, q.db.dialect.(readerDialect), fields, rd)
+	}
+
+	b := q.db.makeQueryBytes()
+	b = q.db.fmter.AppendKeywords(b, "COPY ")
+	b, err = q.appendFirstTable(q.db.fmter, b)
+	if err != nil {
+		return nil, err
+	}
+	b = append(b, " ("...)
+	b = appendColumns(b, "", fields)
+	b = q.db.fmter.AppendKeywords(b, ") FROM STDIN")
+
+	query := internal.String(b)
+	query = q.appendComment(ctx, query)
+
+	var n int64
+	err = withDriverConn(ctx, q.conn, func(driverConn interface{}) error {
+		cn, ok := driverConn.(copyFromConn)
+		if !ok {
+			return errCopyNotSupported
+		}
+
+		ctx, event := q.db.beforeQuery(ctx, q, query, nil)
+
+		var err error
+		n, err = cn.CopyFrom(ctx, rd, query)
+		if err != nil {
+			q.db.afterQuery(ctx, event, nil, err)
+			return err
+		}
+
+		q.db.afterQuery(ctx, event, driver.RowsAffected(n), nil)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return driver.RowsAffected(n), nil
+}
+
+// loadData inserts the rows with `LOAD DATA LOCAL INFILE` reading them from
+// the reader registered with the driver. The default LOAD DATA format matches
+// the COPY text format.
+func (q *InsertQuery) loadData(
+	ctx context.Context, d readerDialect, fields []*schema.Field, rd io.Reader,
+) (sql.Result, error) {
+	name, unregister := d.RegisterReader(rd)
+	defer unregister()
+
+	b := q.db.makeQueryBytes()
+	b = q.db.fmter.AppendKeywords(b, "LOAD DATA LOCAL INFILE ")
+	b = q.db.fmter.Dialect().Append(q.db.fmter, b, "Reader::"+name)
+	b = q.db.fmter.AppendKeywords(b, " INTO TABLE ")
+	b, err := q.appendFirstTable(q.db.fmter, b)
+	if err != nil {
+		return nil, err
+	}
+	b = append(b, " ("...)
+	b = appendColumns(b, "", fields)
+	b = append(b, ")"...)
+
+	query := internal.String(b)
+	query = q.appendComment(ctx, query)
+
+	// LOAD DATA can't be prepared, so the statement cache is not used.
+	ctx, event := q.db.beforeQuery(ctx, q, query, nil)
+	res, err := q.conn.ExecContext(ctx, query)
+	q.db.afterQuery(ctx, event, res, err)
+	return res, err
+}
+
+// copyFields omits the fields that use the column default in all rows,
+// because COPY does not support DEFAULT values.
+func copyFields(fields []*schema.Field, slice reflect.Value) []*schema.Field {
+	copied := make([]*schema.Field, 0, len(fields))
+	for _, f := range fields {
+		if f.NullZero && allZero(f, slice) {
+			continue
+		}
+		copied = append(copied, f)
+	}
+	return copied
+}
+
+func allZero(f *schema.Field, slice reflect.Value) bool {
+	for i := 0; i < slice.Len(); i++ {
+		if !f.HasZeroValue(indirect(slice.Index(i))) {
+			return false
+		}
+	}
+	return true
+}
+
+// isCopyable reports whether the values of the field can be encoded in the COPY text format:
+// driver.Valuer, scalars, time.Time, []byte, and Postgres arrays of scalars.
+func (q *InsertQuery) isCopyable(f *schema.Field, loadData bool) bool {
+	typ := f.IndirectType
+	if q.db.dialect.Tables().TypeAppender(typ) != nil {
+		return false
+	}
+	if typ.Implements(driverValuerType) || reflect.PtrTo(typ).Implements(driverValuerType) {
+		return true
+	}
+	if typ == timeType || typ == bytesType {
+		return true
+	}
+	if isCopyableScalar(typ.Kind()) {
+		return true
+	}
+	return !loadData && f.Tag.HasOption("array") &&
+		(typ.Kind() == reflect.Slice || typ.Kind() == reflect.Array) &&
+		isCopyableScalar(typ.Elem().Kind())
+}
+
+func isCopyableScalar(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// copyRowsReader encodes the slice rows in the COPY text format one row at a time.
+// LOAD DATA uses the same format by default, but expects MySQL dates and raw bytes.
+type copyRowsReader struct {
+	fields   []*schema.Field
+	slice    reflect.Value
+	loadData bool
+
+	index int
+	buf   []byte
+	pos   int
+	tmp   []byte
+}
+
+var _ io.Reader = (*copyRowsReader)(nil)
+
+func (r *copyRowsReader) Read(p []byte) (int, error) {
+	for r.pos == len(r.buf) {
+		if r.index == r.slice.Len() {
+			return 0, io.EOF
+		}
+
+		var err error
+		r.buf, err = r.appendRow(r.buf[:0], indirect(r.slice.Index(r.index)))
+		if err != nil {
+			return 0, err
+		}
+		r.pos = 0
+		r.index++
+	}
+
+	n := copy(p, r.buf[r.pos:])
+	r.pos += n
+	return n, nil
+}
+
+func (r *copyRowsReader) appendRow(b []byte, strct reflect.Value) (_ []byte, err error) {
+	for i, f := range r.fields {
+		if i > 0 {
+			b = append(b, '\t')
+		}
+		if f.NullZero && f.HasZeroValue(strct) {
+			b = append(b, `\N`...)
+			continue
+		}
+		b, err = r.appendValue(b, f, f.Value(strct))
+		if err != nil {
+			return nil, err
+		}
+	}
+	return append(b, '\n'), nil
+}
+
+func (r *copyRowsReader) appendValue(b []byte, f *schema.Field, v reflect.Value) ([]byte, error) {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return append(b, `\N`...), nil
+		}
+		v = v.Elem()
+	}
+
+	if valuer, ok := copyValuer(v); ok {
+		value, err := valuer.Value()
+		if err != nil {
+			return nil, err
+		}
+		return r.appendDriverValue(b, value), nil
+	}
+
+	switch v.Type() {
+	case timeType:
+		return r.appendTime(b, v.Interface().(time.Time)), nil
+	case bytesType:
+		return r.appendBytes(b, v.Bytes()), nil
+	}
+
+	if v.Kind() == reflect.Slice || v.Kind() == reflect.Array {
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return append(b, `\N`...), nil
+		}
+		r.tmp = appendCopyArray(r.tmp[:0], v)
+		return appendCopyText(b, r.tmp), nil
+	}
+
+	r.tmp = appendCopyScalar(r.tmp[:0], v)
+	return appendCopyText(b, r.tmp), nil
+}
+
+func copyValuer(v reflect.Value) (driver.Valuer, bool) {
+	if v.Type().Implements(driverValuerType) {
+		return v.Interface().(driver.Valuer), true
+	}
+	if v.CanAddr() && v.Addr().Type().Implements(driverValuerType) {
+		return v.Addr().Interface().(driver.Valuer), true
+	}
+	return nil, false
+}
+
+func (r *copyRowsReader) appendDriverValue(b []byte, value driver.Value) []byte {
+	switch value := value.(type) {
+	case nil:
+		return append(b, `\N`...)
+	case time.Time:
+		return r.appendTime(b, value)
+	case []byte:
+		return r.appendBytes(b, value)
+	default:
+		r.tmp = appendCopyScalar(r.tmp[:0], reflect.ValueOf(value))
+		return appendCopyText(b, r.tmp)
+	}
+}
+
+func (r *copyRowsReader) appendTime(b []byte, tm time.Time) []byte {
+	if tm.IsZero() {
+		return append(b, `\N`...)
+	}
+	if r.loadData {
+		return tm.UTC().AppendFormat(b, "2006-01-02 15:04:05.999999")
+	}
+	return tm.UTC().AppendFormat(b, "2006-01-02 15:04:05.999999-07:00")
+}
+
+func (r *copyRowsReader) appendBytes(b, bs []byte) []byte {
+	if bs == nil {
+		return append(b, `\N`...)
+	}
+	if r.loadData {
+		return appendCopyText(b, bs)
+	}
+	// The bytea hex format with the escaped backslash.
+	b = append(b, `\\x`...)
+	s := len(b)
+	b = append(b, make([]byte, hex.EncodedLen(len(bs)))...)
+	hex.Encode(b[s:], bs)
+	return b
+}
+
+func appendCopyScalar(b []byte, v reflect.Value) []byte {
+	switch v.Kind() {
+	case reflect.String:
+		return append(b, v.String()...)
+	case reflect.Bool:
+		// Both COPY and LOAD DATA into TINYINT(1) accept 1 and 0.
+		if v.Bool() {
+			return append(b, '1')
+		}
+		return append(b, '0')
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.AppendInt(b, v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.AppendUint(b, v.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		f := v.Float()
+		switch {
+		case math.IsInf(f, 1):
+			return append(b, "Infinity"...)
+		case math.IsInf(f, -1):
+			return append(b, "-Infinity"...)
+		}
+		return strconv.AppendFloat(b, f, 'g', -1, 64)
+	default:
+		panic(fmt.Errorf("bun: COPY does not support %s", v.Type()))
+	}
+}
+
+// appendCopyArray appends the Postgres array literal, for example, `{"a","b"}`.
+func appendCopyArray(b []byte, v reflect.Value) []byte {
+	b = append(b, '{')
+	for i := 0; i < v.Len(); i++ {
+		if i > 0 {
+			b = append(b, ',')
+		}
+		elem := v.Index(i)
+		if elem.Kind() != reflect.String {
+			b = appendCopyScalar(b, elem)
+			continue
+		}
+
+		b = append(b, '"')
+		for _, c := range []byte(elem.String()) {
+			if c == '"' || c == '\\' {
+				b = append(b, '\\')
+			}
+			b = append(b, c)
+		}
+		b = append(b, '"')
+	}
+	return append(b, '}')
+}
+
+// appendCopyText escapes the text for the COPY text format.
+func appendCopyText(b, text []byte) []byte {
+	for _, c := range text {
+		switch c {
+		case '\\':
+			b = append(b, `\\`...)
+		case '\t':
+			b = append(b, `\t`...)
+		case '\n':
+			b = append(b, `\n`...)
+		case '\r':
+			b = append(b, `\r`...)
+		case 0:
+			b = append(b, `\0`...)
+		default:
+			b = append(b, c)
+		}
+	}
+	return b
+}
@@ -3,6 +3,10 @@ package bun
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
 
 	"github.com/uptrace/bun/dialect/feature"
 	"github.com/uptrace/bun/internal"
@@ -12,8 +16,14 @@ import (
 type DeleteQuery struct {
 	whereBaseQuery
 	returningQuery
+
+	joins []joinQuery
+	order []schema.QueryWithArgs
+	limit int32
 }
 
+var _ Query = (*DeleteQuery)(nil)
+
 func NewDeleteQuery(db *DB) *DeleteQuery {
 	q := &DeleteQuery{
 		whereBaseQuery: whereBaseQuery{
@@ -46,6 +56,33 @@ func (q *DeleteQuery) With(name string, query schema.QueryAppender) *DeleteQuery
 	return q
 }
 
+// Comment adds a key-value pair to the sqlcommenter comment appended to the query,
+// for example, `/*action='list',route='%2Fbooks'*/`.
+func (q *DeleteQuery) Comment(key, value string) *DeleteQuery {
+	q.addComment(key, value)
+	return q
+}
+
+// NamedArg sets the ?name placeholder used in the WHERE clause,
+// for example, `Where("tenant_id = ?tenant_id")`. See SelectQuery.NamedArg.
+func (q *DeleteQuery) NamedArg(name string, value interface{}) *DeleteQuery {
+	q.addNamedArg(name, value)
+	return q
+}
+
+// Timeout sets the maximum duration of the query. See SelectQuery.Timeout.
+func (q *DeleteQuery) Timeout(d time.Duration) *DeleteQuery {
+	q.timeout = d
+	return q
+}
+
+// RequireIsolation makes the query fail unless it runs in a transaction
+// with at least the given isolation level, for example, sql.LevelSerializable.
+func (q *DeleteQuery) RequireIsolation(level sql.IsolationLevel) *DeleteQuery {
+	q.isolation = level
+	return q
+}
+
 func (q *DeleteQuery) Table(tables ...string) *DeleteQuery {
 	for _, table := range tables {
 		q.addTable(schema.UnsafeIdent(table))
@@ -63,6 +100,84 @@ func (q *DeleteQuery) ModelTableExpr(query string, args ...interface{}) *DeleteQ
 	return q
 }
 
+// TableSchema qualifies the model table name with the schema.
+func (q *DeleteQuery) TableSchema(schema string) *DeleteQuery {
+	q.tableSchema = schema
+	return q
+}
+
+//------------------------------------------------------------------------------
+
+// Using adds the tables to the USING clause, for example,
+// `DELETE FROM "books" AS "book" USING "authors" AS "author" WHERE ...`.
+func (q *DeleteQuery) Using(tables ...string) *DeleteQuery {
+	for _, table := range tables {
+		q.addTable(schema.UnsafeIdent(table))
+	}
+	return q
+}
+
+func (q *DeleteQuery) UsingExpr(query string, args ...interface{}) *DeleteQuery {
+	q.addTable(schema.SafeQuery(query, args))
+	return q
+}
+
+//------------------------------------------------------------------------------
+
+// Join adds the join to the query, for example, `Join("JOIN authors AS a")`.
+// MySQL uses the multi-table `DELETE alias FROM ... JOIN` syntax and Postgres
+// uses `DELETE ... USING` with the JoinOn conditions in WHERE. Outer joins and
+// joins together with Order or Limit are emulated on Postgres with
+// the `ctid IN (SELECT ... FOR UPDATE)` subquery.
+func (q *DeleteQuery) Join(join string, args ...interface{}) *DeleteQuery {
+	q.joins = append(q.joins, joinQuery{
+		join: schema.SafeQuery(join, args),
+	})
+	return q
+}
+
+func (q *DeleteQuery) JoinOn(cond string, args ...interface{}) *DeleteQuery {
+	return q.joinOn(cond, args, " AND ")
+}
+
+func (q *DeleteQuery) JoinOnOr(cond string, args ...interface{}) *DeleteQuery {
+	return q.joinOn(cond, args, " OR ")
+}
+
+func (q *DeleteQuery) joinOn(cond string, args []interface{}, sep string) *DeleteQuery {
+	if len(q.joins) == 0 {
+		q.setErr(errors.New("bun: query has no joins"))
+		return q
+	}
+	j := &q.joins[len(q.joins)-1]
+	j.on = append(j.on, schema.SafeQueryWithSep(cond, args, sep))
+	return q
+}
+
+//------------------------------------------------------------------------------
+
+// Order orders the deleted rows, which is only useful together with Limit.
+func (q *DeleteQuery) Order(orders ...string) *DeleteQuery {
+	for _, order := range orders {
+		if order != "" {
+			q.order = append(q.order, parseOrder(order))
+		}
+	}
+	return q
+}
+
+func (q *DeleteQuery) OrderExpr(query string, args ...interface{}) *DeleteQuery {
+	q.order = append(q.order, schema.SafeQuery(query, args))
+	return q
+}
+
+// Limit limits the number of deleted rows using `DELETE ... ORDER BY ... LIMIT n` on MySQL
+// and `WHERE ctid IN (SELECT ctid ... LIMIT n)` on Postgres.
+func (q *DeleteQuery) Limit(n int) *DeleteQuery {
+	q.limit = int32(n)
+	return q
+}
+
 //------------------------------------------------------------------------------
 
 func (q *DeleteQuery) WherePK() *DeleteQuery {
@@ -80,6 +195,39 @@ func (q *DeleteQuery) WhereOr(query string, args ...interface{}) *DeleteQuery {
 	return q
 }
 
+// WhereColumn adds a condition comparing two columns, for example,
+// `WhereColumn("a.x", "=", "b.y")` produces `"a"."x" = "b"."y"`.
+func (q *DeleteQuery) WhereColumn(left, op, right string) *DeleteQuery {
+	q.addWhereColumn(left, op, right)
+	return q
+}
+
+// WhereIn adds a `column IN (...)` condition. The values can be a slice or a subquery,
+// for example, `WhereIn("id", db.NewSelect().Column("id").Table("staging"))`.
+func (q *DeleteQuery) WhereIn(column string, values interface{}) *DeleteQuery {
+	q.addWhereIn(column, values)
+	return q
+}
+
+// WhereExists adds an `EXISTS (subquery)` condition.
+func (q *DeleteQuery) WhereExists(subq *SelectQuery) *DeleteQuery {
+	q.addWhereExists("EXISTS", subq)
+	return q
+}
+
+// WhereNotExists adds a `NOT EXISTS (subquery)` condition.
+func (q *DeleteQuery) WhereNotExists(subq *SelectQuery) *DeleteQuery {
+	q.addWhereExists("NOT EXISTS", subq)
+	return q
+}
+
+// WhereIfNotZero adds a `column = value` condition unless the value is zero,
+// for example, an empty string. Use Where to filter by a zero value.
+func (q *DeleteQuery) WhereIfNotZero(column string, value interface{}) *DeleteQuery {
+	q.addWhereIfNotZero(column, value)
+	return q
+}
+
 func (q *DeleteQuery) WhereGroup(sep string, fn func(*DeleteQuery) *DeleteQuery) *DeleteQuery {
 	saved := q.where
 	q.where = nil
@@ -114,6 +262,7 @@ func (q *DeleteQuery) ForceDelete() *DeleteQuery {
 // Returning adds a RETURNING clause to the query.
 //
 // To suppress the auto-generated RETURNING clause, use `Returning("NULL")`.
+// `Returning("NOTHING")` also appends `RETURNING NOTHING` on CockroachDB.
 func (q *DeleteQuery) Returning(query string, args ...interface{}) *DeleteQuery {
 	q.addReturning(schema.SafeQuery(query, args))
 	return q
@@ -126,12 +275,21 @@ func (q *DeleteQuery) hasReturning() bool {
 	return q.returningQuery.hasReturning()
 }
 
+func (q *DeleteQuery) Operation() string {
+	return "DELETE"
+}
+
+func (q *DeleteQuery) String() string {
+	return q.queryString(q)
+}
+
 //------------------------------------------------------------------------------
 
 func (q *DeleteQuery) AppendQuery(fmter schema.Formatter, b []byte) (_ []byte, err error) {
 	if q.err != nil {
 		return nil, q.err
 	}
+	fmter = formatterWithModel(q.withNamedArgs(fmter), q)
 
 	if q.isSoftDelete() {
 		if err := q.tableModel.updateSoftDeleteField(); err != nil {
@@ -146,15 +304,32 @@ func (q *DeleteQuery) AppendQuery(fmter schema.Formatter, b []byte) (_ []byte, e
 		return upd.AppendQuery(fmter, b)
 	}
 
-	q = q.WhereAllWithDeleted()
-	withAlias := q.db.features.Has(feature.DeleteTableAlias)
+	// Force deletes don't filter soft deleted rows. Models without the soft delete
+	// field are skipped, because WhereAllWithDeleted would record an error and
+	// the query could not be formatted again.
+	if q.table != nil && q.table.SoftDeleteField != nil {
+		q.whereAllWithDeleted()
+	}
+
+	useCTID, err := q.useCTID(fmter)
+	if err != nil {
+		return nil, err
+	}
+	useUsing := len(q.joins) > 0 && !useCTID && fmter.HasFeature(feature.DeleteUsing)
+
+	withAlias := q.db.features.Has(feature.DeleteTableAlias) || len(q.joins) > 0
 
 	b, err = q.appendWith(fmter, b)
 	if err != nil {
 		return nil, err
 	}
 
-	b = append(b, "DELETE FROM "...)
+	b = fmter.AppendKeywords(b, "DELETE ")
+	if len(q.joins) > 0 && !useCTID && !useUsing {
+		b = append(b, q.tableAlias()...)
+		b = append(b, ' ')
+	}
+	b = fmter.AppendKeywords(b, "FROM ")
 
 	if withAlias {
 		b, err = q.appendFirstTableWithAlias(fmter, b)
@@ -165,26 +340,174 @@ func (q *DeleteQuery) AppendQuery(fmter schema.Formatter, b []byte) (_ []byte, e
 		return nil, err
 	}
 
+	if useCTID {
+		b, err = q.appendCTIDWhere(fmter, b)
+		if err != nil {
+			return nil, err
+		}
+	} else if useUsing {
+		b, err = q.appendUsingJoins(fmter, b)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		if q.hasMultiTables() {
+			if len(q.joins) > 0 {
+				b = append(b, ", "...)
+			} else {
+				b = fmter.AppendKeywords(b, " USING ")
+			}
+			b, err = q.appendOtherTables(fmter, b)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		for _, j := range q.joins {
+			b, err = j.AppendQuery(fmter, b)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		b, err = q.mustAppendWhere(fmter, b, withAlias)
+		if err != nil {
+			return nil, err
+		}
+
+		b, err = q.appendOrderLimit(fmter, b)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(q.returning) > 0 {
+		b, err = q.appendReturning(fmter, b)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return b, nil
+}
+
+// useCTID reports whether the joins, the order, and the limit are emulated
+// with the ctid subquery, because the dialect does not support them natively.
+func (q *DeleteQuery) useCTID(fmter schema.Formatter) (bool, error) {
+	hasOrderLimit := len(q.order) > 0 || q.limit > 0
+	if len(q.joins) == 0 && !hasOrderLimit {
+		return false, nil
+	}
+
+	switch {
+	case fmter.IsNop():
+		return false, nil
+	case !hasOrderLimit && fmter.HasFeature(feature.DeleteUsing) && q.canUseJoins():
+		return false, nil
+	case fmter.HasFeature(feature.CTID):
+		if q.table == nil {
+			return false, errNilModel
+		}
+		return true, nil
+	case len(q.joins) > 0 && hasOrderLimit:
+		return false, errors.New("bun: DELETE with JOIN does not support ORDER BY and LIMIT")
+	case len(q.joins) > 0 && fmter.HasFeature(feature.UpdateMultiTable):
+		return false, nil
+	case len(q.joins) == 0 && fmter.HasFeature(feature.DeleteOrderLimit):
+		return false, nil
+	}
+
+	if len(q.joins) > 0 {
+		return false, fmt.Errorf("bun: DELETE with JOIN is not supported by %s", fmter.Dialect().Name())
+	}
+	return false, fmt.Errorf("bun: DELETE with ORDER BY and LIMIT is not supported by %s",
+		fmter.Dialect().Name())
+}
+
+// canUseJoins reports whether the joins can follow the other tables in USING
+// or be rewritten as tables in USING.
+func (q *DeleteQuery) canUseJoins() bool {
+	return q.hasMultiTables() || joinTables(q.joins).ok()
+}
+
+// appendUsingJoins appends `USING tables WHERE ...`. The joins follow the other tables
+// if there are any, otherwise the joined tables are listed in USING and the JoinOn
+// conditions are added to WHERE.
+func (q *DeleteQuery) appendUsingJoins(fmter schema.Formatter, b []byte) (_ []byte, err error) {
+	b = fmter.AppendKeywords(b, " USING ")
+
 	if q.hasMultiTables() {
-		b = append(b, " USING "...)
 		b, err = q.appendOtherTables(fmter, b)
 		if err != nil {
 			return nil, err
 		}
+		for _, j := range q.joins {
+			b, err = j.AppendQuery(fmter, b)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return q.mustAppendWhere(fmter, b, true)
 	}
 
-	b, err = q.mustAppendWhere(fmter, b, withAlias)
+	joins := joinTables(q.joins)
+	b, err = joins.appendTables(fmter, b)
 	if err != nil {
 		return nil, err
 	}
 
-	if len(q.returning) > 0 {
-		b, err = q.appendReturning(fmter, b)
-		if err != nil {
-			return nil, err
+	where := joins.where(q.whereBaseQuery)
+	return where.mustAppendWhere(fmter, b, true)
+}
+
+// appendCTIDWhere appends `WHERE alias.ctid IN (SELECT alias.ctid ...)` that selects
+// the rows to delete using the tables, the joins, the order, and the limit of the query.
+func (q *DeleteQuery) appendCTIDWhere(fmter schema.Formatter, b []byte) (_ []byte, err error) {
+	if len(q.where) == 0 && !q.flags.Has(wherePKFlag) {
+		return nil, errors.New("bun: Update and Delete queries require at least one Where")
+	}
+
+	sel := &SelectQuery{
+		whereBaseQuery: q.whereBaseQuery,
+		joins:          q.joins,
+		order:          q.order,
+		limit:          q.limit,
+	}
+	sel.with = nil
+	// Lock the rows, so concurrent updates can't change their ctid before they are deleted.
+	sel.selFor = schema.SafeQuery(string(fmter.AppendKeywords(nil, "UPDATE OF "))+"?TableAlias", nil)
+	sel.columns = []schema.QueryWithArgs{schema.SafeQuery("?TableAlias.ctid", nil)}
+
+	b = fmter.AppendKeywords(b, " WHERE ")
+	b = append(b, q.tableAlias()...)
+	b = fmter.AppendKeywords(b, ".ctid IN (")
+	b, err = sel.AppendQuery(fmter, b)
+	if err != nil {
+		return nil, err
+	}
+	b = append(b, ')')
+	return b, nil
+}
+
+func (q *DeleteQuery) appendOrderLimit(fmter schema.Formatter, b []byte) (_ []byte, err error) {
+	if len(q.order) > 0 {
+		b = fmter.AppendKeywords(b, " ORDER BY ")
+		for i, order := range q.order {
+			if i > 0 {
+				b = append(b, ", "...)
+			}
+			b, err = order.AppendQuery(fmter, b)
+			if err != nil {
+				return nil, err
+			}
 		}
 	}
 
+	if q.limit > 0 {
+		b = fmter.AppendKeywords(b, " LIMIT ")
+		b = strconv.AppendInt(b, int64(q.limit), 10)
+	}
+
 	return b, nil
 }
 
@@ -194,11 +517,27 @@ func (q *DeleteQuery) isSoftDelete() bool {
 
 //------------------------------------------------------------------------------
 
+// Prepare creates a prepared statement replacing the query args with placeholders.
+// The args must be passed in the same order when executing the statement.
+func (q *DeleteQuery) Prepare(ctx context.Context) (Stmt, error) {
+	return q.prepare(ctx, q)
+}
+
 func (q *DeleteQuery) Exec(ctx context.Context, dest ...interface{}) (sql.Result, error) {
 	if q.table != nil {
 		if err := q.beforeDeleteHook(ctx); err != nil {
 			return nil, err
 		}
+		if err := q.beforeAppendModelHook(ctx, q); err != nil {
+			return nil, err
+		}
+		if err := q.beforeAppendQueryHook(ctx, q); err != nil {
+			return nil, err
+		}
+	}
+
+	if q.returningRequested() && !q.db.features.Has(feature.Returning) {
+		return nil, fmt.Errorf("bun: RETURNING is not supported by %s", q.db.dialect.Name())
 	}
 
 	queryBytes, err := q.AppendQuery(q.db.fmter, q.db.makeQueryBytes())
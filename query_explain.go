@@ -0,0 +1,257 @@
+package bun
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/uptrace/bun/dialect"
+	"github.com/uptrace/bun/internal"
+)
+
+type ExplainOption func(*explainOptions)
+
+type explainOptions struct {
+	analyze bool
+	format  string
+}
+
+// ExplainAnalyze executes the query and adds the actual timings and row counts to the plan.
+// It is supported by PostgreSQL and MySQL 8.
+func ExplainAnalyze() ExplainOption {
+	return func(opt *explainOptions) {
+		opt.analyze = true
+	}
+}
+
+// ExplainFormat sets the plan format, for example, `JSON`.
+// PostgreSQL supports TEXT, JSON, XML, and YAML.
+// MySQL supports TRADITIONAL, JSON, and TREE.
+func ExplainFormat(format string) ExplainOption {
+	return func(opt *explainOptions) {
+		opt.format = strings.ToUpper(format)
+	}
+}
+
+var explainFormats = map[dialect.Name][]string{
+	dialect.PG:     {"TEXT", "JSON", "XML", "YAML"},
+	dialect.MySQL5: {"TRADITIONAL", "JSON"},
+	dialect.MySQL8: {"TRADITIONAL", "JSON", "TREE"},
+}
+
+func checkExplainFormat(name dialect.Name, format string) error {
+	if format == "" {
+		return nil
+	}
+	for _, f := range explainFormats[name] {
+		if f == format {
+			return nil
+		}
+	}
+	return fmt.Errorf("bun: EXPLAIN format %q is not supported by %s", format, name)
+}
+
+// Explain returns the plan of the query, one row per line. The columns of multi-column
+// rows, for example, from MySQL or SQLite, are separated by tabs.
+func (q *SelectQuery) Explain(ctx context.Context, opts ...ExplainOption) ([]string, error) {
+	if q.err != nil {
+		return nil, q.err
+	}
+	if err := q.beforeAppendQueryHook(ctx, q); err != nil {
+		return nil, err
+	}
+
+	queryBytes, err := q.AppendQuery(q.db.fmter, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	query, err := explainQuery(q.db.dialect.Name(), internal.String(queryBytes), opts)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := q.withTimeout(ctx)
+	defer cancel()
+
+	// Don't pass the query appender so hooks don't treat EXPLAIN as a SELECT.
+	ctx, event := q.db.beforeQuery(ctx, nil, query, nil)
+	plan, err := scanExplain(ctx, q.conn, query)
+	q.db.afterQuery(ctx, event, nil, err)
+
+	return plan, err
+}
+
+func explainQuery(name dialect.Name, query string, opts []ExplainOption) (string, error) {
+	var opt explainOptions
+	for _, fn := range opts {
+		fn(&opt)
+	}
+	if err := checkExplainFormat(name, opt.format); err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	switch name {
+	case dialect.PG:
+		b.WriteString("EXPLAIN ")
+		if opt.analyze || opt.format != "" {
+			var params []string
+			if opt.analyze {
+				params = append(params, "ANALYZE")
+			}
+			if opt.format != "" {
+				params = append(params, "FORMAT "+opt.format)
+			}
+			b.WriteString("(" + strings.Join(params, ", ") + ") ")
+		}
+	case dialect.MySQL5, dialect.MySQL8:
+		if opt.analyze && name != dialect.MySQL8 {
+			return "", fmt.Errorf("bun: EXPLAIN ANALYZE is not supported by %s", name)
+		}
+		b.WriteString("EXPLAIN ")
+		if opt.analyze {
+			b.WriteString("ANALYZE ")
+		}
+		if opt.format != "" {
+			b.WriteString("FORMAT=" + opt.format + " ")
+		}
+	case dialect.SQLite:
+		if opt.analyze || opt.format != "" {
+			return "", fmt.Errorf("bun: EXPLAIN options are not supported by %s", name)
+		}
+		b.WriteString("EXPLAIN QUERY PLAN ")
+	default:
+		return "", fmt.Errorf("bun: EXPLAIN is not supported by %s", name)
+	}
+	b.WriteString(query)
+
+	return b.String(), nil
+}
+
+func scanExplain(ctx context.Context, conn IConn, query string) ([]string, error) {
+	rows, err := conn.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]sql.NullString, len(columns))
+	dest := make([]interface{}, len(columns))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+
+	var plan []string
+	for rows.Next() {
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+
+		ss := make([]string, len(values))
+		for i, v := range values {
+			ss[i] = v.String
+		}
+		plan = append(plan, strings.Join(ss, "\t"))
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+//------------------------------------------------------------------------------
+
+// ExplainAnalyzeHook logs the plans of the SELECT queries slower than the threshold.
+// The plans are obtained with EXPLAIN ANALYZE, which runs the query one more time,
+// so use the hook for debugging only. SQLite does not support ANALYZE,
+// so the hook logs the query plan instead.
+//
+// The hook skips the queries executed in transactions, the locking queries,
+// for example, `SELECT ... FOR UPDATE`, and the queries with data-modifying
+// WITH queries, because running them again has side effects.
+type ExplainAnalyzeHook struct {
+	// Threshold is the minimal query duration. Zero logs all queries.
+	Threshold time.Duration
+	// Options are added to ExplainAnalyze, for example, ExplainFormat("JSON").
+	Options []ExplainOption
+	// Logger defaults to the bun logger.
+	Logger internal.Logging
+}
+
+var _ QueryHook = (*ExplainAnalyzeHook)(nil)
+
+func (h *ExplainAnalyzeHook) BeforeQuery(ctx context.Context, event *QueryEvent) context.Context {
+	return ctx
+}
+
+func (h *ExplainAnalyzeHook) AfterQuery(ctx context.Context, event *QueryEvent) {
+	if event.Err != nil {
+		return
+	}
+	q, ok := event.QueryAppender.(*SelectQuery)
+	if !ok || !isExplainable(q) {
+		return
+	}
+
+	dur := time.Since(event.StartTime)
+	if dur < h.Threshold {
+		return
+	}
+
+	name := event.DB.Dialect().Name()
+
+	var opts []ExplainOption
+	if name != dialect.SQLite {
+		opts = append(opts, ExplainAnalyze())
+		opts = append(opts, h.Options...)
+	}
+
+	logger := internal.Logger
+	if h.Logger != nil {
+		logger = h.Logger
+	}
+
+	query, err := explainQuery(name, event.Query, opts)
+	if err != nil {
+		logger.Printf("can't explain %s: %s", event.Query, err)
+		return
+	}
+
+	// Use the underlying *sql.DB to not call the hooks again.
+	plan, err := scanExplain(ctx, event.DB.DB, query)
+	if err != nil {
+		logger.Printf("can't explain %s: %s", event.Query, err)
+		return
+	}
+
+	logger.Printf("%s took %s:\n%s", event.Query, dur, strings.Join(plan, "\n"))
+}
+
+// isExplainable reports whether the query can be run again by EXPLAIN ANALYZE
+// without side effects.
+func isExplainable(q *SelectQuery) bool {
+	if q.inTx || !q.selFor.IsZero() {
+		return false
+	}
+	for _, with := range q.with {
+		switch with := with.query.(type) {
+		case *SelectQuery:
+			if !isExplainable(with) {
+				return false
+			}
+		case *ValuesQuery:
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -22,6 +22,8 @@ type CreateIndexQuery struct {
 	include []schema.QueryWithArgs
 }
 
+var _ Query = (*CreateIndexQuery)(nil)
+
 func NewCreateIndexQuery(db *DB) *CreateIndexQuery {
 	q := &CreateIndexQuery{
 		whereBaseQuery: whereBaseQuery{
@@ -90,6 +92,12 @@ func (q *CreateIndexQuery) ModelTableExpr(query string, args ...interface{}) *Cr
 	return q
 }
 
+// TableSchema qualifies the model table name with the schema.
+func (q *CreateIndexQuery) TableSchema(schema string) *CreateIndexQuery {
+	q.tableSchema = schema
+	return q
+}
+
 func (q *CreateIndexQuery) Using(query string, args ...interface{}) *CreateIndexQuery {
 	q.using = schema.SafeQuery(query, args)
 	return q
@@ -140,6 +148,14 @@ func (q *CreateIndexQuery) WhereOr(query string, args ...interface{}) *CreateInd
 	return q
 }
 
+func (q *CreateIndexQuery) Operation() string {
+	return "CREATE INDEX"
+}
+
+func (q *CreateIndexQuery) String() string {
+	return q.queryString(q)
+}
+
 //------------------------------------------------------------------------------
 
 func (q *CreateIndexQuery) AppendQuery(fmter schema.Formatter, b []byte) (_ []byte, err error) {
@@ -147,25 +163,25 @@ func (q *CreateIndexQuery) AppendQuery(fmter schema.Formatter, b []byte) (_ []by
 		return nil, q.err
 	}
 
-	b = append(b, "CREATE "...)
+	b = fmter.AppendKeywords(b, "CREATE ")
 
 	if q.unique {
-		b = append(b, "UNIQUE "...)
+		b = fmter.AppendKeywords(b, "UNIQUE ")
 	}
 	if q.fulltext {
-		b = append(b, "FULLTEXT "...)
+		b = fmter.AppendKeywords(b, "FULLTEXT ")
 	}
 	if q.spatial {
-		b = append(b, "SPATIAL "...)
+		b = fmter.AppendKeywords(b, "SPATIAL ")
 	}
 
-	b = append(b, "INDEX "...)
+	b = fmter.AppendKeywords(b, "INDEX ")
 
 	if q.concurrently {
-		b = append(b, "CONCURRENTLY "...)
+		b = fmter.AppendKeywords(b, "CONCURRENTLY ")
 	}
 	if q.ifNotExists {
-		b = append(b, "IF NOT EXISTS "...)
+		b = fmter.AppendKeywords(b, "IF NOT EXISTS ")
 	}
 
 	b, err = q.index.AppendQuery(fmter, b)
@@ -173,14 +189,14 @@ func (q *CreateIndexQuery) AppendQuery(fmter schema.Formatter, b []byte) (_ []by
 		return nil, err
 	}
 
-	b = append(b, " ON "...)
+	b = fmter.AppendKeywords(b, " ON ")
 	b, err = q.appendFirstTable(fmter, b)
 	if err != nil {
 		return nil, err
 	}
 
 	if !q.using.IsZero() {
-		b = append(b, " USING "...)
+		b = fmter.AppendKeywords(b, " USING ")
 		b, err = q.using.AppendQuery(fmter, b)
 		if err != nil {
 			return nil, err
@@ -200,7 +216,7 @@ func (q *CreateIndexQuery) AppendQuery(fmter schema.Formatter, b []byte) (_ []by
 	b = append(b, ')')
 
 	if len(q.include) > 0 {
-		b = append(b, " INCLUDE ("...)
+		b = fmter.AppendKeywords(b, " INCLUDE (")
 		for i, col := range q.include {
 			if i > 0 {
 				b = append(b, ", "...)
@@ -18,6 +18,8 @@ type DropIndexQuery struct {
 	index schema.QueryWithArgs
 }
 
+var _ Query = (*DropIndexQuery)(nil)
+
 func NewDropIndexQuery(db *DB) *DropIndexQuery {
 	q := &DropIndexQuery{
 		baseQuery: baseQuery{
@@ -60,6 +62,14 @@ func (q *DropIndexQuery) Index(query string, args ...interface{}) *DropIndexQuer
 	return q
 }
 
+func (q *DropIndexQuery) Operation() string {
+	return "DROP INDEX"
+}
+
+func (q *DropIndexQuery) String() string {
+	return q.queryString(q)
+}
+
 //------------------------------------------------------------------------------
 
 func (q *DropIndexQuery) AppendQuery(fmter schema.Formatter, b []byte) (_ []byte, err error) {
@@ -67,13 +77,13 @@ func (q *DropIndexQuery) AppendQuery(fmter schema.Formatter, b []byte) (_ []byte
 		return nil, q.err
 	}
 
-	b = append(b, "DROP INDEX "...)
+	b = fmter.AppendKeywords(b, "DROP INDEX ")
 
 	if q.concurrently {
-		b = append(b, "CONCURRENTLY "...)
+		b = fmter.AppendKeywords(b, "CONCURRENTLY ")
 	}
 	if q.ifExists {
-		b = append(b, "IF EXISTS "...)
+		b = fmter.AppendKeywords(b, "IF EXISTS ")
 	}
 
 	b, err = q.index.AppendQuery(fmter, b)
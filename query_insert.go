@@ -3,8 +3,10 @@ package bun
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"reflect"
+	"time"
 
 	"github.com/uptrace/bun/dialect/feature"
 	"github.com/uptrace/bun/internal"
@@ -16,13 +18,19 @@ type InsertQuery struct {
 	returningQuery
 	customValueQuery
 
-	onConflict schema.QueryWithArgs
+	onConflict  schema.QueryWithArgs
+	setExcluded bool
 	setQuery
 
-	ignore  bool
-	replace bool
+	ignore       bool
+	replace      bool
+	useCopy      bool
+	copyOrInsert bool
+	batchSize    int
 }
 
+var _ Query = (*InsertQuery)(nil)
+
 func NewInsertQuery(db *DB) *InsertQuery {
 	q := &InsertQuery{
 		whereBaseQuery: whereBaseQuery{
@@ -55,6 +63,33 @@ func (q *InsertQuery) With(name string, query schema.QueryAppender) *InsertQuery
 	return q
 }
 
+// Comment adds a key-value pair to the sqlcommenter comment appended to the query,
+// for example, `/*action='list',route='%2Fbooks'*/`.
+func (q *InsertQuery) Comment(key, value string) *InsertQuery {
+	q.addComment(key, value)
+	return q
+}
+
+// NamedArg sets the ?name placeholder used in the inserted values and the ON CONFLICT clause,
+// for example, `Value("created_by", "?user_id")`. See SelectQuery.NamedArg.
+func (q *InsertQuery) NamedArg(name string, value interface{}) *InsertQuery {
+	q.addNamedArg(name, value)
+	return q
+}
+
+// Timeout sets the maximum duration of the query. See SelectQuery.Timeout.
+func (q *InsertQuery) Timeout(d time.Duration) *InsertQuery {
+	q.timeout = d
+	return q
+}
+
+// RequireIsolation makes the query fail unless it runs in a transaction
+// with at least the given isolation level, for example, sql.LevelSerializable.
+func (q *InsertQuery) RequireIsolation(level sql.IsolationLevel) *InsertQuery {
+	q.isolation = level
+	return q
+}
+
 //------------------------------------------------------------------------------
 
 func (q *InsertQuery) Table(tables ...string) *InsertQuery {
@@ -74,6 +109,12 @@ func (q *InsertQuery) ModelTableExpr(query string, args ...interface{}) *InsertQ
 	return q
 }
 
+// TableSchema qualifies the model table name with the schema.
+func (q *InsertQuery) TableSchema(schema string) *InsertQuery {
+	q.tableSchema = schema
+	return q
+}
+
 //------------------------------------------------------------------------------
 
 func (q *InsertQuery) Column(columns ...string) *InsertQuery {
@@ -113,6 +154,7 @@ func (q *InsertQuery) WhereOr(query string, args ...interface{}) *InsertQuery {
 // Returning adds a RETURNING clause to the query.
 //
 // To suppress the auto-generated RETURNING clause, use `Returning("NULL")`.
+// `Returning("NOTHING")` also appends `RETURNING NOTHING` on CockroachDB.
 func (q *InsertQuery) Returning(query string, args ...interface{}) *InsertQuery {
 	q.addReturning(schema.SafeQuery(query, args))
 	return q
@@ -139,12 +181,21 @@ func (q *InsertQuery) Replace() *InsertQuery {
 	return q
 }
 
+func (q *InsertQuery) Operation() string {
+	return "INSERT"
+}
+
+func (q *InsertQuery) String() string {
+	return q.queryString(q)
+}
+
 //------------------------------------------------------------------------------
 
 func (q *InsertQuery) AppendQuery(fmter schema.Formatter, b []byte) (_ []byte, err error) {
 	if q.err != nil {
 		return nil, q.err
 	}
+	fmter = formatterWithModel(q.withNamedArgs(fmter), q)
 
 	b, err = q.appendWith(fmter, b)
 	if err != nil {
@@ -152,14 +203,14 @@ func (q *InsertQuery) AppendQuery(fmter schema.Formatter, b []byte) (_ []byte, e
 	}
 
 	if q.replace {
-		b = append(b, "REPLACE "...)
+		b = fmter.AppendKeywords(b, "REPLACE ")
 	} else {
-		b = append(b, "INSERT "...)
+		b = fmter.AppendKeywords(b, "INSERT ")
 		if q.ignore {
-			b = append(b, "IGNORE "...)
+			b = fmter.AppendKeywords(b, "IGNORE ")
 		}
 	}
-	b = append(b, "INTO "...)
+	b = fmter.AppendKeywords(b, "INTO ")
 
 	if q.db.features.Has(feature.InsertTableAlias) && !q.onConflict.IsZero() {
 		b, err = q.appendFirstTableWithAlias(fmter, b)
@@ -180,7 +231,7 @@ func (q *InsertQuery) AppendQuery(fmter schema.Formatter, b []byte) (_ []byte, e
 		return nil, err
 	}
 
-	if q.hasReturning() {
+	if q.hasReturning() || q.returningNothing() {
 		b, err = q.appendReturning(fmter, b)
 		if err != nil {
 			return nil, err
@@ -203,7 +254,7 @@ func (q *InsertQuery) appendColumnsValues(
 			b = append(b, ")"...)
 		}
 
-		b = append(b, " SELECT * FROM "...)
+		b = fmter.AppendKeywords(b, " SELECT * FROM ")
 		b, err = q.appendOtherTables(fmter, b)
 		if err != nil {
 			return nil, err
@@ -228,9 +279,13 @@ func (q *InsertQuery) appendColumnsValues(
 		return nil, err
 	}
 
+	if len(fields) == 0 && len(q.extraValues) == 0 {
+		return q.appendDefaultValues(fmter, b)
+	}
+
 	b = append(b, " ("...)
 	b = q.appendFields(fmter, b, fields)
-	b = append(b, ") VALUES ("...)
+	b = fmter.AppendKeywords(b, ") VALUES (")
 
 	switch model := q.tableModel.(type) {
 	case *structTableModel:
@@ -252,6 +307,32 @@ func (q *InsertQuery) appendColumnsValues(
 	return b, nil
 }
 
+// appendDefaultValues appends the values for a row that consists only of defaults,
+// i.e. `DEFAULT VALUES` or `() VALUES ()` on MySQL.
+func (q *InsertQuery) appendDefaultValues(fmter schema.Formatter, b []byte) ([]byte, error) {
+	numRows := 1
+	if model, ok := q.tableModel.(*sliceTableModel); ok {
+		numRows = model.sliceLen
+	}
+
+	if fmter.HasFeature(feature.InsertDefaultValues) {
+		if numRows > 1 {
+			return nil, fmt.Errorf("bun: %s does not support inserting multiple rows with DEFAULT VALUES",
+				fmter.Dialect().Name())
+		}
+		return fmter.AppendKeywords(b, " DEFAULT VALUES"), nil
+	}
+
+	b = fmter.AppendKeywords(b, " () VALUES ")
+	for i := 0; i < numRows; i++ {
+		if i > 0 {
+			b = append(b, ", "...)
+		}
+		b = append(b, "()"...)
+	}
+	return b, nil
+}
+
 func (q *InsertQuery) appendStructValues(
 	fmter schema.Formatter, b []byte, fields []*schema.Field, strct reflect.Value,
 ) (_ []byte, err error) {
@@ -273,14 +354,14 @@ func (q *InsertQuery) appendStructValues(
 
 		switch {
 		case isTemplate:
-			b = append(b, '?')
+			b = f.AppendTemplateValue(fmter, b, strct)
 		case f.NullZero && f.HasZeroValue(strct):
 			if q.db.features.Has(feature.DefaultPlaceholder) {
-				b = append(b, "DEFAULT"...)
+				b = fmter.AppendKeywords(b, "DEFAULT")
 			} else if f.SQLDefault != "" {
 				b = append(b, f.SQLDefault...)
 			} else {
-				b = append(b, "NULL"...)
+				b = fmter.AppendKeywords(b, "NULL")
 			}
 			q.addReturningField(f)
 		default:
@@ -306,7 +387,11 @@ func (q *InsertQuery) appendSliceValues(
 	fmter schema.Formatter, b []byte, fields []*schema.Field, slice reflect.Value,
 ) (_ []byte, err error) {
 	if fmter.IsNop() {
-		return q.appendStructValues(fmter, b, fields, reflect.Value{})
+		var strct reflect.Value
+		if slice.Len() > 0 {
+			strct = indirect(slice.Index(0))
+		}
+		return q.appendStructValues(fmter, b, fields, strct)
 	}
 
 	sliceLen := slice.Len()
@@ -390,22 +475,47 @@ func (q *InsertQuery) Set(query string, args ...interface{}) *InsertQuery {
 	return q
 }
 
+// SetExcludedColumns updates the non-primary key columns with the inserted values on conflict,
+// for example, `On("CONFLICT (id) DO UPDATE").SetExcludedColumns()` appends
+// `SET "title" = EXCLUDED."title"` or `title = VALUES(title)` on MySQL.
+// Only the columns selected with Column are updated if any.
+func (q *InsertQuery) SetExcludedColumns() *InsertQuery {
+	if q.table == nil {
+		q.setErr(errors.New("bun: SetExcludedColumns requires a model"))
+		return q
+	}
+	q.setExcluded = true
+	return q
+}
+
 func (q *InsertQuery) appendOn(fmter schema.Formatter, b []byte) (_ []byte, err error) {
 	if q.onConflict.IsZero() {
 		return b, nil
 	}
 
-	b = append(b, " ON "...)
+	b = fmter.AppendKeywords(b, " ON ")
 	b, err = q.onConflict.AppendQuery(fmter, b)
 	if err != nil {
 		return nil, err
 	}
 
-	if len(q.set) > 0 {
+	if len(q.set) > 0 || q.setExcluded {
 		if fmter.HasFeature(feature.OnDuplicateKey) {
 			b = append(b, ' ')
 		} else {
-			b = append(b, " SET "...)
+			b = fmter.AppendKeywords(b, " SET ")
+		}
+
+		if q.setExcluded {
+			fields, err := q.getDataFields()
+			if err != nil {
+				return nil, err
+			}
+			b = q.appendSetExcluded(fmter, b, fields)
+
+			if len(q.set) > 0 {
+				b = append(b, ", "...)
+			}
 		}
 
 		b, err = q.appendSet(fmter, b)
@@ -422,7 +532,8 @@ func (q *InsertQuery) appendOn(fmter schema.Formatter, b []byte) (_ []byte, err
 			fields = q.tableModel.Table().DataFields
 		}
 
-		b = q.appendSetExcluded(b, fields)
+		b = fmter.AppendKeywords(b, " SET ")
+		b = q.appendSetExcluded(fmter, b, fields)
 	}
 
 	b, err = q.appendWhere(fmter, b, true)
@@ -433,26 +544,101 @@ func (q *InsertQuery) appendOn(fmter schema.Formatter, b []byte) (_ []byte, err
 	return b, nil
 }
 
-func (q *InsertQuery) appendSetExcluded(b []byte, fields []*schema.Field) []byte {
-	b = append(b, " SET "...)
+// appendSetExcluded appends `column = EXCLUDED.column` for each field,
+// or `column = VALUES(column)` on MySQL.
+func (q *InsertQuery) appendSetExcluded(
+	fmter schema.Formatter, b []byte, fields []*schema.Field,
+) []byte {
 	for i, f := range fields {
 		if i > 0 {
 			b = append(b, ", "...)
 		}
 		b = append(b, f.SQLName...)
-		b = append(b, " = EXCLUDED."...)
-		b = append(b, f.SQLName...)
+		if fmter.HasFeature(feature.OnDuplicateKey) {
+			b = fmter.AppendKeywords(b, " = VALUES(")
+			b = append(b, f.SQLName...)
+			b = append(b, ')')
+		} else {
+			b = fmter.AppendKeywords(b, " = EXCLUDED.")
+			b = append(b, f.SQLName...)
+		}
 	}
 	return b
 }
 
 //------------------------------------------------------------------------------
 
+// Prepare creates a prepared statement replacing the query args with placeholders.
+// The args must be passed in the same order when executing the statement.
+func (q *InsertQuery) Prepare(ctx context.Context) (Stmt, error) {
+	return q.prepare(ctx, q)
+}
+
+// Copy inserts the slice model using `COPY ... FROM STDIN` when the driver supports it
+// or `LOAD DATA LOCAL INFILE` on MySQL with mysqldialect.WithLoadData,
+// which is much faster for large slices. Exec returns an error when the query can't use COPY,
+// for example, queries with RETURNING, ON CONFLICT, or custom values, Postgres queries
+// in transactions, and models with JSON columns.
+func (q *InsertQuery) Copy() *InsertQuery {
+	q.useCopy = true
+	return q
+}
+
+// CopyOrInsert is like Copy, but uses INSERT when the query can't use COPY.
+func (q *InsertQuery) CopyOrInsert() *InsertQuery {
+	q.useCopy = true
+	q.copyOrInsert = true
+	return q
+}
+
+// BatchSize splits the slice model into multiple INSERT queries with up to n rows each
+// to stay within the placeholder and packet limits, for example, on MySQL and SQLite.
+// The batches run in a transaction, or in the query transaction if there is one,
+// so either all rows are inserted or none.
+func (q *InsertQuery) BatchSize(n int) *InsertQuery {
+	q.batchSize = n
+	return q
+}
+
 func (q *InsertQuery) Exec(ctx context.Context, dest ...interface{}) (sql.Result, error) {
+	var useCopy bool
+	if q.useCopy {
+		err := q.checkCopy(dest)
+		if err != nil && !q.copyOrInsert {
+			return nil, err
+		}
+		useCopy = err == nil
+	}
+
+	if q.batchSize > 0 && len(dest) == 0 && !useCopy {
+		if model, ok := q.tableModel.(*sliceTableModel); ok && model.sliceLen > q.batchSize {
+			return q.execBatches(ctx, model)
+		}
+	}
+
 	if q.table != nil {
 		if err := q.beforeInsertHook(ctx); err != nil {
 			return nil, err
 		}
+		if err := q.beforeAppendModelHook(ctx, q); err != nil {
+			return nil, err
+		}
+		if err := q.beforeAppendQueryHook(ctx, q); err != nil {
+			return nil, err
+		}
+	}
+
+	if useCopy {
+		res, err := q.copyFrom(ctx)
+		if err != errCopyNotSupported || !q.copyOrInsert {
+			if err != nil {
+				return nil, err
+			}
+			if err := q.afterInsertHook(ctx); err != nil {
+				return nil, err
+			}
+			return res, nil
+		}
 	}
 
 	queryBytes, err := q.AppendQuery(q.db.fmter, q.db.makeQueryBytes())
@@ -482,6 +668,12 @@ func (q *InsertQuery) Exec(ctx context.Context, dest ...interface{}) (sql.Result
 		if err := q.tryLastInsertID(res, dest); err != nil {
 			return nil, err
 		}
+
+		if q.returningRequested() && !q.db.features.Has(feature.Returning) {
+			if err := q.selectReturning(ctx, dest); err != nil {
+				return nil, err
+			}
+		}
 	}
 
 	if q.table != nil {
@@ -493,6 +685,94 @@ func (q *InsertQuery) Exec(ctx context.Context, dest ...interface{}) (sql.Result
 	return res, nil
 }
 
+// execBatches inserts the slice in chunks of batchSize rows in a transaction.
+// The chunks share the backing array with the slice, so the returned columns
+// are scanned in place.
+func (q *InsertQuery) execBatches(ctx context.Context, model *sliceTableModel) (sql.Result, error) {
+	if q.inTx {
+		return q.execBatchesIn(ctx, q.conn, model)
+	}
+
+	beginner, ok := q.conn.(interface {
+		BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+	})
+	if !ok {
+		return nil, fmt.Errorf("bun: %T does not support transactions", q.conn)
+	}
+
+	tx, err := beginner.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := q.execBatchesIn(ctx, tx, model)
+	if err != nil {
+		_ = tx.Rollback()
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (q *InsertQuery) execBatchesIn(
+	ctx context.Context, conn IConn, model *sliceTableModel,
+) (sql.Result, error) {
+	sliceType := model.slice.Type()
+	elemType := indirectType(sliceType.Elem())
+
+	var res batchResult
+	for i := 0; i < model.sliceLen; i += q.batchSize {
+		j := i + q.batchSize
+		if j > model.sliceLen {
+			j = model.sliceLen
+		}
+
+		chunk := reflect.New(sliceType).Elem()
+		chunk.Set(model.slice.Slice3(i, j, j))
+
+		batch := *q
+		batch.conn = conn
+		batch.inTx = true
+		batch.batchSize = 0
+		batch.returningFields = nil
+		batchModel := newSliceTableModel(q.db, chunk.Addr().Interface(), chunk, elemType)
+		batch.model = batchModel
+		batch.tableModel = batchModel
+
+		batchRes, err := batch.Exec(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		affected, err := batchRes.RowsAffected()
+		if err != nil {
+			return nil, err
+		}
+		res.rowsAffected += affected
+		res.last = batchRes
+	}
+	return res, nil
+}
+
+// batchResult sums the rows affected by the batches and reports
+// the LastInsertId of the last batch.
+type batchResult struct {
+	rowsAffected int64
+	last         sql.Result
+}
+
+var _ sql.Result = batchResult{}
+
+func (r batchResult) LastInsertId() (int64, error) {
+	return r.last.LastInsertId()
+}
+
+func (r batchResult) RowsAffected() (int64, error) {
+	return r.rowsAffected, nil
+}
+
 func (q *InsertQuery) beforeInsertHook(ctx context.Context) error {
 	if hook, ok := q.table.ZeroIface.(BeforeInsertHook); ok {
 		if err := hook.BeforeInsert(ctx, q); err != nil {
@@ -511,6 +791,85 @@ func (q *InsertQuery) afterInsertHook(ctx context.Context) error {
 	return nil
 }
 
+// selectReturning emulates the RETURNING clause on dialects that don't support it
+// by selecting the returned columns of the inserted models by primary keys with a single query.
+// It uses the same connection so the models are visible in the current transaction.
+func (q *InsertQuery) selectReturning(ctx context.Context, dest []interface{}) error {
+	if len(dest) > 0 {
+		return fmt.Errorf("bun: RETURNING is not supported by %s", q.db.dialect.Name())
+	}
+	if q.table == nil || len(q.table.PKs) == 0 {
+		return fmt.Errorf("bun: RETURNING emulation requires a model with primary keys")
+	}
+
+	var strcts []reflect.Value
+	switch model := q.tableModel.(type) {
+	case *structTableModel:
+		strcts = append(strcts, model.strct)
+	case *sliceTableModel:
+		sliceLen := model.slice.Len()
+		for i := 0; i < sliceLen; i++ {
+			strcts = append(strcts, indirect(model.slice.Index(i)))
+		}
+	default:
+		return fmt.Errorf("bun: RETURNING emulation does not support %T", q.tableModel.Value())
+	}
+
+	key := make([]interface{}, 0, len(q.table.PKs))
+	byPK := make(map[internal.MapKey]reflect.Value, len(strcts))
+	for _, strct := range strcts {
+		for _, pk := range q.table.PKs {
+			if pk.HasZeroValue(strct) {
+				return errors.New("bun: RETURNING emulation requires the primary keys of the inserted rows")
+			}
+		}
+		key = modelKey(key[:0], strct, q.table.PKs)
+		byPK[internal.NewMapKey(key)] = strct
+	}
+
+	sq := NewSelectQuery(q.db).Conn(q.conn).Model(q.tableModel.Value()).WherePK()
+	sq.modelTable = q.modelTable
+	sq.tableSchema = q.tableSchema
+	for _, pk := range q.table.PKs {
+		sq.addColumn(schema.UnsafeIdent(pk.Name))
+	}
+	for _, ret := range q.returning {
+		sq.addColumn(ret)
+	}
+
+	rows, err := sq.Rows(ctx)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+
+	for rows.Next() {
+		row := reflect.New(q.table.Type)
+		if err := q.db.ScanRow(ctx, rows, row.Interface()); err != nil {
+			return err
+		}
+		row = row.Elem()
+
+		key = modelKey(key[:0], row, q.table.PKs)
+		strct, ok := byPK[internal.NewMapKey(key)]
+		if !ok {
+			continue
+		}
+		for _, column := range columns {
+			if field, ok := q.table.FieldMap[column]; ok {
+				field.Value(strct).Set(field.Value(row))
+			}
+		}
+	}
+
+	return rows.Err()
+}
+
 func (q *InsertQuery) tryLastInsertID(res sql.Result, dest []interface{}) error {
 	if q.db.features.Has(feature.Returning) || q.table == nil || len(q.table.PKs) != 1 {
 		return nil
@@ -536,6 +895,14 @@ func (q *InsertQuery) tryLastInsertID(res sql.Result, dest []interface{}) error
 			return err
 		}
 	case *sliceTableModel:
+		// LAST_INSERT_ID returns the id of the first inserted row. The ids of the other rows
+		// can be derived only when every row is inserted, so the query must not skip,
+		// replace, or update rows. RowsAffected can't tell that, because, for example,
+		// MySQL counts an updated row twice and an unchanged row as zero.
+		if q.ignore || q.replace || !q.onConflict.IsZero() {
+			return nil
+		}
+
 		sliceLen := model.slice.Len()
 		for i := 0; i < sliceLen; i++ {
 			strct := indirect(model.slice.Index(i))
@@ -0,0 +1,249 @@
+package bun
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/uptrace/bun/dialect/feature"
+	"github.com/uptrace/bun/internal"
+	"github.com/uptrace/bun/schema"
+)
+
+type MergeQuery struct {
+	baseQuery
+	returningQuery
+
+	using schema.QueryWithArgs
+	on    []schema.QueryWithSep
+	when  []schema.QueryWithSep
+}
+
+var _ Query = (*MergeQuery)(nil)
+
+func NewMergeQuery(db *DB) *MergeQuery {
+	q := &MergeQuery{
+		baseQuery: baseQuery{
+			db:   db,
+			conn: db.DB,
+		},
+	}
+	return q
+}
+
+func (q *MergeQuery) Conn(db IConn) *MergeQuery {
+	q.setConn(db)
+	return q
+}
+
+func (q *MergeQuery) Model(model interface{}) *MergeQuery {
+	q.setTableModel(model)
+	return q
+}
+
+// Apply calls the fn passing the MergeQuery as an argument.
+func (q *MergeQuery) Apply(fn func(*MergeQuery) *MergeQuery) *MergeQuery {
+	return fn(q)
+}
+
+func (q *MergeQuery) With(name string, query schema.QueryAppender) *MergeQuery {
+	q.addWith(name, query)
+	return q
+}
+
+// Comment adds a key-value pair to the sqlcommenter comment appended to the query,
+// for example, `/*action='list',route='%2Fbooks'*/`.
+func (q *MergeQuery) Comment(key, value string) *MergeQuery {
+	q.addComment(key, value)
+	return q
+}
+
+// NamedArg sets the ?name placeholder used in the ON and WHEN clauses,
+// for example, `WhenMatched("UPDATE SET updated_by = ?user_id")`. See SelectQuery.NamedArg.
+func (q *MergeQuery) NamedArg(name string, value interface{}) *MergeQuery {
+	q.addNamedArg(name, value)
+	return q
+}
+
+// Timeout sets the maximum duration of the query. See SelectQuery.Timeout.
+func (q *MergeQuery) Timeout(d time.Duration) *MergeQuery {
+	q.timeout = d
+	return q
+}
+
+//------------------------------------------------------------------------------
+
+func (q *MergeQuery) Table(tables ...string) *MergeQuery {
+	for _, table := range tables {
+		q.addTable(schema.UnsafeIdent(table))
+	}
+	return q
+}
+
+func (q *MergeQuery) TableExpr(query string, args ...interface{}) *MergeQuery {
+	q.addTable(schema.SafeQuery(query, args))
+	return q
+}
+
+func (q *MergeQuery) ModelTableExpr(query string, args ...interface{}) *MergeQuery {
+	q.modelTable = schema.SafeQuery(query, args)
+	return q
+}
+
+// TableSchema qualifies the model table name with the schema.
+func (q *MergeQuery) TableSchema(schema string) *MergeQuery {
+	q.tableSchema = schema
+	return q
+}
+
+//------------------------------------------------------------------------------
+
+// Using sets the source table of the MERGE query.
+func (q *MergeQuery) Using(table string) *MergeQuery {
+	q.using = schema.UnsafeIdent(table)
+	return q
+}
+
+// UsingExpr sets the source of the MERGE query, for example,
+// `UsingExpr("(?) AS src", db.NewSelect().Model(&books))` or
+// `UsingExpr("(?) AS src (?Columns)", db.NewValues(&books))`.
+func (q *MergeQuery) UsingExpr(query string, args ...interface{}) *MergeQuery {
+	q.using = schema.SafeQuery(query, args)
+	return q
+}
+
+// On adds a join condition between the target and the source.
+func (q *MergeQuery) On(query string, args ...interface{}) *MergeQuery {
+	q.on = append(q.on, schema.SafeQueryWithSep(query, args, " AND "))
+	return q
+}
+
+// When adds a `WHEN ...` clause, for example,
+// `When("MATCHED AND src.deleted THEN DELETE")`.
+func (q *MergeQuery) When(query string, args ...interface{}) *MergeQuery {
+	q.when = append(q.when, schema.SafeQueryWithSep(query, args, " WHEN "))
+	return q
+}
+
+// WhenMatched adds a `WHEN MATCHED THEN ...` clause, for example,
+// `WhenMatched("UPDATE SET title = src.title")`.
+func (q *MergeQuery) WhenMatched(query string, args ...interface{}) *MergeQuery {
+	q.when = append(q.when, schema.SafeQueryWithSep(query, args, " WHEN MATCHED THEN "))
+	return q
+}
+
+// WhenNotMatched adds a `WHEN NOT MATCHED THEN ...` clause, for example,
+// `WhenNotMatched("INSERT (id, title) VALUES (src.id, src.title)")`.
+func (q *MergeQuery) WhenNotMatched(query string, args ...interface{}) *MergeQuery {
+	q.when = append(q.when, schema.SafeQueryWithSep(query, args, " WHEN NOT MATCHED THEN "))
+	return q
+}
+
+// Returning adds a RETURNING clause to the query, which requires PostgreSQL 17.
+func (q *MergeQuery) Returning(query string, args ...interface{}) *MergeQuery {
+	q.addReturning(schema.SafeQuery(query, args))
+	return q
+}
+
+func (q *MergeQuery) Operation() string {
+	return "MERGE"
+}
+
+func (q *MergeQuery) String() string {
+	return q.queryString(q)
+}
+
+//------------------------------------------------------------------------------
+
+func (q *MergeQuery) AppendQuery(fmter schema.Formatter, b []byte) (_ []byte, err error) {
+	if q.err != nil {
+		return nil, q.err
+	}
+	fmter = formatterWithModel(q.withNamedArgs(fmter), q)
+
+	if !fmter.IsNop() && !fmter.HasFeature(feature.Merge) {
+		return nil, fmt.Errorf("bun: MERGE is not supported by %s", q.db.Dialect().Name())
+	}
+	if q.using.IsZero() {
+		return nil, errors.New("bun: MERGE query requires Using")
+	}
+	if len(q.on) == 0 {
+		return nil, errors.New("bun: MERGE query requires at least one On")
+	}
+	if len(q.when) == 0 {
+		return nil, errors.New("bun: MERGE query requires at least one When")
+	}
+
+	b, err = q.appendWith(fmter, b)
+	if err != nil {
+		return nil, err
+	}
+
+	b = fmter.AppendKeywords(b, "MERGE INTO ")
+
+	b, err = q.appendFirstTableWithAlias(fmter, b)
+	if err != nil {
+		return nil, err
+	}
+
+	b = fmter.AppendKeywords(b, " USING ")
+	b, err = q.using.AppendQuery(fmter, b)
+	if err != nil {
+		return nil, err
+	}
+
+	b = fmter.AppendKeywords(b, " ON ")
+	b, err = appendWhere(fmter, b, q.on)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, when := range q.when {
+		b = append(b, when.Sep...)
+		b, err = when.AppendQuery(fmter, b)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	b, err = q.appendReturning(fmter, b)
+	if err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+//------------------------------------------------------------------------------
+
+func (q *MergeQuery) Exec(ctx context.Context, dest ...interface{}) (sql.Result, error) {
+	queryBytes, err := q.AppendQuery(q.db.fmter, q.db.makeQueryBytes())
+	if err != nil {
+		return nil, err
+	}
+
+	query := internal.String(queryBytes)
+
+	var res sql.Result
+
+	if hasDest := len(dest) > 0; hasDest || q.hasReturning() {
+		model, err := q.getModel(dest)
+		if err != nil {
+			return nil, err
+		}
+
+		res, err = q.scan(ctx, q, query, model, hasDest)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		res, err = q.exec(ctx, q, query)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return res, nil
+}
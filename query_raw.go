@@ -0,0 +1,89 @@
+package bun
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/uptrace/bun/internal"
+	"github.com/uptrace/bun/schema"
+)
+
+// RawQuery is a raw SQL query that is formatted with the `?` placeholders,
+// passed to query hooks, and scanned into models like queries built with query builders.
+type RawQuery struct {
+	baseQuery
+
+	query string
+	args  []interface{}
+}
+
+var _ Query = (*RawQuery)(nil)
+
+func NewRawQuery(db *DB, query string, args ...interface{}) *RawQuery {
+	return &RawQuery{
+		baseQuery: baseQuery{
+			db:   db,
+			conn: db.DB,
+		},
+		query: query,
+		args:  args,
+	}
+}
+
+func (q *RawQuery) Conn(db IConn) *RawQuery {
+	q.setConn(db)
+	return q
+}
+
+// NamedArg sets the ?name placeholder used in the raw query,
+// for example, `NewRaw("SELECT * FROM ?schema.users")`. See SelectQuery.NamedArg.
+func (q *RawQuery) NamedArg(name string, value interface{}) *RawQuery {
+	q.addNamedArg(name, value)
+	return q
+}
+
+func (q *RawQuery) Operation() string {
+	return queryOperation(q.query)
+}
+
+func (q *RawQuery) String() string {
+	return q.queryString(q)
+}
+
+func (q *RawQuery) AppendQuery(fmter schema.Formatter, b []byte) ([]byte, error) {
+	if q.err != nil {
+		return nil, q.err
+	}
+	return q.withNamedArgs(fmter).AppendQuery(b, q.query, q.args...), nil
+}
+
+// Scan executes the query and scans the rows into the dest, for example,
+// a struct, a slice of structs, a map, or scalar values.
+func (q *RawQuery) Scan(ctx context.Context, dest ...interface{}) error {
+	model, err := q.getModel(dest)
+	if err != nil {
+		return err
+	}
+
+	queryBytes, err := q.AppendQuery(q.db.fmter, q.db.makeQueryBytes())
+	if err != nil {
+		return err
+	}
+
+	_, err = q.scan(ctx, q, internal.String(queryBytes), model, true)
+	return err
+}
+
+// Exec executes the query without returning any rows.
+func (q *RawQuery) Exec(ctx context.Context) (sql.Result, error) {
+	queryBytes, err := q.AppendQuery(q.db.fmter, q.db.makeQueryBytes())
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := q.exec(ctx, q, internal.String(queryBytes))
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
@@ -4,13 +4,19 @@ import (
 	"bytes"
 	"context"
 	"database/sql"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"reflect"
+	"regexp"
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/uptrace/bun/dialect"
+	"github.com/uptrace/bun/dialect/feature"
 	"github.com/uptrace/bun/internal"
 	"github.com/uptrace/bun/schema"
 )
@@ -26,15 +32,35 @@ type SelectQuery struct {
 	distinctOn []schema.QueryWithArgs
 	joins      []joinQuery
 	group      []schema.QueryWithArgs
+	groupAll   bool
 	having     []schema.QueryWithArgs
+	windows    []namedWindow
 	order      []schema.QueryWithArgs
 	limit      int32
 	offset     int32
 	selFor     schema.QueryWithArgs
+	forWait    schema.QueryWithArgs
+	asOf       schema.QueryWithArgs
 
 	union []union
+
+	onSelectAll   func(*SelectQuery)
+	expandAliases bool
+	viewColumns   map[string]string
+
+	totalCountAlias string
+	totalCount      int
+
+	cursor *selectCursor
+
+	// relJoin is the has-one relation join whose apply function is running.
+	relJoin *join
+	// modelJoin is the name of the last join added with JoinModel.
+	modelJoin string
 }
 
+var _ Query = (*SelectQuery)(nil)
+
 func NewSelectQuery(db *DB) *SelectQuery {
 	return &SelectQuery{
 		whereBaseQuery: whereBaseQuery{
@@ -66,11 +92,50 @@ func (q *SelectQuery) With(name string, query schema.QueryAppender) *SelectQuery
 	return q
 }
 
+// Comment adds a key-value pair to the sqlcommenter comment appended to the query,
+// for example, `/*action='list',route='%2Fbooks'*/`.
+func (q *SelectQuery) Comment(key, value string) *SelectQuery {
+	q.addComment(key, value)
+	return q
+}
+
+// NamedArg sets the value of the ?name placeholder for this query, overriding the value
+// set with DB.WithNamedArg, for example, `NamedArg("tenant_id", 1).Where("tenant_id = ?tenant_id")`.
+func (q *SelectQuery) NamedArg(name string, value interface{}) *SelectQuery {
+	q.addNamedArg(name, value)
+	return q
+}
+
+// Timeout sets the maximum duration of the query. The query context is canceled
+// when the timeout expires.
+// The timeout does not apply to Rows, because the rows are read after it returns.
+func (q *SelectQuery) Timeout(d time.Duration) *SelectQuery {
+	q.timeout = d
+	return q
+}
+
+// RequireIsolation makes the query fail unless it runs in a transaction
+// with at least the given isolation level, for example, sql.LevelSerializable.
+func (q *SelectQuery) RequireIsolation(level sql.IsolationLevel) *SelectQuery {
+	q.isolation = level
+	return q
+}
+
 func (q *SelectQuery) Distinct() *SelectQuery {
 	q.distinctOn = make([]schema.QueryWithArgs, 0)
 	return q
 }
 
+// DistinctColumns selects the columns using `SELECT DISTINCT a, b`.
+// Unlike DistinctOn, it is supported by all dialects.
+func (q *SelectQuery) DistinctColumns(columns ...string) *SelectQuery {
+	q.distinctOn = make([]schema.QueryWithArgs, 0)
+	for _, column := range columns {
+		q.addColumn(schema.UnsafeIdent(column))
+	}
+	return q
+}
+
 func (q *SelectQuery) DistinctOn(query string, args ...interface{}) *SelectQuery {
 	q.distinctOn = append(q.distinctOn, schema.SafeQuery(query, args))
 	return q
@@ -95,6 +160,44 @@ func (q *SelectQuery) ModelTableExpr(query string, args ...interface{}) *SelectQ
 	return q
 }
 
+// FromCTE selects from the CTE previously added with With. If the query has a model,
+// the CTE replaces the model table, for example, `FROM "recent" AS "book"`.
+func (q *SelectQuery) FromCTE(name string) *SelectQuery {
+	var found bool
+	for _, with := range q.with {
+		if with.name == name {
+			found = true
+			break
+		}
+	}
+	if !found {
+		q.setErr(fmt.Errorf("bun: FromCTE(%q) requires With(%q, ...)", name, name))
+		return q
+	}
+
+	if q.table != nil {
+		q.modelTable = schema.SafeQuery("? AS ?TableAlias", []interface{}{Ident(name)})
+	} else {
+		q.addTable(schema.UnsafeIdent(name))
+	}
+	return q
+}
+
+// TableSchema qualifies the model table name with the schema, for example,
+// `TableSchema("reporting")` uses `"reporting"."orders"` instead of `"orders"`.
+func (q *SelectQuery) TableSchema(schema string) *SelectQuery {
+	q.tableSchema = schema
+	return q
+}
+
+// ModelTableAlias overrides the model table alias for the query, for example,
+// `ModelTableAlias("u")` selects `FROM "users" AS "u"` and renders model columns
+// and the ?TableAlias placeholder as "u".
+func (q *SelectQuery) ModelTableAlias(alias string) *SelectQuery {
+	q.modelTableAlias = schema.Safe(q.db.fmter.AppendIdent(nil, alias))
+	return q
+}
+
 //------------------------------------------------------------------------------
 
 func (q *SelectQuery) Column(columns ...string) *SelectQuery {
@@ -109,11 +212,128 @@ func (q *SelectQuery) ColumnExpr(query string, args ...interface{}) *SelectQuery
 	return q
 }
 
+// ColumnCase adds a `CASE WHEN ... END` expression built by the fn to the column list, for example,
+// `ColumnCase(func(c *bun.CaseBuilder) { c.When("num > ?", 0).Then("positive").Else("other").As("sign") })`.
+func (q *SelectQuery) ColumnCase(fn func(*CaseBuilder)) *SelectQuery {
+	c := new(CaseBuilder)
+	fn(c)
+	q.addColumn(schema.SafeQuery("?", []interface{}{c}))
+	return q
+}
+
+// RunningTotal adds a running total of the column ordered by orderBy to the column list,
+// for example, `RunningTotal("amount", "date", "total")` selects
+// `sum("amount") OVER (ORDER BY "date" ROWS UNBOUNDED PRECEDING) AS "total"`.
+// Optional partition columns restart the total for each partition.
+func (q *SelectQuery) RunningTotal(
+	column, orderBy, alias string, partition ...string,
+) *SelectQuery {
+	if !q.db.features.Has(feature.WindowFunc) {
+		q.setErr(fmt.Errorf("bun: window functions are not supported by %s", q.db.dialect.Name()))
+		return q
+	}
+
+	args := make([]interface{}, 0, len(partition)+3)
+	args = append(args, Ident(column))
+
+	var b strings.Builder
+	b.WriteString("sum(?) OVER (")
+
+	if len(partition) > 0 {
+		b.WriteString("PARTITION BY ")
+		for i, col := range partition {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			b.WriteString("?")
+			args = append(args, Ident(col))
+		}
+		b.WriteString(" ")
+	}
+
+	b.WriteString("ORDER BY ? ROWS UNBOUNDED PRECEDING) AS ?")
+	args = append(args, parseOrder(orderBy), Ident(alias))
+
+	q.addColumn(schema.SafeQuery(b.String(), args))
+	return q
+}
+
+// ColumnWindow adds the window function call built by the fn to the column list, for example,
+// `ColumnWindow("row_number()", func(w *bun.Window) { w.PartitionBy("account_id").OrderBy("date DESC").As("num") })`
+// selects `row_number() OVER (PARTITION BY "account_id" ORDER BY "date" DESC) AS "num"`.
+func (q *SelectQuery) ColumnWindow(
+	fn string, window func(*Window), args ...interface{},
+) *SelectQuery {
+	if !q.db.features.Has(feature.WindowFunc) {
+		q.setErr(fmt.Errorf("bun: window functions are not supported by %s", q.db.dialect.Name()))
+		return q
+	}
+
+	w := new(Window)
+	window(w)
+	q.addColumn(schema.SafeQuery("?", []interface{}{&windowColumn{
+		fn:     schema.SafeQuery(fn, args),
+		window: w,
+	}}))
+	return q
+}
+
+// Window adds the named window built by the fn to the `WINDOW` clause so columns
+// added with ColumnWindow can use it with Window.Base, for example,
+// `Window("w", func(w *bun.Window) { w.PartitionBy("account_id") })`.
+func (q *SelectQuery) Window(name string, window func(*Window)) *SelectQuery {
+	if !q.db.features.Has(feature.WindowFunc) {
+		q.setErr(fmt.Errorf("bun: window functions are not supported by %s", q.db.dialect.Name()))
+		return q
+	}
+
+	w := new(Window)
+	window(w)
+	q.windows = append(q.windows, namedWindow{
+		name:   name,
+		window: w,
+	})
+	return q
+}
+
+// parseOrder parses the order the same way as Order, for example, `date DESC`.
+func parseOrder(order string) schema.QueryWithArgs {
+	if index := strings.IndexByte(order, ' '); index != -1 {
+		switch sort := order[index+1:]; strings.ToUpper(sort) {
+		case "ASC", "DESC", "ASC NULLS FIRST", "DESC NULLS FIRST",
+			"ASC NULLS LAST", "DESC NULLS LAST":
+			return schema.SafeQuery("? ?", []interface{}{Ident(order[:index]), Safe(sort)})
+		}
+	}
+	return schema.UnsafeIdent(order)
+}
+
+// ViewColumns maps the model columns to differently named columns, for example,
+// `ViewColumns(map[string]string{"name": "full_name"})` selects `full_name AS name`
+// so the values are scanned into the model fields as usual.
+func (q *SelectQuery) ViewColumns(columns map[string]string) *SelectQuery {
+	if q.viewColumns == nil {
+		q.viewColumns = make(map[string]string, len(columns))
+	}
+	for field, column := range columns {
+		q.viewColumns[field] = column
+	}
+	return q
+}
+
 func (q *SelectQuery) ExcludeColumn(columns ...string) *SelectQuery {
 	q.excludeColumn(columns)
 	return q
 }
 
+// WarnOnSelectStar registers a callback that is called when the query selects all model
+// columns because no columns were specified. It helps to find queries that could use
+// narrower projections, for example, to benefit from covering indexes.
+func (q *SelectQuery) WarnOnSelectStar(fn func(*SelectQuery)) *SelectQuery {
+	q.onSelectAll = fn
+	return q
+}
+
 //------------------------------------------------------------------------------
 
 func (q *SelectQuery) WherePK() *SelectQuery {
@@ -131,6 +351,39 @@ func (q *SelectQuery) WhereOr(query string, args ...interface{}) *SelectQuery {
 	return q
 }
 
+// WhereColumn adds a condition comparing two columns, for example,
+// `WhereColumn("a.x", "=", "b.y")` produces `"a"."x" = "b"."y"`.
+func (q *SelectQuery) WhereColumn(left, op, right string) *SelectQuery {
+	q.addWhereColumn(left, op, right)
+	return q
+}
+
+// WhereIn adds a `column IN (...)` condition. The values can be a slice or a subquery,
+// for example, `WhereIn("id", db.NewSelect().Column("id").Table("staging"))`.
+func (q *SelectQuery) WhereIn(column string, values interface{}) *SelectQuery {
+	q.addWhereIn(column, values)
+	return q
+}
+
+// WhereExists adds an `EXISTS (subquery)` condition.
+func (q *SelectQuery) WhereExists(subq *SelectQuery) *SelectQuery {
+	q.addWhereExists("EXISTS", subq)
+	return q
+}
+
+// WhereNotExists adds a `NOT EXISTS (subquery)` condition.
+func (q *SelectQuery) WhereNotExists(subq *SelectQuery) *SelectQuery {
+	q.addWhereExists("NOT EXISTS", subq)
+	return q
+}
+
+// WhereIfNotZero adds a `column = value` condition unless the value is zero,
+// for example, an empty string. Use Where to filter by a zero value.
+func (q *SelectQuery) WhereIfNotZero(column string, value interface{}) *SelectQuery {
+	q.addWhereIfNotZero(column, value)
+	return q
+}
+
 func (q *SelectQuery) WhereGroup(sep string, fn func(*SelectQuery) *SelectQuery) *SelectQuery {
 	saved := q.where
 	q.where = nil
@@ -169,11 +422,27 @@ func (q *SelectQuery) GroupExpr(group string, args ...interface{}) *SelectQuery
 	return q
 }
 
+// GroupByAll groups by all selected columns that are not aggregates, for example,
+// `Column("author_id").ColumnExpr("count(*) AS num").GroupByAll()` groups by author_id.
+// Aggregates in ColumnExpr are detected using function names like count or sum.
+func (q *SelectQuery) GroupByAll() *SelectQuery {
+	q.groupAll = true
+	return q
+}
+
 func (q *SelectQuery) Having(having string, args ...interface{}) *SelectQuery {
 	q.having = append(q.having, schema.SafeQuery(having, args))
 	return q
 }
 
+// ExpandAliases replaces column aliases defined with `ColumnExpr("expr AS alias")`
+// in HAVING and ORDER BY clauses with the aliased expressions when the dialect
+// does not support aliases in HAVING, for example, PostgreSQL.
+func (q *SelectQuery) ExpandAliases() *SelectQuery {
+	q.expandAliases = true
+	return q
+}
+
 func (q *SelectQuery) Order(orders ...string) *SelectQuery {
 	for _, order := range orders {
 		if order == "" {
@@ -203,6 +472,58 @@ func (q *SelectQuery) Order(orders ...string) *SelectQuery {
 	return q
 }
 
+// OrderSafe adds ORDER BY terms from user-provided specs such as "title:asc" or
+// "created_at:desc". Every column must be in the allowed set, otherwise the query
+// fails with an error. The direction defaults to ASC.
+func (q *SelectQuery) OrderSafe(allowed map[string]bool, specs ...string) *SelectQuery {
+	for _, spec := range specs {
+		if spec == "" {
+			continue
+		}
+
+		column, dir := spec, "ASC"
+		if i := strings.IndexByte(spec, ':'); i >= 0 {
+			column, dir = spec[:i], strings.ToUpper(spec[i+1:])
+		}
+
+		if !allowed[column] {
+			q.setErr(fmt.Errorf("bun: order column %q is not allowed", column))
+			return q
+		}
+
+		switch dir {
+		case "ASC", "DESC":
+		default:
+			q.setErr(fmt.Errorf("bun: invalid order direction %q for column %q", dir, column))
+			return q
+		}
+
+		q.order = append(q.order, schema.SafeQuery("? ?", []interface{}{
+			Ident(column),
+			Safe(dir),
+		}))
+	}
+	return q
+}
+
+// WithTotalCount adds the `count(*) OVER () AS alias` column so the query returns
+// the total number of rows ignoring limit and offset along with the paged rows.
+// Use TotalCount to get the count after scanning.
+func (q *SelectQuery) WithTotalCount(alias string) *SelectQuery {
+	if !q.db.features.Has(feature.WindowFunc) {
+		q.setErr(fmt.Errorf("bun: WithTotalCount is not supported by %s", q.db.dialect.Name()))
+		return q
+	}
+	q.totalCountAlias = alias
+	return q
+}
+
+// TotalCount returns the count scanned from the WithTotalCount column.
+// It is zero when the query returns no rows, for example, when the offset is too large.
+func (q *SelectQuery) TotalCount() int {
+	return q.totalCount
+}
+
 func (q *SelectQuery) OrderExpr(query string, args ...interface{}) *SelectQuery {
 	q.order = append(q.order, schema.SafeQuery(query, args))
 	return q
@@ -213,16 +534,150 @@ func (q *SelectQuery) Limit(n int) *SelectQuery {
 	return q
 }
 
+// RandomSample selects n random rows by ordering them by random(), or RAND() on MySQL,
+// and limiting the result to n rows. The database sorts the whole table,
+// so it is slow on large tables; consider `TableExpr("t TABLESAMPLE SYSTEM (1)")` on Postgres.
+func (q *SelectQuery) RandomSample(n int) *SelectQuery {
+	q.order = append(q.order, schema.SafeQuery("?", []interface{}{randomFunc{}}))
+	q.limit = int32(n)
+	return q
+}
+
+// randomFunc appends the function that returns a random number.
+type randomFunc struct{}
+
+var _ schema.QueryAppender = randomFunc{}
+
+func (randomFunc) AppendQuery(fmter schema.Formatter, b []byte) ([]byte, error) {
+	if fmter.HasFeature(feature.RandFunc) {
+		return fmter.AppendKeywords(b, "RAND()"), nil
+	}
+	return append(b, "random()"...), nil
+}
+
+// Cursor orders the query by the column and the primary key for keyset pagination,
+// for example, `Cursor("created_at", "desc").CursorAfter(token).Limit(20)`.
+// Use ScanCursor to get the token of the last scanned row. The column must be NOT NULL,
+// because NULL values can't be compared in the keyset condition.
+func (q *SelectQuery) Cursor(column, direction string) *SelectQuery {
+	if q.table == nil {
+		q.setErr(errors.New("bun: Cursor requires a model"))
+		return q
+	}
+	if len(q.table.PKs) != 1 {
+		q.setErr(fmt.Errorf("bun: Cursor requires %s to have a single primary key", q.table.TypeName))
+		return q
+	}
+
+	field, err := q.table.Field(column)
+	if err != nil {
+		q.setErr(err)
+		return q
+	}
+	if !field.IsPK && !field.NotNull &&
+		(field.NullZero || field.StructField.Type.Kind() == reflect.Ptr) {
+		q.setErr(fmt.Errorf("bun: Cursor requires NOT NULL column, got nullable %q", column))
+		return q
+	}
+
+	dir := strings.ToUpper(direction)
+	switch dir {
+	case "ASC", "DESC":
+	default:
+		q.setErr(fmt.Errorf("bun: invalid cursor direction %q", direction))
+		return q
+	}
+
+	cursor := &selectCursor{
+		fields: []*schema.Field{field},
+		desc:   dir == "DESC",
+	}
+	if pk := q.table.PKs[0]; pk != field {
+		cursor.fields = append(cursor.fields, pk)
+	}
+
+	for _, f := range cursor.fields {
+		q.order = append(q.order, schema.SafeQuery("?.? ?", []interface{}{
+			Safe(q.tableAlias()),
+			Safe(f.SQLName),
+			Safe(dir),
+		}))
+	}
+	q.cursor = cursor
+	return q
+}
+
+// CursorAfter selects the rows that follow the row the token was returned for,
+// for example, `WHERE ("created_at", "id") < ('2020-01-01', 42)`. An empty token
+// selects the first page.
+func (q *SelectQuery) CursorAfter(token string) *SelectQuery {
+	if q.cursor == nil {
+		q.setErr(errors.New("bun: CursorAfter requires Cursor"))
+		return q
+	}
+	if token == "" {
+		return q
+	}
+
+	values, err := q.cursor.decode(token)
+	if err != nil {
+		q.setErr(err)
+		return q
+	}
+
+	columns := make([]interface{}, len(q.cursor.fields))
+	for i, f := range q.cursor.fields {
+		columns[i] = schema.SafeQuery("?.?", []interface{}{Safe(q.tableAlias()), Safe(f.SQLName)})
+	}
+
+	op := ">"
+	if q.cursor.desc {
+		op = "<"
+	}
+	q.addWhere(schema.SafeQueryWithSep("(?) "+op+" (?)", []interface{}{
+		In(columns),
+		In(values),
+	}, " AND "))
+	return q
+}
+
 func (q *SelectQuery) Offset(n int) *SelectQuery {
 	q.offset = int32(n)
 	return q
 }
 
+// AsOfSystemTime reads the historical data at the time on CockroachDB, for example,
+// `AsOfSystemTime("'-10s'")` or `AsOfSystemTime("follower_read_timestamp()")`.
+func (q *SelectQuery) AsOfSystemTime(query string, args ...interface{}) *SelectQuery {
+	if !q.db.features.Has(feature.AsOfSystemTime) {
+		q.setErr(fmt.Errorf("bun: AS OF SYSTEM TIME is not supported by %s", q.db.dialect.Name()))
+		return q
+	}
+	q.asOf = schema.SafeQuery(query, args)
+	return q
+}
+
 func (q *SelectQuery) For(s string, args ...interface{}) *SelectQuery {
 	q.selFor = schema.SafeQuery(s, args)
 	return q
 }
 
+// ForUpdate locks the selected rows using `FOR UPDATE`.
+func (q *SelectQuery) ForUpdate() *SelectQuery {
+	return q.For("UPDATE")
+}
+
+// Wait makes the FOR clause wait up to n seconds for the lock,
+// for example, `FOR UPDATE WAIT 5`. It is supported only by MariaDB.
+func (q *SelectQuery) Wait(seconds int) *SelectQuery {
+	if seconds < 0 {
+		q.setErr(fmt.Errorf("bun: Wait(%d) requires a non-negative number of seconds", seconds))
+		return q
+	}
+	q.forWait = schema.SafeQuery("WAIT ?", []interface{}{seconds})
+	return q
+}
+
 //------------------------------------------------------------------------------
 
 func (q *SelectQuery) Union(other *SelectQuery) *SelectQuery {
@@ -263,9 +718,46 @@ func (q *SelectQuery) Join(join string, args ...interface{}) *SelectQuery {
 	q.joins = append(q.joins, joinQuery{
 		join: schema.SafeQuery(join, args),
 	})
+	q.modelJoin = ""
+	return q
+}
+
+// JoinModel joins the table of the model struct field with the name using the condition
+// and selects the table columns into the field like a has-one relation, so the columns
+// don't need to be aliased manually. The field must be excluded from the model columns
+// using the `bun:"-"` tag, for example:
+//
+//	type StoryWithUser struct {
+//		Story `bun:",extend"`
+//		User  *User `bun:"-"`
+//	}
+//
+//	db.NewSelect().Model(&stories).JoinModel("User", "user.id = story.user_id")
+//
+// Use JoinOn to add more join conditions.
+func (q *SelectQuery) JoinModel(name string, cond string, args ...interface{}) *SelectQuery {
+	if q.tableModel == nil {
+		q.setErr(errNilModel)
+		return q
+	}
+
+	join := q.tableModel.joinAdHoc(name)
+	if join == nil {
+		q.setErr(fmt.Errorf("bun: %s does not have struct field %q to join", q.table, name))
+		return q
+	}
+
+	join.on = append(join.on, schema.SafeQueryWithSep(cond, args, " AND "))
+	q.modelJoin = name
 	return q
 }
 
+// JoinOn adds a condition to the last join. In a has-one relation apply function,
+// the condition is added to the relation join, for example:
+//
+//	q.Relation("Profile", func(q *bun.SelectQuery) *bun.SelectQuery {
+//		return q.JoinOn("profile.active = ?", true)
+//	})
 func (q *SelectQuery) JoinOn(cond string, args ...interface{}) *SelectQuery {
 	return q.joinOn(cond, args, " AND ")
 }
@@ -275,6 +767,16 @@ func (q *SelectQuery) JoinOnOr(cond string, args ...interface{}) *SelectQuery {
 }
 
 func (q *SelectQuery) joinOn(cond string, args []interface{}, sep string) *SelectQuery {
+	if q.relJoin != nil {
+		q.relJoin.on = append(q.relJoin.on, schema.SafeQueryWithSep(cond, args, sep))
+		return q
+	}
+	if q.modelJoin != "" {
+		// Look up the join by name, because adding relations reallocates the joins.
+		j := q.tableModel.GetJoin(q.modelJoin)
+		j.on = append(j.on, schema.SafeQueryWithSep(cond, args, sep))
+		return q
+	}
 	if len(q.joins) == 0 {
 		q.err = errors.New("bun: query has no joins")
 		return q
@@ -288,12 +790,124 @@ func (q *SelectQuery) joinOn(cond string, args []interface{}, sep string) *Selec
 
 // Relation adds a relation to the query. Relation name can be:
 //   - RelationName to select all columns,
-//   - RelationName.column_name,
 //   - RelationName._ to join relation without selecting relation columns.
+//
+// Has-one and belongs-to relations are joined. Has-many and many-to-many relations
+// are loaded with a separate query keyed on the parent primary keys after the parent
+// rows are scanned. Nested relations are separated by a dot, for example, "Orders.Items".
 func (q *SelectQuery) Relation(name string, apply ...func(*SelectQuery) *SelectQuery) *SelectQuery {
+	if strings.HasSuffix(name, "._") {
+		return q.JoinRelation(strings.TrimSuffix(name, "._"), apply...)
+	}
+	q.relation(name, apply)
+	return q
+}
+
+// JoinRelation joins a has-one or belongs-to relation without selecting the relation columns,
+// for example, to filter or order by the relation columns.
+func (q *SelectQuery) JoinRelation(
+	name string, apply ...func(*SelectQuery) *SelectQuery,
+) *SelectQuery {
+	q.joinRelation(name, apply)
+	return q
+}
+
+func (q *SelectQuery) joinRelation(name string, apply []func(*SelectQuery) *SelectQuery) *join {
+	join := q.relation(name, apply)
+	if join == nil {
+		return nil
+	}
+
+	switch join.Relation.Type {
+	case schema.HasOneRelation, schema.BelongsToRelation:
+		join.joinOnly = true
+	default:
+		q.setErr(fmt.Errorf("bun: JoinRelation(%q) requires has-one or belongs-to relation", name))
+	}
+	return join
+}
+
+// RelationAs is like Relation, but joins the has-one or belongs-to relation using
+// the alias instead of the generated one, for example, `RelationAs("Author", "a")`
+// allows to reference the relation columns as `a.name` in Where and Order.
+func (q *SelectQuery) RelationAs(
+	name, alias string, apply ...func(*SelectQuery) *SelectQuery,
+) *SelectQuery {
+	var join *join
+	if strings.HasSuffix(name, "._") {
+		join = q.joinRelation(strings.TrimSuffix(name, "._"), apply)
+	} else {
+		join = q.relation(name, apply)
+	}
+	if join == nil {
+		return q
+	}
+
+	switch join.Relation.Type {
+	case schema.HasOneRelation, schema.BelongsToRelation:
+		join.alias = alias
+	default:
+		q.setErr(fmt.Errorf("bun: RelationAs(%q) requires has-one or belongs-to relation", name))
+	}
+	return q
+}
+
+// JoinType is the join used to join has-one and belongs-to relations.
+type JoinType string
+
+const (
+	// LeftJoin selects the rows with or without the relation. It is the default.
+	LeftJoin JoinType = "LEFT JOIN"
+	// InnerJoin selects only the rows that have the relation.
+	InnerJoin JoinType = "INNER JOIN"
+)
+
+// RelationOpts configures the relation added with RelationWithOpts.
+type RelationOpts struct {
+	// Apply modifies the relation query like the Relation apply function.
+	Apply func(*SelectQuery) *SelectQuery
+	// JoinType replaces LeftJoin used to join has-one and belongs-to relations,
+	// for example, with InnerJoin.
+	JoinType JoinType
+}
+
+// RelationWithOpts adds a relation to the query like Relation using the opts.
+func (q *SelectQuery) RelationWithOpts(name string, opts RelationOpts) *SelectQuery {
+	var apply []func(*SelectQuery) *SelectQuery
+	if opts.Apply != nil {
+		apply = append(apply, opts.Apply)
+	}
+
+	var join *join
+	if strings.HasSuffix(name, "._") {
+		join = q.joinRelation(strings.TrimSuffix(name, "._"), apply)
+	} else {
+		join = q.relation(name, apply)
+	}
+	if join == nil || opts.JoinType == "" {
+		return q
+	}
+
+	switch opts.JoinType {
+	case LeftJoin, InnerJoin:
+	default:
+		q.setErr(fmt.Errorf("bun: unsupported JoinType=%q", opts.JoinType))
+		return q
+	}
+
+	switch join.Relation.Type {
+	case schema.HasOneRelation, schema.BelongsToRelation:
+		join.joinType = opts.JoinType
+	default:
+		q.setErr(fmt.Errorf("bun: JoinType requires has-one or belongs-to relation, got %q", name))
+	}
+	return q
+}
+
+func (q *SelectQuery) relation(name string, apply []func(*SelectQuery) *SelectQuery) *join {
 	if q.tableModel == nil {
 		q.setErr(errNilModel)
-		return q
+		return nil
 	}
 
 	var fn func(*SelectQuery) *SelectQuery
@@ -307,10 +921,20 @@ func (q *SelectQuery) Relation(name string, apply ...func(*SelectQuery) *SelectQ
 	join := q.tableModel.Join(name, fn)
 	if join == nil {
 		q.setErr(fmt.Errorf("%s does not have relation=%q", q.table, name))
-		return q
+		return nil
 	}
 
-	return q
+	switch join.Relation.Type {
+	case schema.HasOneRelation, schema.BelongsToRelation:
+		// Apply has-one relations right away so the relation conditions are used
+		// by count queries and relations joined without selecting columns.
+		if fn != nil {
+			join.applyQuery(q)
+			join.ApplyQueryFunc = nil
+		}
+	}
+
+	return join
 }
 
 func (q *SelectQuery) forEachHasOneJoin(fn func(*join) error) error {
@@ -353,22 +977,38 @@ func (q *SelectQuery) selectJoins(ctx context.Context, joins []join) error {
 	return nil
 }
 
+func (q *SelectQuery) Operation() string {
+	return "SELECT"
+}
+
+func (q *SelectQuery) String() string {
+	return q.queryString(q)
+}
+
 //------------------------------------------------------------------------------
 
 func (q *SelectQuery) AppendQuery(fmter schema.Formatter, b []byte) (_ []byte, err error) {
-	return q.appendQuery(formatterWithModel(fmter, q), b, false)
+	return q.appendQuery(formatterWithModel(fmter, q), b, nil)
 }
 
+// appendQuery appends the query. If agg is not nil, the aggregate is selected
+// instead of the query columns and ORDER, LIMIT, and OFFSET are omitted.
 func (q *SelectQuery) appendQuery(
-	fmter schema.Formatter, b []byte, count bool,
+	fmter schema.Formatter, b []byte, agg *aggregate,
 ) (_ []byte, err error) {
 	if q.err != nil {
 		return nil, q.err
 	}
+	fmter = q.withNamedArgs(fmter)
 
-	cteCount := count && (len(q.group) > 0 || q.distinctOn != nil)
+	count := agg != nil
+	cteCount := agg == countAggregate && (len(q.group) > 0 || q.groupAll || q.distinctOn != nil)
+	var countWrapper string
 	if cteCount {
-		b = append(b, "WITH _count_wrapper AS ("...)
+		countWrapper = q.countWrapperName()
+		b = fmter.AppendKeywords(b, "WITH ")
+		b = append(b, countWrapper...)
+		b = fmter.AppendKeywords(b, " AS (")
 	}
 
 	if len(q.union) > 0 {
@@ -380,10 +1020,10 @@ func (q *SelectQuery) appendQuery(
 		return nil, err
 	}
 
-	b = append(b, "SELECT "...)
+	b = fmter.AppendKeywords(b, "SELECT ")
 
 	if len(q.distinctOn) > 0 {
-		b = append(b, "DISTINCT ON ("...)
+		b = fmter.AppendKeywords(b, "DISTINCT ON (")
 		for i, app := range q.distinctOn {
 			if i > 0 {
 				b = append(b, ", "...)
@@ -395,11 +1035,20 @@ func (q *SelectQuery) appendQuery(
 		}
 		b = append(b, ") "...)
 	} else if q.distinctOn != nil {
-		b = append(b, "DISTINCT "...)
+		b = fmter.AppendKeywords(b, "DISTINCT ")
+	}
+
+	if !count && q.useTop(fmter) {
+		b = fmter.AppendKeywords(b, "TOP ")
+		b = strconv.AppendInt(b, int64(q.limit), 10)
+		b = append(b, ' ')
 	}
 
 	if count && !cteCount {
-		b = append(b, "count(*)"...)
+		b, err = agg.appendQuery(fmter, b, q)
+		if err != nil {
+			return nil, err
+		}
 	} else {
 		b, err = q.appendColumns(fmter, b)
 		if err != nil {
@@ -412,6 +1061,9 @@ func (q *SelectQuery) appendQuery(
 		if err != nil {
 			return nil, err
 		}
+	} else if len(q.where) > 0 && fmter.HasFeature(feature.FromDual) {
+		// MySQL does not allow WHERE in SELECT queries without FROM.
+		b = fmter.AppendKeywords(b, " FROM DUAL")
 	}
 
 	if err := q.forEachHasOneJoin(func(j *join) error {
@@ -429,13 +1081,24 @@ func (q *SelectQuery) appendQuery(
 		}
 	}
 
+	if !q.asOf.IsZero() {
+		b = fmter.AppendKeywords(b, " AS OF SYSTEM TIME ")
+		b, err = q.asOf.AppendQuery(fmter, b)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	b, err = q.appendWhere(fmter, b, true)
 	if err != nil {
 		return nil, err
 	}
 
-	if len(q.group) > 0 {
-		b = append(b, " GROUP BY "...)
+	// Aggregates other than count don't select the columns to group by.
+	groupAll := q.groupAll && (!count || cteCount)
+
+	if len(q.group) > 0 || groupAll {
+		b = fmter.AppendKeywords(b, " GROUP BY ")
 		for i, f := range q.group {
 			if i > 0 {
 				b = append(b, ", "...)
@@ -445,16 +1108,47 @@ func (q *SelectQuery) appendQuery(
 				return nil, err
 			}
 		}
+
+		if groupAll {
+			if len(q.group) > 0 {
+				b = append(b, ", "...)
+			}
+			b, err = q.appendGroupAll(fmter, b)
+			if err != nil {
+				return nil, err
+			}
+		}
 	}
 
 	if len(q.having) > 0 {
-		b = append(b, " HAVING "...)
+		aliases, err := q.columnAliases(fmter)
+		if err != nil {
+			return nil, err
+		}
+
+		b = fmter.AppendKeywords(b, " HAVING ")
 		for i, f := range q.having {
 			if i > 0 {
-				b = append(b, " AND "...)
+				b = fmter.AppendKeywords(b, " AND ")
 			}
 			b = append(b, '(')
-			b, err = f.AppendQuery(fmter, b)
+			b, err = appendWithAliases(fmter, b, f, aliases)
+			if err != nil {
+				return nil, err
+			}
+			b = append(b, ')')
+		}
+	}
+
+	if len(q.windows) > 0 && (!count || cteCount) {
+		b = fmter.AppendKeywords(b, " WINDOW ")
+		for i, w := range q.windows {
+			if i > 0 {
+				b = append(b, ", "...)
+			}
+			b = fmter.AppendIdent(b, w.name)
+			b = fmter.AppendKeywords(b, " AS (")
+			b, err = w.window.AppendQuery(fmter, b)
 			if err != nil {
 				return nil, err
 			}
@@ -468,23 +1162,29 @@ func (q *SelectQuery) appendQuery(
 			return nil, err
 		}
 
-		if q.limit != 0 {
-			b = append(b, " LIMIT "...)
-			b = strconv.AppendInt(b, int64(q.limit), 10)
-		}
-
-		if q.offset != 0 {
-			b = append(b, " OFFSET "...)
-			b = strconv.AppendInt(b, int64(q.offset), 10)
-		}
+		b = q.appendLimitOffset(fmter, b)
 
 		if !q.selFor.IsZero() {
-			b = append(b, " FOR "...)
+			b = fmter.AppendKeywords(b, " FOR ")
 			b, err = q.selFor.AppendQuery(fmter, b)
 			if err != nil {
 				return nil, err
 			}
 		}
+
+		if !q.forWait.IsZero() {
+			if q.selFor.IsZero() {
+				return nil, errors.New("bun: Wait requires For")
+			}
+			if !fmter.IsNop() && !fmter.HasFeature(feature.LockWait) {
+				return nil, fmt.Errorf("bun: FOR ... WAIT is not supported by %s", q.db.Dialect().Name())
+			}
+			b = append(b, ' ')
+			b, err = q.forWait.AppendQuery(fmter, b)
+			if err != nil {
+				return nil, err
+			}
+		}
 	}
 
 	if len(q.union) > 0 {
@@ -502,15 +1202,92 @@ func (q *SelectQuery) appendQuery(
 	}
 
 	if cteCount {
-		b = append(b, ") SELECT count(*) FROM _count_wrapper"...)
+		b = fmter.AppendKeywords(b, ") SELECT count(*) FROM ")
+		b = append(b, countWrapper...)
 	}
 
 	return b, nil
 }
 
+// countWrapperName returns a name for the count wrapper CTE
+// that does not collide with the CTEs and tables used by the query.
+func (q *SelectQuery) countWrapperName() string {
+	name := "_count_wrapper"
+	for i := 1; q.usesName(name); i++ {
+		name = "_count_wrapper_" + strconv.Itoa(i)
+	}
+	return name
+}
+
+func (q *SelectQuery) usesName(name string) bool {
+	for _, with := range q.with {
+		if with.name == name {
+			return true
+		}
+	}
+	for _, table := range q.tables {
+		if strings.Contains(table.Query, name) {
+			return true
+		}
+	}
+	if strings.Contains(q.modelTable.Query, name) {
+		return true
+	}
+	return q.table != nil && q.table.Name == name
+}
+
+// useTop reports whether the limit is appended as `SELECT TOP n`.
+func (q *SelectQuery) useTop(fmter schema.Formatter) bool {
+	return fmter.HasFeature(feature.SelectTop) && q.limit > 0 && q.offset == 0
+}
+
+func (q *SelectQuery) appendLimitOffset(fmter schema.Formatter, b []byte) []byte {
+	if q.useTop(fmter) {
+		return b
+	}
+
+	if fmter.HasFeature(feature.OffsetFetch) {
+		// MSSQL requires OFFSET before FETCH, but uses TOP when there is no offset.
+		if q.offset != 0 {
+			b = fmter.AppendKeywords(b, " OFFSET ")
+			b = strconv.AppendInt(b, int64(q.offset), 10)
+			b = fmter.AppendKeywords(b, " ROWS")
+		}
+		if q.limit != 0 {
+			b = fmter.AppendKeywords(b, " FETCH NEXT ")
+			b = strconv.AppendInt(b, int64(q.limit), 10)
+			b = fmter.AppendKeywords(b, " ROWS ONLY")
+		}
+		return b
+	}
+
+	if q.limit != 0 {
+		b = fmter.AppendKeywords(b, " LIMIT ")
+		b = strconv.AppendInt(b, int64(q.limit), 10)
+	}
+
+	if q.offset != 0 {
+		b = fmter.AppendKeywords(b, " OFFSET ")
+		b = strconv.AppendInt(b, int64(q.offset), 10)
+	}
+
+	return b
+}
+
 func (q *SelectQuery) appendColumns(fmter schema.Formatter, b []byte) (_ []byte, err error) {
 	start := len(b)
 
+	if q.viewColumns != nil {
+		if q.table == nil {
+			return nil, errors.New("bun: ViewColumns requires a model")
+		}
+		for field := range q.viewColumns {
+			if _, err := q.table.Field(field); err != nil {
+				return nil, err
+			}
+		}
+	}
+
 	switch {
 	case q.columns != nil:
 		for i, col := range q.columns {
@@ -518,11 +1295,9 @@ func (q *SelectQuery) appendColumns(fmter schema.Formatter, b []byte) (_ []byte,
 				b = append(b, ", "...)
 			}
 
-			if col.Args == nil {
+			if col.Args == nil && q.table != nil {
 				if field, ok := q.table.FieldMap[col.Query]; ok {
-					b = append(b, q.table.SQLAlias...)
-					b = append(b, '.')
-					b = append(b, field.SQLName...)
+					b = q.appendTableField(fmter, b, field)
 					continue
 				}
 			}
@@ -533,18 +1308,34 @@ func (q *SelectQuery) appendColumns(fmter schema.Formatter, b []byte) (_ []byte,
 			}
 		}
 	case q.table != nil:
-		if len(q.table.Fields) > 10 && fmter.IsNop() {
-			b = append(b, q.table.SQLAlias...)
+		if q.onSelectAll != nil && !fmter.IsNop() {
+			q.onSelectAll(q)
+		}
+
+		switch {
+		case len(q.table.Fields) > 10 && fmter.IsNop():
+			b = append(b, q.tableAlias()...)
 			b = append(b, '.')
 			b = dialect.AppendString(b, fmt.Sprintf("%d columns", len(q.table.Fields)))
-		} else {
-			b = appendColumns(b, q.table.SQLAlias, q.table.Fields)
+		case q.viewColumns != nil:
+			for i, field := range q.table.Fields {
+				if i > 0 {
+					b = append(b, ", "...)
+				}
+				b = q.appendTableField(fmter, b, field)
+			}
+		default:
+			b = appendColumns(b, q.tableAlias(), q.table.Fields)
 		}
 	default:
 		b = append(b, '*')
 	}
 
 	if err := q.forEachHasOneJoin(func(j *join) error {
+		if j.joinOnly {
+			return nil
+		}
+
 		if len(b) != start {
 			b = append(b, ", "...)
 			start = len(b)
@@ -560,11 +1351,106 @@ func (q *SelectQuery) appendColumns(fmter schema.Formatter, b []byte) (_ []byte,
 		return nil, err
 	}
 
+	if q.totalCountAlias != "" {
+		if len(b) != start {
+			b = append(b, ", "...)
+		}
+		b = fmter.AppendKeywords(b, "count(*) OVER () AS ")
+		b = fmter.AppendIdent(b, q.totalCountAlias)
+	}
+
 	b = bytes.TrimSuffix(b, []byte(", "))
 
 	return b, nil
 }
 
+func (q *SelectQuery) appendTableField(
+	fmter schema.Formatter, b []byte, field *schema.Field,
+) []byte {
+	b = append(b, q.tableAlias()...)
+	b = append(b, '.')
+	if column, ok := q.viewColumns[field.Name]; ok {
+		b = fmter.AppendIdent(b, column)
+		b = fmter.AppendKeywords(b, " AS ")
+	}
+	b = append(b, field.SQLName...)
+	return b
+}
+
+// appendGroupAll appends the selected columns that are not aggregates.
+// Column expressions are referenced by their position in the select list.
+func (q *SelectQuery) appendGroupAll(fmter schema.Formatter, b []byte) (_ []byte, err error) {
+	start := len(b)
+
+	switch {
+	case q.columns != nil:
+		for i, col := range q.columns {
+			if strings.HasSuffix(col.Query, "*") {
+				return nil, fmt.Errorf("bun: GroupByAll does not support %q column", col.Query)
+			}
+
+			if col.Args == nil {
+				if len(b) != start {
+					b = append(b, ", "...)
+				}
+
+				if q.table != nil {
+					if field, ok := q.table.FieldMap[col.Query]; ok {
+						b = append(b, q.tableAlias()...)
+						b = append(b, '.')
+						b = append(b, field.SQLName...)
+						continue
+					}
+				}
+				b = fmter.AppendIdent(b, col.Query)
+				continue
+			}
+
+			if aggregateRE.MatchString(col.Query) {
+				continue
+			}
+
+			if len(b) != start {
+				b = append(b, ", "...)
+			}
+			b = strconv.AppendInt(b, int64(i+1), 10)
+		}
+	case q.table != nil:
+		b = appendColumns(b, q.tableAlias(), q.table.Fields)
+	}
+
+	if err := q.forEachHasOneJoin(func(j *join) error {
+		if j.joinOnly {
+			return nil
+		}
+		if j.columns != nil {
+			return fmt.Errorf("bun: GroupByAll does not support relation %q with columns", j.Relation.Field.GoName)
+		}
+
+		for _, field := range j.JoinModel.Table().Fields {
+			if len(b) != start {
+				b = append(b, ", "...)
+			}
+			b = j.appendAlias(fmter, b)
+			b = append(b, '.')
+			b = append(b, field.SQLName...)
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	if len(b) == start {
+		return nil, errors.New("bun: GroupByAll requires at least one non-aggregate column")
+	}
+
+	return b, nil
+}
+
+var aggregateRE = regexp.MustCompile(`(?i)\b(count|sum|avg|min|max|every|bool_and|bool_or|bit_and|bit_or|` +
+	`array_agg|string_agg|json_agg|jsonb_agg|json_object_agg|jsonb_object_agg|` +
+	`json_arrayagg|json_objectagg|group_concat|stddev\w*|var_pop|var_samp|variance)\s*\(`)
+
 func (q *SelectQuery) appendHasOneColumns(
 	fmter schema.Formatter, b []byte, join *join,
 ) (_ []byte, err error) {
@@ -581,7 +1467,7 @@ func (q *SelectQuery) appendHasOneColumns(
 					b = join.appendAlias(fmter, b)
 					b = append(b, '.')
 					b = append(b, field.SQLName...)
-					b = append(b, " AS "...)
+					b = fmter.AppendKeywords(b, " AS ")
 					b = join.appendAliasColumn(fmter, b, field.Name)
 					continue
 				}
@@ -602,26 +1488,31 @@ func (q *SelectQuery) appendHasOneColumns(
 		b = join.appendAlias(fmter, b)
 		b = append(b, '.')
 		b = append(b, field.SQLName...)
-		b = append(b, " AS "...)
+		b = fmter.AppendKeywords(b, " AS ")
 		b = join.appendAliasColumn(fmter, b, field.Name)
 	}
 	return b, nil
 }
 
 func (q *SelectQuery) appendTables(fmter schema.Formatter, b []byte) (_ []byte, err error) {
-	b = append(b, " FROM "...)
+	b = fmter.AppendKeywords(b, " FROM ")
 	return q.appendTablesWithAlias(fmter, b)
 }
 
 func (q *SelectQuery) appendOrder(fmter schema.Formatter, b []byte) (_ []byte, err error) {
 	if len(q.order) > 0 {
-		b = append(b, " ORDER BY "...)
+		aliases, err := q.columnAliases(fmter)
+		if err != nil {
+			return nil, err
+		}
+
+		b = fmter.AppendKeywords(b, " ORDER BY ")
 
 		for i, f := range q.order {
 			if i > 0 {
 				b = append(b, ", "...)
 			}
-			b, err = f.AppendQuery(fmter, b)
+			b, err = appendWithAliases(fmter, b, f, aliases)
 			if err != nil {
 				return nil, err
 			}
@@ -632,19 +1523,215 @@ func (q *SelectQuery) appendOrder(fmter schema.Formatter, b []byte) (_ []byte, e
 	return b, nil
 }
 
+var columnAliasRE = regexp.MustCompile(`(?is)^(.+?)\s+AS\s+(\w+|"[^"]+"|` + "`[^`]+`" + `|\?)\s*$`)
+
+// columnAliases returns the formatted column expressions keyed by their aliases
+// if ExpandAliases is used and the dialect does not support aliases in HAVING.
+func (q *SelectQuery) columnAliases(fmter schema.Formatter) (map[string][]byte, error) {
+	if !q.expandAliases || fmter.HasFeature(feature.HavingAlias) {
+		return nil, nil
+	}
+
+	var aliases map[string][]byte
+
+	for _, col := range q.columns {
+		if col.Args == nil {
+			continue
+		}
+
+		m := columnAliasRE.FindStringSubmatch(col.Query)
+		if m == nil {
+			continue
+		}
+
+		expr, alias, args := m[1], m[2], col.Args
+		switch alias[0] {
+		case '?':
+			if len(args) == 0 {
+				continue
+			}
+			ident, ok := args[len(args)-1].(Ident)
+			if !ok {
+				continue
+			}
+			alias, args = string(ident), args[:len(args)-1]
+		case '"', '`':
+			alias = alias[1 : len(alias)-1]
+		default:
+			alias = strings.ToLower(alias)
+		}
+
+		b, err := schema.SafeQuery(expr, args).AppendQuery(fmter, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		if aliases == nil {
+			aliases = make(map[string][]byte)
+		}
+		aliases[alias] = b
+	}
+
+	return aliases, nil
+}
+
+// appendWithAliases appends the query replacing column aliases with the expressions.
+func appendWithAliases(
+	fmter schema.Formatter, b []byte, query schema.QueryAppender, aliases map[string][]byte,
+) (_ []byte, err error) {
+	if len(aliases) == 0 {
+		return query.AppendQuery(fmter, b)
+	}
+
+	src, err := query.AppendQuery(fmter, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < len(src); {
+		c := src[i]
+
+		switch {
+		case c == '\'':
+			end := bytes.IndexByte(src[i+1:], '\'')
+			if end == -1 {
+				return append(b, src[i:]...), nil
+			}
+			end += i + 2
+			b = append(b, src[i:end]...)
+			i = end
+		case c == '"' || c == '`':
+			end := bytes.IndexByte(src[i+1:], c)
+			if end == -1 {
+				return append(b, src[i:]...), nil
+			}
+			end += i + 2
+			b = appendAliasExpr(b, src, i, end, string(src[i+1:end-1]), aliases)
+			i = end
+		case c == '_' || isLetter(c):
+			end := i + 1
+			for end < len(src) && (src[end] == '_' || isLetter(src[end]) || isDigit(src[end])) {
+				end++
+			}
+			b = appendAliasExpr(b, src, i, end, strings.ToLower(string(src[i:end])), aliases)
+			i = end
+		default:
+			b = append(b, c)
+			i++
+		}
+	}
+
+	return b, nil
+}
+
+func appendAliasExpr(b, src []byte, start, end int, name string, aliases map[string][]byte) []byte {
+	expr, ok := aliases[name]
+	// Skip qualified names like table.alias and function calls like alias().
+	if !ok || (start > 0 && src[start-1] == '.') ||
+		(end < len(src) && (src[end] == '.' || src[end] == '(')) {
+		return append(b, src[start:end]...)
+	}
+
+	b = append(b, '(')
+	b = append(b, expr...)
+	b = append(b, ')')
+	return b
+}
+
+func isLetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
 //------------------------------------------------------------------------------
 
+// Rows executes the query and returns the rows without scanning them, so large results
+// can be processed row by row with constant memory, for example:
+//
+//	for rows.Next() {
+//		book := new(Book)
+//		if err := db.ScanRow(ctx, rows, book); err != nil {
+//			return err
+//		}
+//	}
 func (q *SelectQuery) Rows(ctx context.Context) (*sql.Rows, error) {
+	if err := q.checkIsolation(); err != nil {
+		return nil, err
+	}
+	if err := q.beforeAppendQueryHook(ctx, q); err != nil {
+		return nil, err
+	}
+
 	queryBytes, err := q.AppendQuery(q.db.fmter, q.db.makeQueryBytes())
 	if err != nil {
 		return nil, err
 	}
 
-	query := internal.String(queryBytes)
-	return q.conn.QueryContext(ctx, query)
+	query := q.appendComment(ctx, internal.String(queryBytes))
+	return q.queryContext(ctx, q, query)
+}
+
+// ColumnTypes returns the column types of the query result without fetching any rows.
+// The query is wrapped in a subquery with LIMIT 0 so it works with queries
+// that already have a limit or use UNION.
+func (q *SelectQuery) ColumnTypes(ctx context.Context) ([]*sql.ColumnType, error) {
+	if err := q.checkIsolation(); err != nil {
+		return nil, err
+	}
+	if err := q.beforeAppendQueryHook(ctx, q); err != nil {
+		return nil, err
+	}
+
+	b := q.db.makeQueryBytes()
+	b = q.db.fmter.AppendKeywords(b, "SELECT * FROM (")
+
+	b, err := q.AppendQuery(q.db.fmter, b)
+	if err != nil {
+		return nil, err
+	}
+
+	b = q.db.fmter.AppendKeywords(b, ") AS _column_types LIMIT 0")
+	query := q.appendComment(ctx, internal.String(b))
+
+	ctx, cancel := q.withTimeout(ctx)
+	defer cancel()
+
+	ctx, event := q.db.beforeQuery(ctx, q, query, nil)
+
+	rows, err := q.conn.QueryContext(ctx, query)
+	if err != nil {
+		q.db.afterQuery(ctx, event, nil, err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	columnTypes, err := rows.ColumnTypes()
+	if err == nil {
+		err = rows.Err()
+	}
+
+	q.db.afterQuery(ctx, event, nil, err)
+
+	if err != nil {
+		return nil, err
+	}
+	return columnTypes, nil
+}
+
+// Prepare creates a prepared statement replacing the query args with placeholders.
+// The args must be passed in the same order when executing the statement.
+func (q *SelectQuery) Prepare(ctx context.Context) (Stmt, error) {
+	return q.prepare(ctx, q)
 }
 
 func (q *SelectQuery) Exec(ctx context.Context) (res sql.Result, err error) {
+	if err := q.beforeAppendQueryHook(ctx, q); err != nil {
+		return nil, err
+	}
+
 	queryBytes, err := q.AppendQuery(q.db.fmter, q.db.makeQueryBytes())
 	if err != nil {
 		return nil, err
@@ -665,6 +1752,84 @@ func (q *SelectQuery) Scan(ctx context.Context, dest ...interface{}) error {
 	if err != nil {
 		return err
 	}
+	return q.scanModel(ctx, model)
+}
+
+// ScanStrict is like Scan, but returns an error listing all result columns
+// that do not match any model field, for example, misspelled ColumnExpr aliases.
+func (q *SelectQuery) ScanStrict(ctx context.Context, dest ...interface{}) error {
+	model, err := q.getModel(dest)
+	if err != nil {
+		return err
+	}
+
+	strictModel, ok := model.(interface {
+		setStrict(bool)
+		unknownColumnsErr() error
+	})
+	if !ok {
+		return fmt.Errorf("bun: ScanStrict does not support %T", model.Value())
+	}
+
+	strictModel.setStrict(true)
+	defer strictModel.setStrict(false)
+
+	if err := q.scanModel(ctx, model); err != nil {
+		return err
+	}
+	return strictModel.unknownColumnsErr()
+}
+
+// ScanCursor is like Scan, but also returns the cursor token of the last scanned row,
+// which is passed to CursorAfter to select the next page. The token is empty
+// when the query returns no rows.
+func (q *SelectQuery) ScanCursor(ctx context.Context, dest ...interface{}) (string, error) {
+	if q.err != nil {
+		return "", q.err
+	}
+	if q.cursor == nil {
+		return "", errors.New("bun: ScanCursor requires Cursor")
+	}
+
+	model, err := q.getModel(dest)
+	if err != nil {
+		return "", err
+	}
+	if err := q.scanModel(ctx, model); err != nil {
+		return "", err
+	}
+	return q.cursor.next(model)
+}
+
+// ScanOne is like Scan, but returns sql.ErrNoRows when the query returns no rows
+// and ErrMultipleRows when the query returns more than one row.
+func (q *SelectQuery) ScanOne(ctx context.Context, dest ...interface{}) error {
+	model, err := q.getModel(dest)
+	if err != nil {
+		return err
+	}
+
+	q.flags = q.flags.Set(scanOneFlag)
+	defer func() {
+		q.flags = q.flags.Remove(scanOneFlag)
+	}()
+
+	return q.scanModel(ctx, model)
+}
+
+func (q *SelectQuery) scanModel(ctx context.Context, model model) error {
+	if q.totalCountAlias != "" {
+		totalModel, ok := model.(interface {
+			setTotalCount(column string, dest *int)
+		})
+		if !ok {
+			return fmt.Errorf("bun: WithTotalCount does not support %T", model.Value())
+		}
+
+		q.totalCount = 0
+		totalModel.setTotalCount(q.totalCountAlias, &q.totalCount)
+		defer totalModel.setTotalCount("", nil)
+	}
 
 	if q.limit > 1 {
 		if model, ok := model.(interface{ SetCap(int) }); ok {
@@ -676,6 +1841,9 @@ func (q *SelectQuery) Scan(ctx context.Context, dest ...interface{}) error {
 		if err := q.beforeSelectHook(ctx); err != nil {
 			return err
 		}
+		if err := q.beforeAppendQueryHook(ctx, q); err != nil {
+			return err
+		}
 	}
 
 	queryBytes, err := q.AppendQuery(q.db.fmter, q.db.makeQueryBytes())
@@ -726,62 +1894,216 @@ func (q *SelectQuery) afterSelectHook(ctx context.Context) error {
 }
 
 func (q *SelectQuery) Count(ctx context.Context) (int, error) {
+	if err := q.checkIsolation(); err != nil {
+		return 0, err
+	}
+	if err := q.beforeAppendQueryHook(ctx, q); err != nil {
+		return 0, err
+	}
+
 	qq := countQuery{q}
 
-	queryBytes, err := qq.appendQuery(q.db.fmter, nil, true)
+	queryBytes, err := qq.AppendQuery(q.db.fmter, nil)
 	if err != nil {
 		return 0, err
 	}
 
-	query := internal.String(queryBytes)
+	query := q.appendComment(ctx, internal.String(queryBytes))
+
+	ctx, cancel := q.withTimeout(ctx)
+	defer cancel()
+
 	ctx, event := q.db.beforeQuery(ctx, qq, query, nil)
 
 	var num int
-	err = q.conn.QueryRowContext(ctx, query).Scan(&num)
+	err = q.queryRowContext(ctx, qq, query).Scan(&num)
+
+	q.db.afterQuery(ctx, event, nil, err)
+
+	return num, err
+}
+
+// Exists reports whether the query returns any rows using `SELECT EXISTS (query)`,
+// which is cheaper than counting the rows.
+func (q *SelectQuery) Exists(ctx context.Context) (bool, error) {
+	if err := q.checkIsolation(); err != nil {
+		return false, err
+	}
+	if err := q.beforeAppendQueryHook(ctx, q); err != nil {
+		return false, err
+	}
+
+	qq := existsQuery{q}
+
+	queryBytes, err := qq.AppendQuery(q.db.fmter, nil)
+	if err != nil {
+		return false, err
+	}
+
+	query := q.appendComment(ctx, internal.String(queryBytes))
+
+	ctx, cancel := q.withTimeout(ctx)
+	defer cancel()
+
+	ctx, event := q.db.beforeQuery(ctx, qq, query, nil)
+
+	var exists bool
+	err = q.queryRowContext(ctx, qq, query).Scan(&exists)
+
+	q.db.afterQuery(ctx, event, nil, err)
+
+	return exists, err
+}
+
+// Sum returns the sum of the column values using the query WHERE and JOIN conditions.
+// Sum returns an invalid sql.NullFloat64 when there are no rows to aggregate.
+func (q *SelectQuery) Sum(ctx context.Context, column string) (sql.NullFloat64, error) {
+	return q.aggregate(ctx, "sum", column)
+}
+
+// Avg returns the average of the column values. See Sum for details.
+func (q *SelectQuery) Avg(ctx context.Context, column string) (sql.NullFloat64, error) {
+	return q.aggregate(ctx, "avg", column)
+}
+
+// Min returns the minimum of the column values. See Sum for details.
+func (q *SelectQuery) Min(ctx context.Context, column string) (sql.NullFloat64, error) {
+	return q.aggregate(ctx, "min", column)
+}
+
+// Max returns the maximum of the column values. See Sum for details.
+func (q *SelectQuery) Max(ctx context.Context, column string) (sql.NullFloat64, error) {
+	return q.aggregate(ctx, "max", column)
+}
+
+func (q *SelectQuery) aggregate(
+	ctx context.Context, fn, column string,
+) (sql.NullFloat64, error) {
+	qq := aggregateQuery{
+		SelectQuery: q,
+		agg: &aggregate{
+			fn:     fn,
+			column: schema.UnsafeIdent(column),
+		},
+	}
+
+	var num sql.NullFloat64
+
+	if err := q.checkIsolation(); err != nil {
+		return num, err
+	}
+	if err := q.beforeAppendQueryHook(ctx, q); err != nil {
+		return num, err
+	}
+
+	queryBytes, err := qq.AppendQuery(q.db.fmter, nil)
+	if err != nil {
+		return num, err
+	}
+
+	query := q.appendComment(ctx, internal.String(queryBytes))
+
+	ctx, cancel := q.withTimeout(ctx)
+	defer cancel()
+
+	ctx, event := q.db.beforeQuery(ctx, qq, query, nil)
+
+	err = q.queryRowContext(ctx, qq, query).Scan(&num)
 
 	q.db.afterQuery(ctx, event, nil, err)
 
 	return num, err
 }
 
+// ScanAndCount scans the rows into the dest and counts all rows ignoring the limit and offset.
+// With *DB the queries run concurrently; with Tx and Conn they run sequentially
+// on the same connection. Errors from both queries are returned.
 func (q *SelectQuery) ScanAndCount(ctx context.Context, dest ...interface{}) (int, error) {
+	// Transactions and connections can't run queries concurrently.
+	if _, ok := q.conn.(*sql.DB); !ok {
+		return q.scanAndCount(ctx, dest)
+	}
+
+	// Run the hook before starting the goroutines, because it modifies the query
+	// and both queries must include the conditions added by the hook.
+	if err := q.beforeAppendQueryHook(ctx, q); err != nil {
+		return 0, err
+	}
+
 	var count int
+	var scanErr, countErr error
 	var wg sync.WaitGroup
-	var mu sync.Mutex
-	var firstErr error
 
 	if q.limit >= 0 {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-
-			if err := q.Scan(ctx, dest...); err != nil {
-				mu.Lock()
-				if firstErr == nil {
-					firstErr = err
-				}
-				mu.Unlock()
-			}
+			scanErr = q.Scan(ctx, dest...)
 		}()
 	}
 
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
-
-		var err error
-		count, err = q.Count(ctx)
-		if err != nil {
-			mu.Lock()
-			if firstErr == nil {
-				firstErr = err
-			}
-			mu.Unlock()
-		}
+		count, countErr = q.Count(ctx)
 	}()
 
 	wg.Wait()
-	return count, firstErr
+	return count, internal.JoinErrors(scanErr, countErr)
+}
+
+// scanAndCount runs the scan and count queries sequentially on the same connection.
+func (q *SelectQuery) scanAndCount(ctx context.Context, dest []interface{}) (int, error) {
+	if q.limit >= 0 {
+		if err := q.Scan(ctx, dest...); err != nil {
+			return 0, err
+		}
+	}
+	return q.Count(ctx)
+}
+
+// ConsistentScanAndCount is like ScanAndCount, but runs both queries sequentially
+// in a read-only repeatable read transaction so the rows and the count are consistent.
+// If the query already uses a transaction, the queries run in that transaction.
+func (q *SelectQuery) ConsistentScanAndCount(ctx context.Context, dest ...interface{}) (int, error) {
+	if tx, ok := q.conn.(*sql.Tx); ok {
+		return q.scanAndCountIn(ctx, tx, dest)
+	}
+
+	beginner, ok := q.conn.(interface {
+		BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+	})
+	if !ok {
+		return 0, fmt.Errorf("bun: %T does not support transactions", q.conn)
+	}
+
+	tx, err := beginner.BeginTx(ctx, &sql.TxOptions{
+		Isolation: sql.LevelRepeatableRead,
+		ReadOnly:  true,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	count, err := q.scanAndCountIn(ctx, tx, dest)
+	if err != nil {
+		_ = tx.Rollback()
+		return 0, err
+	}
+	return count, tx.Commit()
+}
+
+func (q *SelectQuery) scanAndCountIn(
+	ctx context.Context, tx *sql.Tx, dest []interface{},
+) (int, error) {
+	clone := *q
+	clone.conn = tx
+	if !q.inTx {
+		clone.inTx = true
+		clone.txIsolation = sql.LevelRepeatableRead
+	}
+
+	return clone.scanAndCount(ctx, dest)
 }
 
 //------------------------------------------------------------------------------
@@ -800,7 +2122,7 @@ func (j *joinQuery) AppendQuery(fmter schema.Formatter, b []byte) (_ []byte, err
 	}
 
 	if len(j.on) > 0 {
-		b = append(b, " ON "...)
+		b = fmter.AppendKeywords(b, " ON ")
 		for i, on := range j.on {
 			if i > 0 {
 				b = append(b, on.Sep...)
@@ -818,6 +2140,104 @@ func (j *joinQuery) AppendQuery(fmter schema.Formatter, b []byte) (_ []byte, err
 	return b, nil
 }
 
+// usingTable returns the joined table without the JOIN keyword, for example,
+// `authors AS a` for `JOIN authors AS a`, so the join can be rewritten as a table
+// in the USING or FROM clause and the JoinOn conditions in WHERE. It reports false
+// for outer joins and joins with the conditions in the join string.
+func (j *joinQuery) usingTable() (schema.QueryWithArgs, bool) {
+	query := strings.TrimSpace(j.join.Query)
+	upper := strings.ToUpper(query)
+
+	for _, prefix := range []string{"JOIN ", "INNER JOIN ", "CROSS JOIN "} {
+		if !strings.HasPrefix(upper, prefix) {
+			continue
+		}
+		rest := upper[len(prefix):]
+		if strings.Contains(rest, " ON ") ||
+			strings.Contains(rest, " USING ") ||
+			strings.Contains(rest, "JOIN ") {
+			return schema.QueryWithArgs{}, false
+		}
+		return schema.SafeQuery(strings.TrimSpace(query[len(prefix):]), j.join.Args), true
+	}
+	return schema.QueryWithArgs{}, false
+}
+
+// joinTables rewrites the joins as a list of tables, for example, in `DELETE ... USING`
+// and `UPDATE ... FROM`, with the JoinOn conditions in WHERE.
+type joinTables []joinQuery
+
+var _ schema.QueryAppender = joinTables(nil)
+
+// ok reports whether every join can be rewritten as a table.
+func (joins joinTables) ok() bool {
+	for i := range joins {
+		if _, ok := joins[i].usingTable(); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func (joins joinTables) appendTables(fmter schema.Formatter, b []byte) (_ []byte, err error) {
+	for i := range joins {
+		if i > 0 {
+			b = append(b, ", "...)
+		}
+		table, _ := joins[i].usingTable()
+		b, err = table.AppendQuery(fmter, b)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+// where returns a copy of the query with the JoinOn conditions added to WHERE.
+func (joins joinTables) where(q whereBaseQuery) whereBaseQuery {
+	if joins.isZero() || (len(q.where) == 0 && !q.flags.Has(wherePKFlag)) {
+		return q
+	}
+	where := make([]schema.QueryWithSep, 0, len(q.where)+1)
+	where = append(where, schema.SafeQueryWithSep("?", []interface{}{joins}, " AND "))
+	q.where = append(where, q.where...)
+	return q
+}
+
+// AppendQuery appends the JoinOn conditions of the joins.
+func (joins joinTables) AppendQuery(fmter schema.Formatter, b []byte) (_ []byte, err error) {
+	first := true
+	for _, j := range joins {
+		for i, on := range j.on {
+			if !first {
+				if i == 0 {
+					b = fmter.AppendKeywords(b, " AND ")
+				} else {
+					b = append(b, on.Sep...)
+				}
+			}
+			first = false
+
+			b = append(b, '(')
+			b, err = on.AppendQuery(fmter, b)
+			if err != nil {
+				return nil, err
+			}
+			b = append(b, ')')
+		}
+	}
+	return b, nil
+}
+
+func (joins joinTables) isZero() bool {
+	for _, j := range joins {
+		if len(j.on) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
 //------------------------------------------------------------------------------
 
 type countQuery struct {
@@ -825,5 +2245,122 @@ type countQuery struct {
 }
 
 func (q countQuery) AppendQuery(fmter schema.Formatter, b []byte) (_ []byte, err error) {
-	return q.appendQuery(formatterWithModel(fmter, q), b, true)
+	return q.appendQuery(formatterWithModel(fmter, q), b, countAggregate)
+}
+
+//------------------------------------------------------------------------------
+
+type existsQuery struct {
+	*SelectQuery
+}
+
+func (q existsQuery) AppendQuery(fmter schema.Formatter, b []byte) (_ []byte, err error) {
+	b = fmter.AppendKeywords(b, "SELECT EXISTS (")
+	b, err = q.SelectQuery.AppendQuery(fmter, b)
+	if err != nil {
+		return nil, err
+	}
+	return append(b, ')'), nil
+}
+
+//------------------------------------------------------------------------------
+
+var countAggregate = &aggregate{
+	fn:     "count",
+	column: schema.SafeQuery("*", nil),
+}
+
+type aggregate struct {
+	fn     string
+	column schema.QueryWithArgs
+}
+
+func (agg *aggregate) appendQuery(
+	fmter schema.Formatter, b []byte, q *SelectQuery,
+) (_ []byte, err error) {
+	b = append(b, agg.fn...)
+	b = append(b, '(')
+
+	if agg.column.Args == nil && q.table != nil {
+		if field, ok := q.table.FieldMap[agg.column.Query]; ok {
+			b = append(b, q.tableAlias()...)
+			b = append(b, '.')
+			b = append(b, field.SQLName...)
+			b = append(b, ')')
+			return b, nil
+		}
+	}
+
+	b, err = agg.column.AppendQuery(fmter, b)
+	if err != nil {
+		return nil, err
+	}
+
+	b = append(b, ')')
+	return b, nil
+}
+
+type aggregateQuery struct {
+	*SelectQuery
+	agg *aggregate
+}
+
+func (q aggregateQuery) AppendQuery(fmter schema.Formatter, b []byte) (_ []byte, err error) {
+	return q.appendQuery(formatterWithModel(fmter, q), b, q.agg)
+}
+
+//------------------------------------------------------------------------------
+
+// selectCursor is the keyset pagination state, see SelectQuery.Cursor.
+type selectCursor struct {
+	fields []*schema.Field // the cursor column and the primary key
+	desc   bool
+}
+
+// next encodes the cursor column values of the last scanned row.
+func (c *selectCursor) next(model model) (string, error) {
+	v := reflect.Indirect(reflect.ValueOf(model.Value()))
+	if v.Kind() == reflect.Slice {
+		if v.Len() == 0 {
+			return "", nil
+		}
+		v = reflect.Indirect(v.Index(v.Len() - 1))
+	}
+	if v.Kind() != reflect.Struct {
+		return "", fmt.Errorf("bun: Cursor does not support %s", v.Type())
+	}
+
+	values := make([]interface{}, len(c.fields))
+	for i, f := range c.fields {
+		values[i] = f.Value(v).Interface()
+	}
+
+	b, err := json.Marshal(values)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// decode decodes the token into values of the cursor field types.
+func (c *selectCursor) decode(token string) ([]interface{}, error) {
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, fmt.Errorf("bun: invalid cursor %q", token)
+	}
+
+	var raw []json.RawMessage
+	if err := json.Unmarshal(b, &raw); err != nil || len(raw) != len(c.fields) {
+		return nil, fmt.Errorf("bun: invalid cursor %q", token)
+	}
+
+	values := make([]interface{}, len(c.fields))
+	for i, f := range c.fields {
+		v := reflect.New(f.IndirectType)
+		if err := json.Unmarshal(raw[i], v.Interface()); err != nil {
+			return nil, fmt.Errorf("bun: invalid cursor %q", token)
+		}
+		values[i] = v.Elem().Interface()
+	}
+	return values, nil
 }
@@ -3,6 +3,7 @@ package bun
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"sort"
 	"strconv"
 
@@ -18,12 +19,17 @@ type CreateTableQuery struct {
 	temp        bool
 	ifNotExists bool
 	varchar     int
+	relationFKs bool
 
 	fks         []schema.QueryWithArgs
+	engine      schema.QueryWithArgs
 	partitionBy schema.QueryWithArgs
+	orderBy     schema.QueryWithArgs
 	tablespace  schema.QueryWithArgs
 }
 
+var _ Query = (*CreateTableQuery)(nil)
+
 func NewCreateTableQuery(db *DB) *CreateTableQuery {
 	q := &CreateTableQuery{
 		baseQuery: baseQuery{
@@ -63,6 +69,12 @@ func (q *CreateTableQuery) ModelTableExpr(query string, args ...interface{}) *Cr
 	return q
 }
 
+// TableSchema qualifies the model table name with the schema.
+func (q *CreateTableQuery) TableSchema(schema string) *CreateTableQuery {
+	q.tableSchema = schema
+	return q
+}
+
 //------------------------------------------------------------------------------
 
 func (q *CreateTableQuery) Temp() *CreateTableQuery {
@@ -85,6 +97,49 @@ func (q *CreateTableQuery) ForeignKey(query string, args ...interface{}) *Create
 	return q
 }
 
+// WithForeignKeys adds foreign key constraints for the belongs-to relations of the model,
+// for example, `FOREIGN KEY ("author_id") REFERENCES "authors" ("id") ON DELETE CASCADE`.
+// Use `on_delete` and `on_update` tag options on the relation field to set the actions.
+func (q *CreateTableQuery) WithForeignKeys() *CreateTableQuery {
+	q.relationFKs = true
+	return q
+}
+
+// Engine appends `ENGINE = ...` to the table definition, for example,
+// `Engine("InnoDB")` on MySQL or `Engine("MergeTree()")` on ClickHouse.
+func (q *CreateTableQuery) Engine(query string, args ...interface{}) *CreateTableQuery {
+	q.engine = schema.SafeQuery(query, args)
+	return q
+}
+
+// PartitionBy appends `PARTITION BY ...` to the table definition, for example,
+// `PartitionBy("RANGE (created_at)")`. SQLite does not support it.
+func (q *CreateTableQuery) PartitionBy(query string, args ...interface{}) *CreateTableQuery {
+	q.partitionBy = schema.SafeQuery(query, args)
+	return q
+}
+
+// OrderBy appends `ORDER BY ...` to the table definition, for example,
+// `OrderBy("(account_id, created_at)")` sets the sorting key. Only ClickHouse supports it.
+func (q *CreateTableQuery) OrderBy(query string, args ...interface{}) *CreateTableQuery {
+	q.orderBy = schema.SafeQuery(query, args)
+	return q
+}
+
+// TableSpace creates the table in the tablespace on Postgres and MySQL.
+func (q *CreateTableQuery) TableSpace(tablespace string) *CreateTableQuery {
+	q.tablespace = schema.UnsafeIdent(tablespace)
+	return q
+}
+
+func (q *CreateTableQuery) Operation() string {
+	return "CREATE TABLE"
+}
+
+func (q *CreateTableQuery) String() string {
+	return q.queryString(q)
+}
+
 func (q *CreateTableQuery) AppendQuery(fmter schema.Formatter, b []byte) (_ []byte, err error) {
 	if q.err != nil {
 		return nil, q.err
@@ -93,13 +148,13 @@ func (q *CreateTableQuery) AppendQuery(fmter schema.Formatter, b []byte) (_ []by
 		return nil, errNilModel
 	}
 
-	b = append(b, "CREATE "...)
+	b = fmter.AppendKeywords(b, "CREATE ")
 	if q.temp {
-		b = append(b, "TEMP "...)
+		b = fmter.AppendKeywords(b, "TEMP ")
 	}
-	b = append(b, "TABLE "...)
+	b = fmter.AppendKeywords(b, "TABLE ")
 	if q.ifNotExists {
-		b = append(b, "IF NOT EXISTS "...)
+		b = fmter.AppendKeywords(b, "IF NOT EXISTS ")
 	}
 	b, err = q.appendFirstTable(fmter, b)
 	if err != nil {
@@ -117,18 +172,18 @@ func (q *CreateTableQuery) AppendQuery(fmter schema.Formatter, b []byte) (_ []by
 		b = append(b, " "...)
 		b = q.appendSQLType(b, field)
 		if field.NotNull {
-			b = append(b, " NOT NULL"...)
+			b = fmter.AppendKeywords(b, " NOT NULL")
 		}
 		if q.db.features.Has(feature.AutoIncrement) && field.AutoIncrement {
-			b = append(b, " AUTO_INCREMENT"...)
+			b = fmter.AppendKeywords(b, " AUTO_INCREMENT")
 		}
 		if field.SQLDefault != "" {
-			b = append(b, " DEFAULT "...)
+			b = fmter.AppendKeywords(b, " DEFAULT ")
 			b = append(b, field.SQLDefault...)
 		}
 	}
 
-	b = q.appendPKConstraint(b, q.table.PKs)
+	b = q.appendPKConstraint(fmter, b, q.table.PKs)
 	b = q.appendUniqueConstraints(fmter, b)
 	b, err = q.appenFKConstraints(fmter, b)
 	if err != nil {
@@ -137,17 +192,62 @@ func (q *CreateTableQuery) AppendQuery(fmter schema.Formatter, b []byte) (_ []by
 
 	b = append(b, ")"...)
 
+	if !fmter.IsNop() {
+		if !q.engine.IsZero() && !fmter.HasFeature(feature.TableEngine) {
+			return nil, fmt.Errorf("bun: CREATE TABLE with ENGINE is not supported by %s",
+				fmter.Dialect().Name())
+		}
+		if !q.orderBy.IsZero() && !fmter.HasFeature(feature.TableOrderBy) {
+			return nil, fmt.Errorf("bun: CREATE TABLE with ORDER BY is not supported by %s",
+				fmter.Dialect().Name())
+		}
+		if !q.partitionBy.IsZero() && !fmter.HasFeature(feature.TablePartition) {
+			return nil, fmt.Errorf("bun: CREATE TABLE with PARTITION BY is not supported by %s",
+				fmter.Dialect().Name())
+		}
+		if !q.tablespace.IsZero() && !fmter.HasFeature(feature.TableSpace) {
+			return nil, fmt.Errorf("bun: CREATE TABLE with TABLESPACE is not supported by %s",
+				fmter.Dialect().Name())
+		}
+	}
+
+	// Some dialects, for example, MySQL require the table options, including TABLESPACE,
+	// before the partitioning.
+	tableSpaceFirst := fmter.HasFeature(feature.TableSpaceFirst)
+
+	if !q.engine.IsZero() {
+		b = fmter.AppendKeywords(b, " ENGINE = ")
+		b, err = q.engine.AppendQuery(fmter, b)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if tableSpaceFirst {
+		b, err = q.appendTableSpace(fmter, b)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	if !q.partitionBy.IsZero() {
-		b = append(b, " PARTITION BY "...)
+		b = fmter.AppendKeywords(b, " PARTITION BY ")
 		b, err = q.partitionBy.AppendQuery(fmter, b)
 		if err != nil {
 			return nil, err
 		}
 	}
 
-	if !q.tablespace.IsZero() {
-		b = append(b, " TABLESPACE "...)
-		b, err = q.tablespace.AppendQuery(fmter, b)
+	if !q.orderBy.IsZero() {
+		b = fmter.AppendKeywords(b, " ORDER BY ")
+		b, err = q.orderBy.AppendQuery(fmter, b)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if !tableSpaceFirst {
+		b, err = q.appendTableSpace(fmter, b)
 		if err != nil {
 			return nil, err
 		}
@@ -156,6 +256,14 @@ func (q *CreateTableQuery) AppendQuery(fmter schema.Formatter, b []byte) (_ []by
 	return b, nil
 }
 
+func (q *CreateTableQuery) appendTableSpace(fmter schema.Formatter, b []byte) (_ []byte, err error) {
+	if q.tablespace.IsZero() {
+		return b, nil
+	}
+	b = fmter.AppendKeywords(b, " TABLESPACE ")
+	return q.tablespace.AppendQuery(fmter, b)
+}
+
 func (q *CreateTableQuery) appendSQLType(b []byte, field *schema.Field) []byte {
 	if field.CreateTableSQLType != field.DiscoveredSQLType {
 		return append(b, field.CreateTableSQLType...)
@@ -192,12 +300,12 @@ func (q *CreateTableQuery) appendUniqueConstraint(
 	fmter schema.Formatter, b []byte, name string, fields []*schema.Field,
 ) []byte {
 	if name != "" {
-		b = append(b, ", CONSTRAINT "...)
+		b = fmter.AppendKeywords(b, ", CONSTRAINT ")
 		b = fmter.AppendIdent(b, name)
 	} else {
 		b = append(b, ","...)
 	}
-	b = append(b, " UNIQUE ("...)
+	b = fmter.AppendKeywords(b, " UNIQUE (")
 	b = appendColumns(b, "", fields)
 	b = append(b, ")"...)
 
@@ -207,8 +315,12 @@ func (q *CreateTableQuery) appendUniqueConstraint(
 func (q *CreateTableQuery) appenFKConstraints(
 	fmter schema.Formatter, b []byte,
 ) (_ []byte, err error) {
+	if q.relationFKs {
+		b = q.appendRelationFKs(fmter, b)
+	}
+
 	for _, fk := range q.fks {
-		b = append(b, ", FOREIGN KEY "...)
+		b = fmter.AppendKeywords(b, ", FOREIGN KEY ")
 		b, err = fk.AppendQuery(fmter, b)
 		if err != nil {
 			return nil, err
@@ -217,12 +329,47 @@ func (q *CreateTableQuery) appenFKConstraints(
 	return b, nil
 }
 
-func (q *CreateTableQuery) appendPKConstraint(b []byte, pks []*schema.Field) []byte {
+func (q *CreateTableQuery) appendRelationFKs(fmter schema.Formatter, b []byte) []byte {
+	names := make([]string, 0, len(q.table.Relations))
+	for name, rel := range q.table.Relations {
+		// Belongs-to relations have HasOneRelation type, so check the tag.
+		if rel.Field.Tag.Options["rel"] == "belongs-to" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		rel := q.table.Relations[name]
+
+		b = fmter.AppendKeywords(b, ", FOREIGN KEY (")
+		b = appendColumns(b, "", rel.BaseFields)
+		b = fmter.AppendKeywords(b, ") REFERENCES ")
+		b = append(b, rel.JoinTable.SQLName...)
+		b = append(b, " ("...)
+		b = appendColumns(b, "", rel.JoinFields)
+		b = append(b, ")"...)
+
+		if rel.Field.OnDelete != "" {
+			b = fmter.AppendKeywords(b, " ON DELETE ")
+			b = append(b, rel.Field.OnDelete...)
+		}
+		if rel.Field.OnUpdate != "" {
+			b = fmter.AppendKeywords(b, " ON UPDATE ")
+			b = append(b, rel.Field.OnUpdate...)
+		}
+	}
+	return b
+}
+
+func (q *CreateTableQuery) appendPKConstraint(
+	fmter schema.Formatter, b []byte, pks []*schema.Field,
+) []byte {
 	if len(pks) == 0 {
 		return b
 	}
 
-	b = append(b, ", PRIMARY KEY ("...)
+	b = fmter.AppendKeywords(b, ", PRIMARY KEY (")
 	b = appendColumns(b, "", pks)
 	b = append(b, ")"...)
 	return b
@@ -15,6 +15,8 @@ type DropTableQuery struct {
 	ifExists bool
 }
 
+var _ Query = (*DropTableQuery)(nil)
+
 func NewDropTableQuery(db *DB) *DropTableQuery {
 	q := &DropTableQuery{
 		baseQuery: baseQuery{
@@ -61,6 +63,14 @@ func (q *DropTableQuery) Restrict() *DropTableQuery {
 	return q
 }
 
+func (q *DropTableQuery) Operation() string {
+	return "DROP TABLE"
+}
+
+func (q *DropTableQuery) String() string {
+	return q.queryString(q)
+}
+
 //------------------------------------------------------------------------------
 
 func (q *DropTableQuery) AppendQuery(fmter schema.Formatter, b []byte) (_ []byte, err error) {
@@ -68,9 +78,9 @@ func (q *DropTableQuery) AppendQuery(fmter schema.Formatter, b []byte) (_ []byte
 		return nil, q.err
 	}
 
-	b = append(b, "DROP TABLE "...)
+	b = fmter.AppendKeywords(b, "DROP TABLE ")
 	if q.ifExists {
-		b = append(b, "IF EXISTS "...)
+		b = fmter.AppendKeywords(b, "IF EXISTS ")
 	}
 
 	b, err = q.appendTables(fmter, b)
@@ -3,6 +3,7 @@ package bun
 import (
 	"context"
 	"database/sql"
+	"fmt"
 
 	"github.com/uptrace/bun/dialect/feature"
 	"github.com/uptrace/bun/internal"
@@ -16,6 +17,8 @@ type TruncateTableQuery struct {
 	continueIdentity bool
 }
 
+var _ Query = (*TruncateTableQuery)(nil)
+
 func NewTruncateTableQuery(db *DB) *TruncateTableQuery {
 	q := &TruncateTableQuery{
 		baseQuery: baseQuery{
@@ -52,16 +55,38 @@ func (q *TruncateTableQuery) TableExpr(query string, args ...interface{}) *Trunc
 
 //------------------------------------------------------------------------------
 
+// RestartIdentity resets the sequences owned by the truncated tables, which is the default.
+// It appends RESTART IDENTITY on Postgres; MySQL resets AUTO_INCREMENT on TRUNCATE anyway.
+func (q *TruncateTableQuery) RestartIdentity() *TruncateTableQuery {
+	q.continueIdentity = false
+	return q
+}
+
 func (q *TruncateTableQuery) ContinueIdentity() *TruncateTableQuery {
 	q.continueIdentity = true
 	return q
 }
 
+// Cascade also truncates the tables that have foreign keys to the truncated tables,
+// which is the default. Only Postgres supports TRUNCATE ... CASCADE.
+func (q *TruncateTableQuery) Cascade() *TruncateTableQuery {
+	q.restrict = false
+	return q
+}
+
 func (q *TruncateTableQuery) Restrict() *TruncateTableQuery {
 	q.restrict = true
 	return q
 }
 
+func (q *TruncateTableQuery) Operation() string {
+	return "TRUNCATE TABLE"
+}
+
+func (q *TruncateTableQuery) String() string {
+	return q.queryString(q)
+}
+
 //------------------------------------------------------------------------------
 
 func (q *TruncateTableQuery) AppendQuery(
@@ -71,8 +96,14 @@ func (q *TruncateTableQuery) AppendQuery(
 		return nil, q.err
 	}
 
-	if !fmter.HasFeature(feature.TableTruncate) {
-		b = append(b, "DELETE FROM "...)
+	// Only Postgres truncates several tables in one statement.
+	if q.hasMultiTables() && !fmter.IsNop() && !fmter.HasFeature(feature.TableIdentity) {
+		return nil, fmt.Errorf("bun: %s does not support truncating multiple tables",
+			fmter.Dialect().Name())
+	}
+
+	if !fmter.IsNop() && !fmter.HasFeature(feature.TableTruncate) {
+		b = fmter.AppendKeywords(b, "DELETE FROM ")
 
 		b, err = q.appendTables(fmter, b)
 		if err != nil {
@@ -82,7 +113,7 @@ func (q *TruncateTableQuery) AppendQuery(
 		return b, nil
 	}
 
-	b = append(b, "TRUNCATE TABLE "...)
+	b = fmter.AppendKeywords(b, "TRUNCATE TABLE ")
 
 	b, err = q.appendTables(fmter, b)
 	if err != nil {
@@ -91,9 +122,9 @@ func (q *TruncateTableQuery) AppendQuery(
 
 	if q.db.features.Has(feature.TableIdentity) {
 		if q.continueIdentity {
-			b = append(b, " CONTINUE IDENTITY"...)
+			b = fmter.AppendKeywords(b, " CONTINUE IDENTITY")
 		} else {
-			b = append(b, " RESTART IDENTITY"...)
+			b = fmter.AppendKeywords(b, " RESTART IDENTITY")
 		}
 	}
 
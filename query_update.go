@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/uptrace/bun/dialect/feature"
 	"github.com/uptrace/bun/internal"
@@ -17,9 +18,12 @@ type UpdateQuery struct {
 	customValueQuery
 	setQuery
 
+	joins    []joinQuery
 	omitZero bool
 }
 
+var _ Query = (*UpdateQuery)(nil)
+
 func NewUpdateQuery(db *DB) *UpdateQuery {
 	q := &UpdateQuery{
 		whereBaseQuery: whereBaseQuery{
@@ -52,6 +56,33 @@ func (q *UpdateQuery) With(name string, query schema.QueryAppender) *UpdateQuery
 	return q
 }
 
+// Comment adds a key-value pair to the sqlcommenter comment appended to the query,
+// for example, `/*action='list',route='%2Fbooks'*/`.
+func (q *UpdateQuery) Comment(key, value string) *UpdateQuery {
+	q.addComment(key, value)
+	return q
+}
+
+// NamedArg sets the ?name placeholder used in the SET and WHERE clauses,
+// for example, `Set("updated_by = ?user_id")`. See SelectQuery.NamedArg.
+func (q *UpdateQuery) NamedArg(name string, value interface{}) *UpdateQuery {
+	q.addNamedArg(name, value)
+	return q
+}
+
+// Timeout sets the maximum duration of the query. See SelectQuery.Timeout.
+func (q *UpdateQuery) Timeout(d time.Duration) *UpdateQuery {
+	q.timeout = d
+	return q
+}
+
+// RequireIsolation makes the query fail unless it runs in a transaction
+// with at least the given isolation level, for example, sql.LevelSerializable.
+func (q *UpdateQuery) RequireIsolation(level sql.IsolationLevel) *UpdateQuery {
+	q.isolation = level
+	return q
+}
+
 //------------------------------------------------------------------------------
 
 func (q *UpdateQuery) Table(tables ...string) *UpdateQuery {
@@ -66,11 +97,25 @@ func (q *UpdateQuery) TableExpr(query string, args ...interface{}) *UpdateQuery
 	return q
 }
 
+// From adds the tables to the FROM clause, for example,
+// `UPDATE "books" AS "book" SET ... FROM "authors" AS "author" WHERE ...`.
+// MySQL lists the tables after the model table instead: `UPDATE books AS book, authors AS author SET ...`.
+// Use TableExpr for expressions.
+func (q *UpdateQuery) From(tables ...string) *UpdateQuery {
+	return q.Table(tables...)
+}
+
 func (q *UpdateQuery) ModelTableExpr(query string, args ...interface{}) *UpdateQuery {
 	q.modelTable = schema.SafeQuery(query, args)
 	return q
 }
 
+// TableSchema qualifies the model table name with the schema.
+func (q *UpdateQuery) TableSchema(schema string) *UpdateQuery {
+	q.tableSchema = schema
+	return q
+}
+
 //------------------------------------------------------------------------------
 
 func (q *UpdateQuery) Column(columns ...string) *UpdateQuery {
@@ -102,6 +147,37 @@ func (q *UpdateQuery) Value(column string, value string, args ...interface{}) *U
 
 //------------------------------------------------------------------------------
 
+// Join adds the join to the query, for example, `Join("JOIN authors AS a")`.
+// MySQL joins the model table: `UPDATE books AS book JOIN authors AS a ON ... SET ...`.
+// Other dialects can't join the updated table, so the joins are appended to the
+// tables added with From and the join conditions can only reference those tables.
+func (q *UpdateQuery) Join(join string, args ...interface{}) *UpdateQuery {
+	q.joins = append(q.joins, joinQuery{
+		join: schema.SafeQuery(join, args),
+	})
+	return q
+}
+
+func (q *UpdateQuery) JoinOn(cond string, args ...interface{}) *UpdateQuery {
+	return q.joinOn(cond, args, " AND ")
+}
+
+func (q *UpdateQuery) JoinOnOr(cond string, args ...interface{}) *UpdateQuery {
+	return q.joinOn(cond, args, " OR ")
+}
+
+func (q *UpdateQuery) joinOn(cond string, args []interface{}, sep string) *UpdateQuery {
+	if len(q.joins) == 0 {
+		q.setErr(errors.New("bun: query has no joins"))
+		return q
+	}
+	j := &q.joins[len(q.joins)-1]
+	j.on = append(j.on, schema.SafeQueryWithSep(cond, args, sep))
+	return q
+}
+
+//------------------------------------------------------------------------------
+
 func (q *UpdateQuery) WherePK() *UpdateQuery {
 	q.flags = q.flags.Set(wherePKFlag)
 	return q
@@ -117,6 +193,39 @@ func (q *UpdateQuery) WhereOr(query string, args ...interface{}) *UpdateQuery {
 	return q
 }
 
+// WhereColumn adds a condition comparing two columns, for example,
+// `WhereColumn("a.x", "=", "b.y")` produces `"a"."x" = "b"."y"`.
+func (q *UpdateQuery) WhereColumn(left, op, right string) *UpdateQuery {
+	q.addWhereColumn(left, op, right)
+	return q
+}
+
+// WhereIn adds a `column IN (...)` condition. The values can be a slice or a subquery,
+// for example, `WhereIn("id", db.NewSelect().Column("id").Table("staging"))`.
+func (q *UpdateQuery) WhereIn(column string, values interface{}) *UpdateQuery {
+	q.addWhereIn(column, values)
+	return q
+}
+
+// WhereExists adds an `EXISTS (subquery)` condition.
+func (q *UpdateQuery) WhereExists(subq *SelectQuery) *UpdateQuery {
+	q.addWhereExists("EXISTS", subq)
+	return q
+}
+
+// WhereNotExists adds a `NOT EXISTS (subquery)` condition.
+func (q *UpdateQuery) WhereNotExists(subq *SelectQuery) *UpdateQuery {
+	q.addWhereExists("NOT EXISTS", subq)
+	return q
+}
+
+// WhereIfNotZero adds a `column = value` condition unless the value is zero,
+// for example, an empty string. Use Where to filter by a zero value.
+func (q *UpdateQuery) WhereIfNotZero(column string, value interface{}) *UpdateQuery {
+	q.addWhereIfNotZero(column, value)
+	return q
+}
+
 func (q *UpdateQuery) WhereGroup(sep string, fn func(*UpdateQuery) *UpdateQuery) *UpdateQuery {
 	saved := q.where
 	q.where = nil
@@ -146,6 +255,7 @@ func (q *UpdateQuery) WhereAllWithDeleted() *UpdateQuery {
 // Returning adds a RETURNING clause to the query.
 //
 // To suppress the auto-generated RETURNING clause, use `Returning("NULL")`.
+// `Returning("NOTHING")` also appends `RETURNING NOTHING` on CockroachDB.
 func (q *UpdateQuery) Returning(query string, args ...interface{}) *UpdateQuery {
 	q.addReturning(schema.SafeQuery(query, args))
 	return q
@@ -158,21 +268,38 @@ func (q *UpdateQuery) hasReturning() bool {
 	return q.returningQuery.hasReturning()
 }
 
+func (q *UpdateQuery) Operation() string {
+	return "UPDATE"
+}
+
+func (q *UpdateQuery) String() string {
+	return q.queryString(q)
+}
+
 //------------------------------------------------------------------------------
 
 func (q *UpdateQuery) AppendQuery(fmter schema.Formatter, b []byte) (_ []byte, err error) {
 	if q.err != nil {
 		return nil, q.err
 	}
+	fmter = formatterWithModel(q.withNamedArgs(fmter), q)
 
 	withAlias := fmter.HasFeature(feature.UpdateMultiTable)
+	// The joins follow the model table in the multi-table syntax and the other tables
+	// in FROM otherwise. Without other tables, the joins are rewritten as tables in FROM.
+	joinTable := withAlias
+	joinsAsTables := !joinTable && len(q.joins) > 0 && !q.hasMultiTables()
+
+	if joinsAsTables && !joinTables(q.joins).ok() {
+		return nil, fmt.Errorf("bun: UPDATE with JOIN requires From on %s", fmter.Dialect().Name())
+	}
 
 	b, err = q.appendWith(fmter, b)
 	if err != nil {
 		return nil, err
 	}
 
-	b = append(b, "UPDATE "...)
+	b = fmter.AppendKeywords(b, "UPDATE ")
 
 	if withAlias {
 		b, err = q.appendTablesWithAlias(fmter, b)
@@ -183,21 +310,42 @@ func (q *UpdateQuery) AppendQuery(fmter schema.Formatter, b []byte) (_ []byte, e
 		return nil, err
 	}
 
+	if joinTable {
+		b, err = q.appendJoins(fmter, b)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	b, err = q.mustAppendSet(fmter, b)
 	if err != nil {
 		return nil, err
 	}
 
-	if !fmter.HasFeature(feature.UpdateMultiTable) {
-		b, err = q.appendOtherTables(fmter, b)
+	if joinsAsTables {
+		b, err = q.appendJoinsAsTables(fmter, b)
 		if err != nil {
 			return nil, err
 		}
-	}
+	} else {
+		if !withAlias {
+			b, err = q.appendOtherTables(fmter, b)
+			if err != nil {
+				return nil, err
+			}
+		}
 
-	b, err = q.mustAppendWhere(fmter, b, withAlias)
-	if err != nil {
-		return nil, err
+		if !joinTable {
+			b, err = q.appendJoins(fmter, b)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		b, err = q.mustAppendWhere(fmter, b, withAlias)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	if len(q.returning) > 0 {
@@ -211,7 +359,7 @@ func (q *UpdateQuery) AppendQuery(fmter schema.Formatter, b []byte) (_ []byte, e
 }
 
 func (q *UpdateQuery) mustAppendSet(fmter schema.Formatter, b []byte) (_ []byte, err error) {
-	b = append(b, " SET "...)
+	b = fmter.AppendKeywords(b, " SET ")
 
 	if len(q.set) > 0 {
 		return q.appendSet(fmter, b)
@@ -264,7 +412,7 @@ func (q *UpdateQuery) appendSetStruct(
 		b = append(b, " = "...)
 
 		if isTemplate {
-			b = append(b, '?')
+			b = f.AppendTemplateValue(fmter, b, model.strct)
 			continue
 		}
 
@@ -301,7 +449,7 @@ func (q *UpdateQuery) appendOtherTables(fmter schema.Formatter, b []byte) (_ []b
 		return b, nil
 	}
 
-	b = append(b, " FROM "...)
+	b = fmter.AppendKeywords(b, " FROM ")
 
 	b, err = q.whereBaseQuery.appendOtherTables(fmter, b)
 	if err != nil {
@@ -311,8 +459,37 @@ func (q *UpdateQuery) appendOtherTables(fmter schema.Formatter, b []byte) (_ []b
 	return b, nil
 }
 
+// appendJoinsAsTables appends `FROM tables WHERE ...` with the joined tables in FROM
+// and the JoinOn conditions in WHERE.
+func (q *UpdateQuery) appendJoinsAsTables(fmter schema.Formatter, b []byte) (_ []byte, err error) {
+	joins := joinTables(q.joins)
+
+	b = fmter.AppendKeywords(b, " FROM ")
+	b, err = joins.appendTables(fmter, b)
+	if err != nil {
+		return nil, err
+	}
+
+	where := joins.where(q.whereBaseQuery)
+	return where.mustAppendWhere(fmter, b, false)
+}
+
+func (q *UpdateQuery) appendJoins(fmter schema.Formatter, b []byte) (_ []byte, err error) {
+	for _, j := range q.joins {
+		b, err = j.AppendQuery(fmter, b)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
 //------------------------------------------------------------------------------
 
+// Bulk updates the rows of the slice model in one query joining the table
+// with the slice values by the primary key. Only the columns selected with Column
+// are updated if any, for example, `Model(&books).Bulk().Column("title")`.
+// The columns are resolved when the query is formatted.
 func (q *UpdateQuery) Bulk() *UpdateQuery {
 	model, ok := q.model.(*sliceTableModel)
 	if !ok {
@@ -323,49 +500,83 @@ func (q *UpdateQuery) Bulk() *UpdateQuery {
 	return q.With("_data", q.db.NewValues(model)).
 		Model(model).
 		TableExpr("_data").
-		Set(q.updateSliceSet(model)).
-		Where(q.updateSliceWhere(model))
+		Set("?", updateSliceSet{q: q, model: model}).
+		Where("?", updateSliceWhere{model: model})
 }
 
-func (q *UpdateQuery) updateSliceSet(model *sliceTableModel) string {
-	var b []byte
-	for i, field := range model.table.DataFields {
+// updateSliceSet appends `column = _data.column` for the columns updated by Bulk.
+type updateSliceSet struct {
+	q     *UpdateQuery
+	model *sliceTableModel
+}
+
+var _ schema.QueryAppender = updateSliceSet{}
+
+func (s updateSliceSet) AppendQuery(fmter schema.Formatter, b []byte) ([]byte, error) {
+	fields, err := s.q.getDataFields()
+	if err != nil {
+		return nil, err
+	}
+
+	for i, field := range fields {
 		if i > 0 {
 			b = append(b, ", "...)
 		}
-		if q.db.fmter.HasFeature(feature.UpdateMultiTable) {
-			b = append(b, model.table.SQLAlias...)
+		if fmter.HasFeature(feature.UpdateMultiTable) {
+			b = append(b, s.model.table.SQLAlias...)
 			b = append(b, '.')
 		}
 		b = append(b, field.SQLName...)
 		b = append(b, " = _data."...)
 		b = append(b, field.SQLName...)
 	}
-	return internal.String(b)
+	return b, nil
+}
+
+// updateSliceWhere appends `alias.pk = _data.pk` for the primary keys of the slice model.
+type updateSliceWhere struct {
+	model *sliceTableModel
 }
 
-func (db *UpdateQuery) updateSliceWhere(model *sliceTableModel) string {
-	var b []byte
-	for i, pk := range model.table.PKs {
+var _ schema.QueryAppender = updateSliceWhere{}
+
+func (w updateSliceWhere) AppendQuery(fmter schema.Formatter, b []byte) ([]byte, error) {
+	for i, pk := range w.model.table.PKs {
 		if i > 0 {
-			b = append(b, " AND "...)
+			b = fmter.AppendKeywords(b, " AND ")
 		}
-		b = append(b, model.table.SQLAlias...)
+		b = append(b, w.model.table.SQLAlias...)
 		b = append(b, '.')
 		b = append(b, pk.SQLName...)
 		b = append(b, " = _data."...)
 		b = append(b, pk.SQLName...)
 	}
-	return internal.String(b)
+	return b, nil
 }
 
 //------------------------------------------------------------------------------
 
+// Prepare creates a prepared statement replacing the query args with placeholders.
+// The args must be passed in the same order when executing the statement.
+func (q *UpdateQuery) Prepare(ctx context.Context) (Stmt, error) {
+	return q.prepare(ctx, q)
+}
+
 func (q *UpdateQuery) Exec(ctx context.Context, dest ...interface{}) (sql.Result, error) {
 	if q.table != nil {
 		if err := q.beforeUpdateHook(ctx); err != nil {
 			return nil, err
 		}
+		if err := q.beforeAppendModelHook(ctx, q); err != nil {
+			return nil, err
+		}
+		if err := q.beforeAppendQueryHook(ctx, q); err != nil {
+			return nil, err
+		}
+	}
+
+	if q.returningRequested() && !q.db.features.Has(feature.Returning) {
+		return nil, fmt.Errorf("bun: RETURNING is not supported by %s", q.db.dialect.Name())
 	}
 
 	queryBytes, err := q.AppendQuery(q.db.fmter, q.db.makeQueryBytes())
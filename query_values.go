@@ -16,7 +16,10 @@ type ValuesQuery struct {
 	withOrder bool
 }
 
-var _ schema.NamedArgAppender = (*ValuesQuery)(nil)
+var (
+	_ Query                   = (*ValuesQuery)(nil)
+	_ schema.NamedArgAppender = (*ValuesQuery)(nil)
+)
 
 func NewValuesQuery(db *DB, model interface{}) *ValuesQuery {
 	q := &ValuesQuery{
@@ -84,6 +87,14 @@ func (q *ValuesQuery) AppendColumns(fmter schema.Formatter, b []byte) (_ []byte,
 	return nil, fmt.Errorf("bun: Values does not support %T", q.model)
 }
 
+func (q *ValuesQuery) Operation() string {
+	return "VALUES"
+}
+
+func (q *ValuesQuery) String() string {
+	return q.queryString(q)
+}
+
 func (q *ValuesQuery) AppendQuery(fmter schema.Formatter, b []byte) (_ []byte, err error) {
 	if q.err != nil {
 		return nil, q.err
@@ -115,9 +126,9 @@ func (q *ValuesQuery) appendQuery(
 	b []byte,
 	fields []*schema.Field,
 ) (_ []byte, err error) {
-	b = append(b, "VALUES "...)
+	b = fmter.AppendKeywords(b, "VALUES ")
 	if q.db.features.Has(feature.ValuesRow) {
-		b = append(b, "ROW("...)
+		b = fmter.AppendKeywords(b, "ROW(")
 	} else {
 		b = append(b, '(')
 	}
@@ -140,7 +151,7 @@ func (q *ValuesQuery) appendQuery(
 			if i > 0 {
 				b = append(b, "), "...)
 				if q.db.features.Has(feature.ValuesRow) {
-					b = append(b, "ROW("...)
+					b = fmter.AppendKeywords(b, "ROW(")
 				} else {
 					b = append(b, '(')
 				}
@@ -184,7 +195,7 @@ func (q *ValuesQuery) appendValues(
 		}
 
 		if isTemplate {
-			b = append(b, '?')
+			b = f.AppendTemplateValue(fmter, b, indirect(strct))
 		} else {
 			b = f.AppendValue(fmter, b, indirect(strct))
 		}
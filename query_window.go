@@ -0,0 +1,163 @@
+package bun
+
+import (
+	"github.com/uptrace/bun/schema"
+)
+
+// Window builds a window definition, for example,
+// `PARTITION BY "account_id" ORDER BY "date" DESC ROWS UNBOUNDED PRECEDING`.
+type Window struct {
+	base      string
+	partition []schema.QueryWithArgs
+	order     []schema.QueryWithArgs
+	frame     schema.QueryWithArgs
+	alias     string
+}
+
+var _ schema.QueryAppender = (*Window)(nil)
+
+// Base makes the window refine the named window defined with SelectQuery.Window.
+// A window with only the base set is referenced as `OVER name`.
+func (w *Window) Base(name string) *Window {
+	w.base = name
+	return w
+}
+
+// PartitionBy adds the columns to the `PARTITION BY` clause.
+func (w *Window) PartitionBy(columns ...string) *Window {
+	for _, column := range columns {
+		w.partition = append(w.partition, schema.UnsafeIdent(column))
+	}
+	return w
+}
+
+// PartitionByExpr adds the expression to the `PARTITION BY` clause.
+func (w *Window) PartitionByExpr(query string, args ...interface{}) *Window {
+	w.partition = append(w.partition, schema.SafeQuery(query, args))
+	return w
+}
+
+// OrderBy adds the orders to the `ORDER BY` clause, for example, `date DESC`.
+func (w *Window) OrderBy(orders ...string) *Window {
+	for _, order := range orders {
+		w.order = append(w.order, parseOrder(order))
+	}
+	return w
+}
+
+// OrderByExpr adds the expression to the `ORDER BY` clause.
+func (w *Window) OrderByExpr(query string, args ...interface{}) *Window {
+	w.order = append(w.order, schema.SafeQuery(query, args))
+	return w
+}
+
+// Frame sets the frame clause, for example, `ROWS BETWEEN 1 PRECEDING AND CURRENT ROW`.
+func (w *Window) Frame(query string, args ...interface{}) *Window {
+	w.frame = schema.SafeQuery(query, args)
+	return w
+}
+
+// As sets the alias of the column added with SelectQuery.ColumnWindow.
+func (w *Window) As(alias string) *Window {
+	w.alias = alias
+	return w
+}
+
+func (w *Window) isBaseOnly() bool {
+	return w.base != "" && w.partition == nil && w.order == nil && w.frame.IsZero()
+}
+
+// AppendQuery appends the window definition without parentheses.
+func (w *Window) AppendQuery(fmter schema.Formatter, b []byte) (_ []byte, err error) {
+	start := len(b)
+
+	if w.base != "" {
+		b = fmter.AppendIdent(b, w.base)
+	}
+
+	if len(w.partition) > 0 {
+		if len(b) != start {
+			b = append(b, ' ')
+		}
+		b = fmter.AppendKeywords(b, "PARTITION BY ")
+		for i, part := range w.partition {
+			if i > 0 {
+				b = append(b, ", "...)
+			}
+			b, err = part.AppendQuery(fmter, b)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if len(w.order) > 0 {
+		if len(b) != start {
+			b = append(b, ' ')
+		}
+		b = fmter.AppendKeywords(b, "ORDER BY ")
+		for i, order := range w.order {
+			if i > 0 {
+				b = append(b, ", "...)
+			}
+			b, err = order.AppendQuery(fmter, b)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if !w.frame.IsZero() {
+		if len(b) != start {
+			b = append(b, ' ')
+		}
+		b, err = w.frame.AppendQuery(fmter, b)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return b, nil
+}
+
+//------------------------------------------------------------------------------
+
+// windowColumn is a window function call, for example, `row_number() OVER (...) AS alias`.
+type windowColumn struct {
+	fn     schema.QueryWithArgs
+	window *Window
+}
+
+var _ schema.QueryAppender = (*windowColumn)(nil)
+
+func (c *windowColumn) AppendQuery(fmter schema.Formatter, b []byte) (_ []byte, err error) {
+	b, err = c.fn.AppendQuery(fmter, b)
+	if err != nil {
+		return nil, err
+	}
+
+	b = fmter.AppendKeywords(b, " OVER ")
+	if c.window.isBaseOnly() {
+		b = fmter.AppendIdent(b, c.window.base)
+	} else {
+		b = append(b, '(')
+		b, err = c.window.AppendQuery(fmter, b)
+		if err != nil {
+			return nil, err
+		}
+		b = append(b, ')')
+	}
+
+	if c.window.alias != "" {
+		b = fmter.AppendKeywords(b, " AS ")
+		b = fmter.AppendIdent(b, c.window.alias)
+	}
+
+	return b, nil
+}
+
+// namedWindow is a window defined in the `WINDOW` clause.
+type namedWindow struct {
+	name   string
+	window *Window
+}
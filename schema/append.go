@@ -17,6 +17,9 @@ func FieldAppender(dialect Dialect, field *Field) AppenderFunc {
 	if field.Tag.HasOption("msgpack") {
 		return appendMsgpack
 	}
+	if field.Tag.HasOption("json") {
+		return AppendJSONValue
+	}
 
 	switch strings.ToUpper(field.UserSQLType) {
 	case sqltype.JSON, sqltype.JSONB:
@@ -38,7 +38,7 @@ type nopDialect struct {
 func newNopDialect() *nopDialect {
 	d := new(nopDialect)
 	d.tables = NewTables(d)
-	d.features = feature.Returning
+	d.features = feature.Returning | feature.RenameColumn
 	return d
 }
 
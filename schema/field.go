@@ -3,6 +3,7 @@ package schema
 import (
 	"fmt"
 	"reflect"
+	"time"
 
 	"github.com/uptrace/bun/dialect"
 	"github.com/uptrace/bun/internal/tagparser"
@@ -32,6 +33,10 @@ type Field struct {
 	NullZero      bool
 	AutoIncrement bool
 
+	// ScanLocation is the location scanned time values are converted to,
+	// for example, `bun:",location:UTC"`.
+	ScanLocation *time.Location
+
 	Append AppenderFunc
 	Scan   ScannerFunc
 	IsZero IsZeroerFunc
@@ -64,6 +69,22 @@ func (f *Field) HasZeroValue(v reflect.Value) bool {
 	return f.IsZero(v)
 }
 
+// AppendTemplateValue appends the `?` placeholder used by query templates
+// and collects the field value when the formatter collects params.
+func (f *Field) AppendTemplateValue(fmter Formatter, b []byte, strct reflect.Value) []byte {
+	if fmter.params == nil {
+		return append(b, '?')
+	}
+
+	var arg interface{}
+	if strct.IsValid() {
+		if fv, ok := fieldByIndex(strct, f.Index); ok {
+			arg = fv.Interface()
+		}
+	}
+	return fmter.AppendTemplateArg(b, arg)
+}
+
 func (f *Field) AppendValue(fmter Formatter, b []byte, strct reflect.Value) []byte {
 	fv, ok := fieldByIndex(strct, f.Index)
 	if !ok {
@@ -20,9 +20,10 @@ type namedArg struct {
 // TODO: try linked list
 type namedArgs []namedArg
 
+// Get returns the value of the named arg. Args added later take precedence.
 func (args namedArgs) Get(name string) (interface{}, bool) {
-	for _, arg := range args {
-		if arg.name == name {
+	for i := len(args) - 1; i >= 0; i-- {
+		if arg := args[i]; arg.name == name {
 			return arg.value, true
 		}
 	}
@@ -39,6 +40,11 @@ type Formatter struct {
 	dialect   Dialect
 	model     NamedArgAppender
 	namedArgs namedArgs
+	params    *[]interface{}
+	// err receives the first error of the args, which are otherwise embedded in the query.
+	err *error
+
+	lowerKeywords bool
 }
 
 func NewFormatter(dialect Dialect) Formatter {
@@ -71,6 +77,52 @@ func (f Formatter) Dialect() Dialect {
 	return f.dialect
 }
 
+// WithLowerKeywords returns a copy of the formatter that makes AppendKeywords
+// lowercase SQL keywords, for example, `select` instead of `SELECT`.
+func (f Formatter) WithLowerKeywords() Formatter {
+	f.lowerKeywords = true
+	return f
+}
+
+// AppendKeywords appends SQL generated by query builders, for example, ` WHERE `,
+// lowercasing uppercase words when the formatter is configured to do so.
+func (f Formatter) AppendKeywords(b []byte, keywords string) []byte {
+	if !f.lowerKeywords {
+		return append(b, keywords...)
+	}
+
+	for i := 0; i < len(keywords); {
+		if !isUpper(keywords[i]) {
+			b = append(b, keywords[i])
+			i++
+			continue
+		}
+
+		end := i + 1
+		for end < len(keywords) && isUpper(keywords[end]) {
+			end++
+		}
+		if end < len(keywords) && isLower(keywords[end]) {
+			// Mixed case words are not keywords.
+			b = append(b, keywords[i:end]...)
+		} else {
+			for _, c := range []byte(keywords[i:end]) {
+				b = append(b, c+'a'-'A')
+			}
+		}
+		i = end
+	}
+	return b
+}
+
+func isUpper(c byte) bool {
+	return c >= 'A' && c <= 'Z'
+}
+
+func isLower(c byte) bool {
+	return c >= 'a' && c <= 'z'
+}
+
 func (f Formatter) IdentQuote() byte {
 	return f.dialect.IdentQuote()
 }
@@ -83,6 +135,9 @@ func (f Formatter) AppendValue(b []byte, v reflect.Value) []byte {
 	if v.Kind() == reflect.Ptr && v.IsNil() {
 		return dialect.AppendNull(b)
 	}
+	if appender := f.dialect.Tables().TypeAppender(v.Type()); appender != nil {
+		return appender(f, b, v)
+	}
 	appender := f.dialect.Appender(v.Type())
 	return appender(f, b, v)
 }
@@ -110,25 +165,54 @@ func (f Formatter) WithArg(name string, value interface{}) Formatter {
 	return clone
 }
 
+// WithParams returns a formatter that appends query args as driver placeholders,
+// for example, `$1` or `?`, and collects the args into the params.
+func (f Formatter) WithParams(params *[]interface{}) Formatter {
+	clone := f.clone()
+	clone.params = params
+	return clone
+}
+
+// appendQueryErr is like AppendQuery, but returns the first error of the args
+// instead of embedding it in the query.
+func (f Formatter) appendQueryErr(dst []byte, query string, args ...interface{}) ([]byte, error) {
+	var err error
+	f.err = &err
+	dst = f.AppendQuery(dst, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return dst, nil
+}
+
 func (f Formatter) Arg(name string) interface{} {
 	value, _ := f.namedArgs.Get(name)
 	return value
 }
 
 func (f Formatter) FormatQuery(query string, args ...interface{}) string {
-	if f.IsNop() || (args == nil && f.hasNoArgs()) || strings.IndexByte(query, '?') == -1 {
+	if f.isVerbatim(query, args) {
 		return query
 	}
 	return internal.String(f.AppendQuery(nil, query, args...))
 }
 
 func (f Formatter) AppendQuery(dst []byte, query string, args ...interface{}) []byte {
-	if f.IsNop() || (args == nil && f.hasNoArgs()) || strings.IndexByte(query, '?') == -1 {
+	if f.isVerbatim(query, args) {
 		return append(dst, query...)
 	}
 	return f.append(dst, parser.NewString(query), args)
 }
 
+// isVerbatim reports whether the query is appended as is. The nop formatter keeps
+// the placeholders unless it collects the args into the params.
+func (f Formatter) isVerbatim(query string, args []interface{}) bool {
+	if f.IsNop() && (f.params == nil || args == nil) {
+		return true
+	}
+	return (args == nil && f.hasNoArgs()) || strings.IndexByte(query, '?') == -1
+}
+
 func (f Formatter) hasNoArgs() bool {
 	return f.namedArgs == nil && f.model == nil
 }
@@ -139,7 +223,10 @@ func (f Formatter) append(dst []byte, p *parser.Parser, args []interface{}) []by
 		var ok bool
 		namedArgs, ok = args[0].(NamedArgAppender)
 		if !ok {
-			namedArgs, _ = newStructArgs(f, args[0])
+			// Don't store a nil *structArgs in the interface.
+			if structArgs, ok := newStructArgs(f, args[0]); ok {
+				namedArgs = structArgs
+			}
 		}
 	}
 
@@ -159,6 +246,10 @@ func (f Formatter) append(dst []byte, p *parser.Parser, args []interface{}) []by
 
 		name, numeric := p.ReadIdentifier()
 		if name != "" {
+			if f.IsNop() {
+				goto restore_arg
+			}
+
 			if numeric {
 				idx, err := strconv.Atoi(name)
 				if err != nil {
@@ -217,18 +308,52 @@ func (f Formatter) append(dst []byte, p *parser.Parser, args []interface{}) []by
 }
 
 func (f Formatter) appendArg(b []byte, arg interface{}) []byte {
+	if f.IsNop() {
+		// Keep the placeholder of the query template and collect the arg.
+		return f.appendParam(b, arg)
+	}
+
 	switch arg := arg.(type) {
 	case QueryAppender:
+		errPtr := f.err
+		f.err = nil
 		bb, err := arg.AppendQuery(f, b)
 		if err != nil {
+			if errPtr != nil && *errPtr == nil {
+				*errPtr = err
+			}
 			return dialect.AppendError(b, err)
 		}
 		return bb
 	default:
+		if f.params != nil {
+			return f.appendParam(b, arg)
+		}
+		if appender := f.dialect.Tables().TypeAppender(reflect.TypeOf(arg)); appender != nil {
+			return appender(f, b, reflect.ValueOf(arg))
+		}
 		return f.dialect.Append(f, b, arg)
 	}
 }
 
+// AppendTemplateArg appends the `?` placeholder used by query templates
+// and collects the arg when the formatter collects params.
+func (f Formatter) AppendTemplateArg(b []byte, arg interface{}) []byte {
+	if f.params != nil {
+		*f.params = append(*f.params, arg)
+	}
+	return append(b, '?')
+}
+
+func (f Formatter) appendParam(b []byte, arg interface{}) []byte {
+	*f.params = append(*f.params, arg)
+	if f.HasFeature(feature.DollarPlaceholder) {
+		b = append(b, '$')
+		return strconv.AppendInt(b, int64(len(*f.params)), 10)
+	}
+	return append(b, '?')
+}
+
 //------------------------------------------------------------------------------
 
 type NamedArgAppender interface {
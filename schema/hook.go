@@ -5,6 +5,7 @@ import (
 	"reflect"
 )
 
+// BeforeScanHook is called before each row is scanned into the model.
 type BeforeScanHook interface {
 	BeforeScan(context.Context) error
 }
@@ -13,6 +14,8 @@ var beforeScanHookType = reflect.TypeOf((*BeforeScanHook)(nil)).Elem()
 
 //------------------------------------------------------------------------------
 
+// AfterScanHook is called after each row is scanned into the model,
+// for example, to decrypt or otherwise transform the scanned values.
 type AfterScanHook interface {
 	AfterScan(context.Context) error
 }
@@ -7,10 +7,12 @@ import (
 	"net"
 	"reflect"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/vmihailenco/msgpack/v5"
 
+	"github.com/uptrace/bun/dialect/sqltype"
 	"github.com/uptrace/bun/extra/bunjson"
 	"github.com/uptrace/bun/internal"
 )
@@ -54,6 +56,18 @@ func FieldScanner(dialect Dialect, field *Field) ScannerFunc {
 	if field.Tag.HasOption("json_use_number") {
 		return scanJSONUseNumber
 	}
+	if field.Tag.HasOption("json") {
+		return scanJSON
+	}
+
+	switch strings.ToUpper(field.UserSQLType) {
+	case sqltype.JSON, sqltype.JSONB:
+		// Dialects scan interface{} as is, for example, as []byte.
+		if field.StructField.Type.Kind() == reflect.Interface {
+			return scanJSON
+		}
+	}
+
 	return dialect.Scanner(field.StructField.Type)
 }
 
@@ -88,6 +102,10 @@ func Scanner(typ reflect.Type) ScannerFunc {
 		return scanJSONRawMessage
 	}
 
+	if kind == reflect.Slice && typ.Elem().Kind() == reflect.Uint8 {
+		return scanBytes
+	}
+
 	return scanners[kind]
 }
 
@@ -332,6 +350,22 @@ func scanJSONRawMessage(dest reflect.Value, src interface{}) error {
 	return nil
 }
 
+func scanBytes(dest reflect.Value, src interface{}) error {
+	if src == nil {
+		dest.SetBytes(nil)
+		return nil
+	}
+
+	b, err := toBytes(src)
+	if err != nil {
+		return err
+	}
+
+	// The driver may reuse the src buffer.
+	dest.SetBytes(append([]byte(nil), b...))
+	return nil
+}
+
 func addrScanner(fn ScannerFunc) ScannerFunc {
 	return func(dest reflect.Value, src interface{}) error {
 		if !dest.CanAddr() {
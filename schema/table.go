@@ -10,6 +10,7 @@ import (
 
 	"github.com/jinzhu/inflection"
 
+	"github.com/uptrace/bun/dialect/sqltype"
 	"github.com/uptrace/bun/internal"
 	"github.com/uptrace/bun/internal/tagparser"
 )
@@ -182,7 +183,7 @@ func (t *Table) fieldByGoName(name string) *Field {
 func (t *Table) initFields() {
 	t.Fields = make([]*Field, 0, t.Type.NumField())
 	t.FieldMap = make(map[string]*Field, t.Type.NumField())
-	t.addFields(t.Type, nil)
+	t.addFields(t.Type, "", nil)
 
 	if len(t.PKs) > 0 {
 		return
@@ -204,7 +205,9 @@ func (t *Table) initFields() {
 	}
 }
 
-func (t *Table) addFields(typ reflect.Type, baseIndex []int) {
+func (t *Table) addFields(typ reflect.Type, prefix string, baseIndex []int) {
+	var baseModelField *reflect.StructField
+
 	for i := 0; i < typ.NumField(); i++ {
 		f := typ.Field(i)
 
@@ -218,7 +221,7 @@ func (t *Table) addFields(typ reflect.Type, baseIndex []int) {
 			}
 			if f.Name == "BaseModel" && f.Type == baseModelType {
 				if len(index) == 0 {
-					t.processBaseModelField(f)
+					baseModelField = &f
 				}
 				continue
 			}
@@ -227,27 +230,50 @@ func (t *Table) addFields(typ reflect.Type, baseIndex []int) {
 			if fieldType.Kind() != reflect.Struct {
 				continue
 			}
-			t.addFields(fieldType, append(index, f.Index...))
 
 			tag := tagparser.Parse(f.Tag.Get("bun"))
-			if _, inherit := tag.Options["inherit"]; inherit {
-				embeddedTable := t.dialect.Tables().Ref(fieldType)
-				t.TypeName = embeddedTable.TypeName
-				t.SQLName = embeddedTable.SQLName
-				t.SQLNameForSelects = embeddedTable.SQLNameForSelects
-				t.Alias = embeddedTable.Alias
-				t.SQLAlias = embeddedTable.SQLAlias
-				t.ModelName = embeddedTable.ModelName
+			t.addFields(fieldType, prefix+tag.Options["embed"], append(index, f.Index...))
+
+			if tag.HasOption("extend") || tag.HasOption("inherit") {
+				t.extend(t.dialect.Tables().Ref(fieldType))
 			}
 
 			continue
 		}
 
-		field := t.newField(f, index)
+		if embedPrefix, ok := tagparser.Parse(f.Tag.Get("bun")).Options["embed"]; ok {
+			fieldType := indirectType(f.Type)
+			if fieldType.Kind() != reflect.Struct {
+				panic(fmt.Errorf("bun: %s.%s has embed option, but it is not a struct",
+					t.TypeName, f.Name))
+			}
+			if f.PkgPath == "" {
+				t.addFields(fieldType, prefix+embedPrefix, append(index, f.Index...))
+			}
+			continue
+		}
+
+		field := t.newField(f, prefix, index)
 		if field != nil {
 			t.addField(field)
 		}
 	}
+
+	// Process the BaseModel last so its table name overrides the extended model.
+	if baseModelField != nil {
+		t.processBaseModelField(*baseModelField)
+	}
+}
+
+// extend makes the table use the name and the alias of the embedded table.
+func (t *Table) extend(embeddedTable *Table) {
+	t.TypeName = embeddedTable.TypeName
+	t.Name = embeddedTable.Name
+	t.SQLName = embeddedTable.SQLName
+	t.SQLNameForSelects = embeddedTable.SQLNameForSelects
+	t.Alias = embeddedTable.Alias
+	t.SQLAlias = embeddedTable.SQLAlias
+	t.ModelName = embeddedTable.ModelName
 }
 
 func (t *Table) processBaseModelField(f reflect.StructField) {
@@ -281,7 +307,7 @@ func (t *Table) processBaseModelField(f reflect.StructField) {
 }
 
 //nolint
-func (t *Table) newField(f reflect.StructField, index []int) *Field {
+func (t *Table) newField(f reflect.StructField, prefix string, index []int) *Field {
 	tag := tagparser.Parse(f.Tag.Get("bun"))
 
 	if f.PkgPath != "" {
@@ -307,6 +333,7 @@ func (t *Table) newField(f reflect.StructField, index []int) *Field {
 	if !skip && tag.Name != "" {
 		sqlName = tag.Name
 	}
+	sqlName = prefix + sqlName
 
 	index = append(index, f.Index...)
 	if field := t.fieldWithLock(sqlName); field != nil {
@@ -352,9 +379,29 @@ func (t *Table) newField(f reflect.StructField, index []int) *Field {
 	if s, ok := field.Tag.Options["type"]; ok {
 		field.UserSQLType = s
 	}
+	if s, ok := tag.Options["on_delete"]; ok {
+		field.OnDelete = t.fkAction(f, "on_delete", s)
+	}
+	if s, ok := tag.Options["on_update"]; ok {
+		field.OnUpdate = t.fkAction(f, "on_update", s)
+	}
+	if s, ok := tag.Options["location"]; ok {
+		loc, err := time.LoadLocation(s)
+		if err != nil {
+			panic(fmt.Errorf("bun: %s.%s has invalid location=%q: %w", t.TypeName, f.Name, s, err))
+		}
+		field.ScanLocation = loc
+	}
 	field.DiscoveredSQLType = DiscoverSQLType(field.IndirectType)
+	switch {
+	case tag.HasOption("json"):
+		field.DiscoveredSQLType = sqltype.JSON
+	case tag.HasOption("msgpack"):
+		field.DiscoveredSQLType = sqltype.Blob
+	}
 	field.Append = t.dialect.FieldAppender(field)
 	field.Scan = FieldScanner(t.dialect, field)
+	t.dialect.Tables().applyCustomType(field)
 	field.IsZero = FieldZeroChecker(field)
 
 	if v, ok := tag.Options["alt"]; ok {
@@ -377,6 +424,16 @@ func (t *Table) newField(f reflect.StructField, index []int) *Field {
 	return field
 }
 
+// fkAction validates the foreign key action from the on_delete or on_update tag option.
+func (t *Table) fkAction(f reflect.StructField, option, s string) string {
+	action := strings.ToUpper(strings.Join(strings.Fields(s), " "))
+	switch action {
+	case "CASCADE", "RESTRICT", "SET NULL", "SET DEFAULT", "NO ACTION":
+		return action
+	}
+	panic(fmt.Errorf("bun: %s.%s has invalid %s=%q", t.TypeName, f.Name, option, s))
+}
+
 func (t *Table) initInlines() {
 	for _, f := range t.skippedFields {
 		if f.IndirectType.Kind() == reflect.Struct {
@@ -436,6 +493,38 @@ func (t *Table) initRelation(field *Field, rel string) {
 	}
 }
 
+// AdHocRelation returns a has-one relation without join fields for the struct field
+// with the name, for example, a field with the `bun:"-"` tag, so it can be joined
+// using custom conditions. It returns nil if the field is not a struct or a struct pointer.
+func (t *Table) AdHocRelation(name string) *Relation {
+	sf, ok := t.Type.FieldByName(name)
+	if !ok || sf.PkgPath != "" {
+		return nil
+	}
+
+	typ := indirectType(sf.Type)
+	if typ.Kind() != reflect.Struct {
+		return nil
+	}
+
+	sqlName := internal.Underscore(sf.Name)
+	field := &Field{
+		StructField:  sf,
+		IndirectType: typ,
+		Index:        sf.Index,
+
+		Name:    sqlName,
+		GoName:  sf.Name,
+		SQLName: t.quoteIdent(sqlName),
+	}
+
+	return &Relation{
+		Type:      HasOneRelation,
+		Field:     field,
+		JoinTable: t.dialect.Tables().Ref(typ),
+	}
+}
+
 func (t *Table) addRelation(rel *Relation) {
 	if t.Relations == nil {
 		t.Relations = make(map[string]*Relation)
@@ -514,6 +603,7 @@ func (t *Table) hasOneRelation(field *Field) *Relation {
 	}
 
 	joinTable := t.dialect.Tables().Ref(field.IndirectType)
+	_, isPolymorphic := field.Tag.Options["polymorphic"]
 	rel := &Relation{
 		Type:      BelongsToRelation,
 		Field:     field,
@@ -523,6 +613,11 @@ func (t *Table) hasOneRelation(field *Field) *Relation {
 	if join, ok := field.Tag.Options["join"]; ok {
 		baseColumns, joinColumns := parseRelationJoin(join)
 		for i, baseColumn := range baseColumns {
+			if isPolymorphic && baseColumn == "type" {
+				t.setPolymorphicField(rel, joinColumns[i])
+				continue
+			}
+
 			if f := t.fieldWithLock(baseColumn); f != nil {
 				rel.BaseFields = append(rel.BaseFields, f)
 			} else {
@@ -542,11 +637,17 @@ func (t *Table) hasOneRelation(field *Field) *Relation {
 				))
 			}
 		}
+		if isPolymorphic && rel.PolymorphicField == nil {
+			t.setPolymorphicField(rel, "")
+		}
 		return rel
 	}
 
 	rel.BaseFields = t.PKs
 	fkPrefix := internal.Underscore(t.ModelName) + "_"
+	if isPolymorphic {
+		t.setPolymorphicField(rel, fkPrefix+"type")
+	}
 	for _, pk := range t.PKs {
 		fkName := fkPrefix + pk.Name
 		if f := joinTable.fieldWithLock(fkName); f != nil {
@@ -580,13 +681,12 @@ func (t *Table) hasManyRelation(field *Field) *Relation {
 	}
 
 	joinTable := t.dialect.Tables().Ref(indirectType(field.IndirectType.Elem()))
-	polymorphicValue, isPolymorphic := field.Tag.Options["polymorphic"]
+	_, isPolymorphic := field.Tag.Options["polymorphic"]
 	rel := &Relation{
 		Type:      HasManyRelation,
 		Field:     field,
 		JoinTable: joinTable,
 	}
-	var polymorphicColumn string
 
 	if join, ok := field.Tag.Options["join"]; ok {
 		baseColumns, joinColumns := parseRelationJoin(join)
@@ -594,7 +694,7 @@ func (t *Table) hasManyRelation(field *Field) *Relation {
 			joinColumn := joinColumns[i]
 
 			if isPolymorphic && baseColumn == "type" {
-				polymorphicColumn = joinColumn
+				t.setPolymorphicField(rel, joinColumn)
 				continue
 			}
 
@@ -620,7 +720,7 @@ func (t *Table) hasManyRelation(field *Field) *Relation {
 		rel.BaseFields = t.PKs
 		fkPrefix := internal.Underscore(t.ModelName) + "_"
 		if isPolymorphic {
-			polymorphicColumn = fkPrefix + "type"
+			t.setPolymorphicField(rel, fkPrefix+"type")
 		}
 
 		for _, pk := range t.PKs {
@@ -643,24 +743,32 @@ func (t *Table) hasManyRelation(field *Field) *Relation {
 		}
 	}
 
-	if isPolymorphic {
-		rel.PolymorphicField = joinTable.fieldWithLock(polymorphicColumn)
-		if rel.PolymorphicField == nil {
-			panic(fmt.Errorf(
-				"bun: %s has-many %s: %s must have polymorphic column %s",
-				t.TypeName, field.GoName, joinTable.TypeName, polymorphicColumn,
-			))
-		}
-
-		if polymorphicValue == "" {
-			polymorphicValue = t.ModelName
-		}
-		rel.PolymorphicValue = polymorphicValue
+	if isPolymorphic && rel.PolymorphicField == nil {
+		t.setPolymorphicField(rel, "")
 	}
 
 	return rel
 }
 
+// setPolymorphicField sets the join table column that stores the type of the parent
+// model and the type value, which is the `polymorphic:value` tag option or the model name.
+func (t *Table) setPolymorphicField(rel *Relation, column string) {
+	rel.PolymorphicField = rel.JoinTable.fieldWithLock(column)
+	if rel.PolymorphicField == nil {
+		panic(fmt.Errorf(
+			"bun: %s %s %s: %s must have polymorphic column %s "+
+				"(to override, use join:type=join_column tag on the field %s)",
+			t.TypeName, rel.Field.Tag.Options["rel"], rel.Field.GoName,
+			rel.JoinTable.TypeName, column, rel.Field.GoName,
+		))
+	}
+
+	rel.PolymorphicValue = rel.Field.Tag.Options["polymorphic"]
+	if rel.PolymorphicValue == "" {
+		rel.PolymorphicValue = t.ModelName
+	}
+}
+
 func (t *Table) m2mRelation(field *Field) *Relation {
 	if field.IndirectType.Kind() != reflect.Slice {
 		panic(fmt.Errorf(
@@ -818,7 +926,9 @@ func isKnownFieldOption(name string) bool {
 		"type",
 		"array",
 		"hstore",
+		"lowcardinality",
 		"composite",
+		"json",
 		"json_use_number",
 		"msgpack",
 		"notnull",
@@ -826,6 +936,10 @@ func isKnownFieldOption(name string) bool {
 		"default",
 		"unique",
 		"soft_delete",
+		"location",
+		"on_delete",
+		"on_update",
+		"embed",
 
 		"pk",
 		"autoincrement",
@@ -3,7 +3,11 @@ package schema
 import (
 	"fmt"
 	"reflect"
+	"strings"
 	"sync"
+	"sync/atomic"
+
+	"github.com/uptrace/bun/dialect"
 )
 
 type tableInProgress struct {
@@ -37,10 +41,26 @@ func (inp *tableInProgress) init2() bool {
 	return inited
 }
 
+// CustomType describes how to append and scan the values of a Go or SQL type
+// registered with Tables.RegisterType or Tables.RegisterSQLType.
+type CustomType struct {
+	// SQLType is the column type used when the field has no type tag,
+	// for example, `NUMERIC`. It is ignored by RegisterSQLType.
+	SQLType string
+	// Append appends the value, which is never a pointer.
+	Append AppenderFunc
+	// Scan scans the src into the dest, which is never a pointer.
+	Scan ScannerFunc
+}
+
 type Tables struct {
 	dialect Dialect
 	tables  sync.Map
 
+	types    sync.Map // map[reflect.Type]*CustomType
+	sqlTypes sync.Map // map[string]*CustomType
+	numTypes int32
+
 	mu         sync.RWMutex
 	inProgress map[reflect.Type]*tableInProgress
 }
@@ -52,6 +72,99 @@ func NewTables(dialect Dialect) *Tables {
 	}
 }
 
+// RegisterType registers the funcs used to append and scan the values of the Go type,
+// for example, decimal.Decimal, so the type does not need to implement
+// driver.Valuer and sql.Scanner. Pointers to the type are handled automatically.
+// Register types before the models that use them.
+func (t *Tables) RegisterType(typ reflect.Type, ct CustomType) {
+	if _, loaded := t.types.LoadOrStore(typ, &ct); loaded {
+		t.types.Store(typ, &ct)
+		return
+	}
+	atomic.AddInt32(&t.numTypes, 1)
+}
+
+// RegisterSQLType registers the funcs used to append and scan the fields
+// with the SQL type, for example, `bun:"type:money"`.
+// Register types before the models that use them.
+func (t *Tables) RegisterSQLType(sqlType string, append AppenderFunc, scan ScannerFunc) {
+	t.sqlTypes.Store(strings.ToUpper(sqlType), &CustomType{
+		Append: append,
+		Scan:   scan,
+	})
+}
+
+// TypeAppender returns the appender registered with RegisterType or nil.
+func (t *Tables) TypeAppender(typ reflect.Type) AppenderFunc {
+	if ct := t.customType(typ); ct != nil && ct.Append != nil {
+		return indirectAppender(typ, ct.Append)
+	}
+	return nil
+}
+
+// TypeScanner returns the scanner registered with RegisterType or nil.
+func (t *Tables) TypeScanner(typ reflect.Type) ScannerFunc {
+	if ct := t.customType(typ); ct != nil && ct.Scan != nil {
+		return indirectScanner(typ, ct.Scan)
+	}
+	return nil
+}
+
+func (t *Tables) customType(typ reflect.Type) *CustomType {
+	// Most apps don't register types, so don't look up every arg type.
+	if typ == nil || atomic.LoadInt32(&t.numTypes) == 0 {
+		return nil
+	}
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if v, ok := t.types.Load(typ); ok {
+		return v.(*CustomType)
+	}
+	return nil
+}
+
+func (t *Tables) applyCustomType(field *Field) {
+	ct := t.customType(field.IndirectType)
+	if field.UserSQLType != "" {
+		if v, ok := t.sqlTypes.Load(strings.ToUpper(field.UserSQLType)); ok {
+			ct = v.(*CustomType)
+		}
+	}
+	if ct == nil {
+		return
+	}
+
+	if field.UserSQLType == "" {
+		field.UserSQLType = ct.SQLType
+	}
+	if ct.Append != nil {
+		field.Append = indirectAppender(field.StructField.Type, ct.Append)
+	}
+	if ct.Scan != nil {
+		field.Scan = indirectScanner(field.StructField.Type, ct.Scan)
+	}
+}
+
+func indirectAppender(typ reflect.Type, fn AppenderFunc) AppenderFunc {
+	if typ.Kind() != reflect.Ptr {
+		return fn
+	}
+	return func(fmter Formatter, b []byte, v reflect.Value) []byte {
+		if v.IsNil() {
+			return dialect.AppendNull(b)
+		}
+		return fn(fmter, b, v.Elem())
+	}
+}
+
+func indirectScanner(typ reflect.Type, fn ScannerFunc) ScannerFunc {
+	if typ.Kind() != reflect.Ptr {
+		return fn
+	}
+	return ptrScanner(fn)
+}
+
 func (t *Tables) Register(models ...interface{}) {
 	for _, model := range models {
 		_ = t.Get(reflect.TypeOf(model).Elem())
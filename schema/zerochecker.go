@@ -13,6 +13,23 @@ type isZeroer interface {
 
 type IsZeroerFunc func(reflect.Value) bool
 
+// IsZero reports whether the value is zero using the same rules as the nullzero option,
+// for example, an empty string, 0, or a zero time.Time. A non-nil pointer is not zero.
+func IsZero(v interface{}) bool {
+	if v == nil {
+		return true
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr {
+		// Make the value addressable for IsZero methods with pointer receivers.
+		addr := reflect.New(rv.Type()).Elem()
+		addr.Set(rv)
+		rv = addr
+	}
+	return zeroChecker(rv.Type())(rv)
+}
+
 func FieldZeroChecker(field *Field) IsZeroerFunc {
 	return zeroChecker(field.IndirectType)
 }
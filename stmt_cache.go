@@ -0,0 +1,243 @@
+package bun
+
+import (
+	"container/list"
+	"context"
+	"database/sql"
+	"sync"
+
+	"github.com/uptrace/bun/internal"
+	"github.com/uptrace/bun/schema"
+)
+
+// WithStmtCache enables a cache of up to size prepared statements.
+// Queries built with query builders are prepared with driver placeholders for the args,
+// for example, `WHERE id = $1`, so queries that differ only by the args share a statement.
+// Only SELECT, INSERT, UPDATE, and DELETE queries consisting of a single statement are cached.
+// Queries executed using a Conn are not cached.
+func WithStmtCache(size int) DBOption {
+	return func(db *DB) {
+		if size > 0 {
+			db.stmtCache = newStmtCache(size)
+		}
+	}
+}
+
+type stmtCacheEntry struct {
+	query string
+	stmt  *sql.Stmt
+
+	refs    int
+	evicted bool
+}
+
+// stmtCache is an LRU cache of prepared statements. The evicted statements
+// are closed once they are no longer used.
+type stmtCache struct {
+	mu    sync.Mutex
+	size  int
+	ll    *list.List
+	items map[string]*list.Element
+}
+
+func newStmtCache(size int) *stmtCache {
+	return &stmtCache{
+		size:  size,
+		ll:    list.New(),
+		items: make(map[string]*list.Element, size),
+	}
+}
+
+// get returns the cached entry for the query, if any. The caller must release
+// the returned entry when it is done with the stmt.
+func (c *stmtCache) get(query string) *stmtCacheEntry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[query]; ok {
+		c.ll.MoveToFront(el)
+		entry := el.Value.(*stmtCacheEntry)
+		entry.refs++
+		return entry
+	}
+	return nil
+}
+
+// add adds the stmt to the cache and returns the cached entry, which holds a different
+// stmt if another goroutine has prepared the same query first. The caller must release
+// the returned entry when it is done with the stmt.
+func (c *stmtCache) add(query string, stmt *sql.Stmt) *stmtCacheEntry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[query]; ok {
+		_ = stmt.Close()
+		c.ll.MoveToFront(el)
+		entry := el.Value.(*stmtCacheEntry)
+		entry.refs++
+		return entry
+	}
+
+	entry := &stmtCacheEntry{query: query, stmt: stmt, refs: 1}
+	c.items[query] = c.ll.PushFront(entry)
+
+	for c.ll.Len() > c.size {
+		evicted := c.ll.Remove(c.ll.Back()).(*stmtCacheEntry)
+		delete(c.items, evicted.query)
+		evicted.evicted = true
+		if evicted.refs == 0 {
+			_ = evicted.stmt.Close()
+		}
+	}
+
+	return entry
+}
+
+// release closes the stmt of the evicted entry when it is no longer used.
+func (c *stmtCache) release(entry *stmtCacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry.refs--
+	if entry.evicted && entry.refs == 0 {
+		_ = entry.stmt.Close()
+	}
+}
+
+// cachedStmt is a prepared statement with the args for the driver placeholders.
+type cachedStmt struct {
+	*sql.Stmt
+	args []interface{}
+
+	cache *stmtCache
+	entry *stmtCacheEntry
+}
+
+func (s *cachedStmt) release() {
+	s.cache.release(s.entry)
+}
+
+// stmt returns a cached prepared statement for the query or nil
+// if the statement cache is disabled or the query can't be cached.
+func (q *baseQuery) stmt(ctx context.Context, queryApp schema.QueryAppender) (*cachedStmt, error) {
+	cache := q.db.stmtCache
+	if cache == nil || !isCacheableQuery(queryApp) {
+		return nil, nil
+	}
+
+	var tx *sql.Tx
+	switch conn := q.conn.(type) {
+	case *sql.DB:
+	case *sql.Tx:
+		tx = conn
+	default:
+		return nil, nil
+	}
+
+	var args []interface{}
+	queryBytes, err := queryApp.AppendQuery(q.db.fmter.WithParams(&args), q.db.makeQueryBytes())
+	if err != nil {
+		return nil, err
+	}
+	if isMultiStatement(queryBytes) {
+		return nil, nil
+	}
+	query := q.appendComment(ctx, internal.String(queryBytes))
+
+	entry := cache.get(query)
+	if entry == nil {
+		stmt, err := q.db.DB.PrepareContext(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		entry = cache.add(query, stmt)
+	}
+
+	stmt := entry.stmt
+	if tx != nil {
+		// The transaction-specific statement is closed with the transaction.
+		stmt = tx.StmtContext(ctx, stmt)
+	}
+	return &cachedStmt{Stmt: stmt, args: args, cache: cache, entry: entry}, nil
+}
+
+func isCacheableQuery(queryApp schema.QueryAppender) bool {
+	q, ok := queryApp.(Query)
+	if !ok {
+		return false
+	}
+	switch q.Operation() {
+	case "SELECT", "INSERT", "UPDATE", "DELETE":
+		return true
+	default:
+		return false
+	}
+}
+
+// isMultiStatement reports whether the query has a `;` outside of quotes
+// that is followed by another statement.
+func isMultiStatement(query []byte) bool {
+	var quote byte
+	for i, c := range query {
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == ';':
+			for _, c := range query[i+1:] {
+				switch c {
+				case ' ', '\t', '\n', '\r', ';':
+				default:
+					return true
+				}
+			}
+			return false
+		}
+	}
+	return false
+}
+
+func (q *baseQuery) queryContext(
+	ctx context.Context, queryApp schema.QueryAppender, query string,
+) (*sql.Rows, error) {
+	stmt, err := q.stmt(ctx, queryApp)
+	if err != nil {
+		return nil, err
+	}
+	if stmt == nil {
+		return q.conn.QueryContext(ctx, query)
+	}
+	// It is safe to release the stmt before the rows are read, because
+	// database/sql closes the stmt only after its rows are closed.
+	defer stmt.release()
+	return stmt.QueryContext(ctx, stmt.args...)
+}
+
+func (q *baseQuery) queryRowContext(
+	ctx context.Context, queryApp schema.QueryAppender, query string,
+) *sql.Row {
+	stmt, err := q.stmt(ctx, queryApp)
+	if err != nil || stmt == nil {
+		// Let the conn report the error, if any.
+		return q.conn.QueryRowContext(ctx, query)
+	}
+	defer stmt.release()
+	return stmt.QueryRowContext(ctx, stmt.args...)
+}
+
+func (q *baseQuery) execContext(
+	ctx context.Context, queryApp schema.QueryAppender, query string,
+) (sql.Result, error) {
+	stmt, err := q.stmt(ctx, queryApp)
+	if err != nil {
+		return nil, err
+	}
+	if stmt == nil {
+		return q.conn.ExecContext(ctx, query)
+	}
+	defer stmt.release()
+	return stmt.ExecContext(ctx, stmt.args...)
+}